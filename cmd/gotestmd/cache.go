@@ -0,0 +1,261 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/gotestmd/internal/config"
+	"github.com/networkservicemesh/gotestmd/internal/version"
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+)
+
+// cacheVersion is the version of the cache file. Caches of other versions are ignored
+const cacheVersion = 1
+
+// generationCache keeps the digests of the inputs of the generated Go files and of their content between the runs.
+// A file isn't generated again if the digest of its inputs is the same and the file still has the content it was
+// generated with
+type generationCache struct {
+	Version int                    `json:"version"`
+	Files   map[string]*cachedFile `json:"files"`
+	// location is the cache file, settings is the digest of the settings of the generation, inputs are the digests of
+	// the inputs of the suites by their dirs
+	location string
+	settings string
+	inputs   map[string]string
+}
+
+// cachedFile is the cache entry of a generated file
+type cachedFile struct {
+	// Inputs is the digest of the settings and the markdown files the file is generated from
+	Inputs string `json:"inputs"`
+	// Content is the digest of the generated content
+	Content string `json:"content"`
+}
+
+// loadCache reads the cache file of the generation. An unreadable or outdated cache is ignored, so all files are
+// generated and the cache is rewritten
+func loadCache(location string, c *config.Config, examples []*linker.LinkedExample) (*generationCache, error) {
+	settings, err := settingsDigest(c)
+	if err != nil {
+		return nil, err
+	}
+	var result = &generationCache{
+		Version:  cacheVersion,
+		Files:    map[string]*cachedFile{},
+		location: location,
+		settings: settings,
+		inputs:   inputDigests(settings, c, examples),
+	}
+	// #nosec
+	content, err := os.ReadFile(location)
+	if os.IsNotExist(err) {
+		return result, nil
+	}
+	var cached generationCache
+	if err == nil {
+		err = json.Unmarshal(content, &cached)
+	}
+	switch {
+	case err != nil:
+		logrus.Warnf("cache %v is ignored: %v", location, err)
+	case cached.Version != cacheVersion:
+		logrus.Warnf("cache %v is ignored: unknown version %v", location, cached.Version)
+	case cached.Files != nil:
+		result.Files = cached.Files
+	}
+	return result, nil
+}
+
+// fresh returns true if the file of the suite in the dir doesn't have to be generated again
+func (c *generationCache) fresh(dir, location string) bool {
+	if c == nil {
+		return false
+	}
+	file, ok := c.Files[filepath.ToSlash(location)]
+	if !ok || file.Inputs != c.inputs[dir] {
+		return false
+	}
+	// #nosec
+	content, err := os.ReadFile(location)
+	return err == nil && digest(content) == file.Content
+}
+
+// update records the content of the file generated for the suite in the dir
+func (c *generationCache) update(dir, location string, content []byte) {
+	if c == nil {
+		return
+	}
+	c.Files[filepath.ToSlash(location)] = &cachedFile{Inputs: c.inputs[dir], Content: digest(content)}
+}
+
+// save writes the cache file. Entries of the removed files are dropped
+func (c *generationCache) save() error {
+	if c == nil {
+		return nil
+	}
+	for location := range c.Files {
+		if _, err := os.Stat(filepath.FromSlash(location)); os.IsNotExist(err) {
+			delete(c.Files, location)
+		}
+	}
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(c.location, append(content, '\n'), 0o600); err != nil {
+		return withExitCode(ExitWrite, errors.Wrapf(err, "cannot save cache %v", c.location))
+	}
+	return nil
+}
+
+// settingsDigest returns the digest of the settings changing the generated files: the config, the version of
+// gotestmd with the digest of its executable, so builds from a modified checkout are told apart, and the templates
+func settingsDigest(c *config.Config) (string, error) {
+	h := sha256.New()
+	if err := json.NewEncoder(h).Encode(c); err != nil {
+		return "", err
+	}
+	if err := json.NewEncoder(h).Encode(version.Get()); err != nil {
+		return "", err
+	}
+	if executable, err := os.Executable(); err == nil {
+		if err = hashFile(h, executable); err != nil {
+			return "", err
+		}
+	}
+	if c.TemplatesDir != "" {
+		entries, err := os.ReadDir(c.TemplatesDir)
+		if err != nil {
+			return "", err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			_, _ = io.WriteString(h, entry.Name()+"\n")
+			if err = hashFile(h, filepath.Join(c.TemplatesDir, entry.Name())); err != nil {
+				return "", err
+			}
+		}
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashFile(w io.Writer, location string) error {
+	// #nosec
+	f, err := os.Open(location)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// inputDigests returns the digests of the inputs of the suites by the dirs of their examples: the settings and the
+// markdown files of the example, the examples set up before it and the examples it includes recursively. Types of
+// the suites generated into a single package are unique among all examples, so all examples are their inputs
+func inputDigests(settings string, c *config.Config, examples []*linker.LinkedExample) map[string]string {
+	var index = map[string]*linker.LinkedExample{}
+	var all = map[string]string{}
+	for _, e := range examples {
+		index[e.Name] = e
+		all[e.Name] = e.Hash
+	}
+	var result = map[string]string{}
+	if c.SinglePackage {
+		inputs := inputsDigest(settings, all)
+		for _, e := range examples {
+			result[e.Dir] = inputs
+		}
+		return result
+	}
+	for _, e := range examples {
+		var inputs = map[string]string{}
+		addDependencies(index, e, inputs)
+		addIncluded(e, inputs)
+		result[e.Dir] = inputsDigest(settings, inputs)
+	}
+	return result
+}
+
+// inputsDigest returns the digest of the settings and the hashes of the markdown files by the names of the examples
+func inputsDigest(settings string, inputs map[string]string) string {
+	var names []string
+	for name := range inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	h := sha256.New()
+	_, _ = io.WriteString(h, settings+"\n")
+	for _, name := range names {
+		_, _ = io.WriteString(h, name+"\x00"+inputs[name]+"\n")
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// addDependencies adds the example with its parents, the examples it requires and the examples of its required tests
+// recursively to the inputs
+func addDependencies(index map[string]*linker.LinkedExample, e *linker.LinkedExample, inputs map[string]string) {
+	if _, ok := inputs[e.Name]; ok {
+		return
+	}
+	inputs[e.Name] = e.Hash
+	var deps = append([]*linker.LinkedExample{}, e.Parents...)
+	for _, name := range append(append([]string{}, e.Requires...), e.OptionalRequires...) {
+		if dep, ok := index[name]; ok {
+			deps = append(deps, dep)
+		}
+	}
+	for _, dep := range append(deps, e.RequiredTests...) {
+		addDependencies(index, dep, inputs)
+	}
+}
+
+// addIncluded adds the examples included by the example recursively to the inputs
+func addIncluded(e *linker.LinkedExample, inputs map[string]string) {
+	var visited = map[*linker.LinkedExample]bool{}
+	var visit func(e *linker.LinkedExample)
+	visit = func(e *linker.LinkedExample) {
+		for _, child := range e.Children {
+			if !visited[child] {
+				visited[child] = true
+				inputs[child.Name] = child.Hash
+				visit(child)
+			}
+		}
+	}
+	visit(e)
+}
+
+func digest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestmd
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+)
+
+// changedFiles returns the files set by --changed-files and the files changed since the revision set by
+// --changed-since. The second value is false if neither is set, so all suites are generated
+func changedFiles(cmd *cobra.Command) ([]string, bool, error) {
+	files, err := cmd.Flags().GetStringArray("changed-files")
+	if err != nil {
+		return nil, false, err
+	}
+	since, err := cmd.Flags().GetString("changed-since")
+	if err != nil {
+		return nil, false, err
+	}
+	if since == "" {
+		return files, cmd.Flags().Changed("changed-files"), nil
+	}
+	changed, err := gitChangedFiles(since)
+	if err != nil {
+		return nil, false, err
+	}
+	return append(files, changed...), true, nil
+}
+
+// gitChangedFiles returns the files changed in the working tree since the revision and the untracked files relative to
+// the current dir
+func gitChangedFiles(revision string) ([]string, error) {
+	// #nosec
+	changed, err := exec.Command("git", "diff", "--name-only", "--relative", revision, "--").Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot get the files changed since %v", revision)
+	}
+	untracked, err := exec.Command("git", "ls-files", "--others", "--exclude-standard").Output()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get the untracked files")
+	}
+	var result []string
+	for _, line := range strings.Split(string(changed)+"\n"+string(untracked), "\n") {
+		if line != "" {
+			result = append(result, line)
+		}
+	}
+	return result, nil
+}
+
+// affectedExamples returns the examples containing the files in their dirs and the examples depending on them: the
+// examples they include and the examples requiring them or their tests, recursively. A file belongs to the example
+// with the closest dir
+func affectedExamples(examples []*linker.LinkedExample, files []string) []*linker.LinkedExample {
+	var affected = map[*linker.LinkedExample]bool{}
+	for _, file := range files {
+		owner := ownerExample(examples, file)
+		if owner == nil || affected[owner] {
+			continue
+		}
+		affected[owner] = true
+		for _, dependent := range linker.Dependents(examples, owner) {
+			affected[dependent] = true
+		}
+	}
+	var result []*linker.LinkedExample
+	for _, e := range examples {
+		if affected[e] {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// ownerExample returns the local example with the closest dir containing the file or nil if there is no such example
+func ownerExample(examples []*linker.LinkedExample, file string) *linker.LinkedExample {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil
+	}
+	var result *linker.LinkedExample
+	var resultDir string
+	for _, e := range examples {
+		if e.Remote != nil || linker.IsURL(e.Source) {
+			continue
+		}
+		dir, err := filepath.Abs(e.Dir)
+		if err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(dir, abs); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if result == nil || len(dir) > len(resultDir) {
+			result, resultDir = e, dir
+		}
+	}
+	return result
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/gotestmd/internal/generator"
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+)
+
+func newCleanCommand() *cobra.Command {
+	var dryRun bool
+	cleanCmd := &cobra.Command{
+		Use:   "clean OUTPUT_DIR...",
+		Short: "Removes the generated files whose Source: headers point to markdown files that don't exist anymore",
+		Args:  cobra.MinimumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cleanOutput(cmd.OutOrStdout(), args, dryRun)
+		},
+	}
+
+	cleanCmd.Flags().BoolVar(&dryRun, "dry-run", false, "prints the files to remove without removing them")
+
+	return cleanCmd
+}
+
+// cleanOutput removes the files of the output dirs generated from deleted markdown files and the dirs left empty.
+// A file is generated from its first Source: header, the other ones are its tests. URL sources aren't checked. Paths
+// of the sources are relative to the module root of the output dir, the dirs outside of modules are refused
+func cleanOutput(out io.Writer, outputDirs []string, dryRun bool) error {
+	action := "removed"
+	if dryRun {
+		action = "would remove"
+	}
+	for _, outputDir := range outputDirs {
+		root := generator.ModuleRoot(outputDir)
+		if root == "" {
+			return errors.Errorf("cannot find go.mod of %v: the sources of generated files are relative to the module root", outputDir)
+		}
+		stale, err := findStaleFiles(outputDir, root)
+		if err != nil {
+			return err
+		}
+		var dirs = map[string]bool{}
+		for _, location := range stale {
+			_, _ = fmt.Fprintf(out, "%v %v: source %v doesn't exist\n", action, location.path, location.source)
+			if dryRun {
+				continue
+			}
+			if err := os.Remove(location.path); err != nil {
+				return withExitCode(ExitWrite, errors.Wrapf(err, "cannot remove %v", location.path))
+			}
+			dirs[filepath.Dir(location.path)] = true
+		}
+		removeEmptyDirs(outputDir, dirs)
+	}
+	return nil
+}
+
+// generatedMarker marks the files generated by gotestmd. Other files aren't removed even if they have Source: headers
+const generatedMarker = "Code generated by gotestmd"
+
+type staleFile struct {
+	path   string
+	source string
+}
+
+// findStaleFiles returns the files generated by gotestmd in the dir with the first Source: header pointing to a file
+// that doesn't exist in the root
+func findStaleFiles(dir, root string) ([]staleFile, error) {
+	var result []staleFile
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		// #nosec
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "cannot read %v", path)
+		}
+		if !bytes.Contains(content, []byte(generatedMarker)) {
+			return nil
+		}
+		match := sourceRegex.FindSubmatch(content)
+		if match == nil || linker.IsURL(string(match[1])) {
+			return nil
+		}
+		source := string(match[1])
+		location := filepath.FromSlash(source)
+		if !filepath.IsAbs(location) {
+			location = filepath.Join(root, location)
+		}
+		if _, err := os.Stat(location); os.IsNotExist(err) {
+			result = append(result, staleFile{path: path, source: source})
+		}
+		return nil
+	})
+	return result, err
+}
+
+// removeEmptyDirs removes the dirs left empty and their empty parents up to the output dir
+func removeEmptyDirs(outputDir string, dirs map[string]bool) {
+	var sorted []string
+	for dir := range dirs {
+		sorted = append(sorted, dir)
+	}
+	// Nested dirs go first, so their parents can become empty
+	sort.Sort(sort.Reverse(sort.StringSlice(sorted)))
+	for _, dir := range sorted {
+		for ; isSubdir(outputDir, dir) && filepath.Clean(dir) != filepath.Clean(outputDir); dir = filepath.Dir(dir) {
+			if os.Remove(dir) != nil {
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestmd
+
+// Exit codes of gotestmd by the class of the failure
+const (
+	// ExitError is returned for invalid arguments and flags and for failures without a class
+	ExitError = 1
+	// ExitParse is returned if markdown files can't be parsed
+	ExitParse = 2
+	// ExitLink is returned if examples can't be linked, e.g. for broken Includes and Requires links or orphaned examples
+	ExitLink = 3
+	// ExitWrite is returned if generated files can't be written or removed
+	ExitWrite = 4
+	// ExitNoMatch is returned if --match or the run target selects nothing or no suites are affected by the changed files
+	ExitNoMatch = 5
+	// ExitDrift is returned by verify if generated files are out of date
+	ExitDrift = 6
+	// ExitLint is returned by lint if it finds problems with the --fail-on severity
+	ExitLint = 7
+	// ExitRun is returned by run if a suite fails
+	ExitRun = 8
+)
+
+// exitError is an error with the exit code of its class
+type exitError struct {
+	error
+	code int
+}
+
+// withExitCode sets the exit code of the error. The error keeps its message
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{error: err, code: code}
+}
+
+// ExitCode returns the exit code of the error returned by the command: the code of its class or ExitError
+func ExitCode(err error) int {
+	for err != nil {
+		if e, ok := err.(*exitError); ok {
+			return e.code
+		}
+		cause, ok := err.(interface{ Cause() error })
+		if !ok {
+			return ExitError
+		}
+		err = cause.Cause()
+	}
+	return ExitError
+}
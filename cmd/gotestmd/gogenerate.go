@@ -0,0 +1,84 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/gotestmd/internal/generator"
+)
+
+func newGoGenerateCommand() *cobra.Command {
+	goGenerateCmd := &cobra.Command{
+		Use:   "go-generate INPUT_DIR OUTPUT_DIR [BASE_PKG]",
+		Short: "Regenerates the Go suite of the current dir with the suites it includes and requires, for //go:generate directives",
+		Long: `Regenerates the Go suite of the current dir with the suites it includes and requires.
+It's run by a //go:generate directive placed in the package of a generated suite or in the dir of an example, e.g.
+
+	//go:generate gotestmd go-generate ../../../examples ..
+
+go generate runs the command in the dir of the file with the directive, so the paths are relative to it.
+Files with the same content are left untouched, so repeated runs don't change anything.`,
+		Args: cobra.ArbitraryArgs,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := os.Getwd()
+			if err != nil {
+				return errors.Wrap(err, "cannot get the current dir")
+			}
+			gen, err := prepare(cmd, args)
+			if err != nil {
+				return err
+			}
+			if gen.suites == nil || gen.config.Bash {
+				return errors.New("go-generate generates only Go suites")
+			}
+			suites, err := suitesOf(gen.suites, dir)
+			if err != nil {
+				return err
+			}
+			return processGoSuites(diskWriter{}, suites, gen.config.Format, nil)
+		},
+	}
+
+	addGenerateFlags(goGenerateCmd.Flags())
+
+	return goGenerateCmd
+}
+
+// suitesOf returns the suites generated into the dir or from the example in the dir with the suites they include and
+// require
+func suitesOf(suites []*generator.Suite, dir string) ([]*generator.Suite, error) {
+	result := generator.Closure(suites, func(suite *generator.Suite) bool {
+		return samePath(suite.Dir, dir) || samePath(filepath.Dir(suite.Location), dir)
+	})
+	if len(result) == 0 {
+		return nil, withExitCode(ExitNoMatch, errors.Errorf("no suite is generated into or from %v", dir))
+	}
+	return result, nil
+}
+
+// samePath returns true if the paths point to the same location
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
@@ -18,19 +18,29 @@
 package gotestmd
 
 import (
+	"bytes"
+	"io"
 	"os"
-	"path"
+	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/networkservicemesh/gotestmd/internal/config"
+	"github.com/networkservicemesh/gotestmd/internal/export"
 	"github.com/networkservicemesh/gotestmd/internal/generator"
-	"github.com/networkservicemesh/gotestmd/internal/linker"
-	"github.com/networkservicemesh/gotestmd/internal/parser"
+	"github.com/networkservicemesh/gotestmd/internal/version"
+	"github.com/networkservicemesh/gotestmd/pkg/backend"
+	pkggenerator "github.com/networkservicemesh/gotestmd/pkg/generator"
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+	"github.com/networkservicemesh/gotestmd/pkg/rewrite"
 )
 
 // New creates new cmd/gotestmd
@@ -38,75 +48,805 @@ func New() *cobra.Command {
 	gotestmdCmd := &cobra.Command{
 		Use:     "gotestmd",
 		Short:   "Command for generating integration tests",
-		Version: "0.0.1",
+		Version: version.Get().String(),
+		Args:    cobra.ArbitraryArgs,
+		// The usage is printed by withUsage only for invalid arguments and flags
+		SilenceUsage: true,
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			match := cmd.Flag("match").Value.String()
-			bash := false
-			if value, err := cmd.Flags().GetBool("bash"); err == nil {
-				bash = value
+			dryRun, err := cmd.Flags().GetBool("dry-run")
+			if err != nil {
+				return err
 			}
-
-			if bash && match == "" {
-				return errors.New("Flag --bash can be used only with flag --match")
+			showDiff, err := cmd.Flags().GetBool("diff")
+			if err != nil {
+				return err
 			}
-
-			c := config.FromArgs(args)
-			c.Bash = bash
-			c.Match = match
-			_ = os.MkdirAll(c.OutputDir, os.ModePerm)
-			var examples []*parser.Example
-
-			var p = parser.New()
-			var l = linker.New(c.InputDir)
-			var g = generator.New(c)
-			dirs := getRecursiveDirectories(c.InputDir)
-			for _, dir := range dirs {
-				ex, err := p.ParseFile(path.Join(dir, "README.md"))
-				if err == nil {
-					examples = append(examples, ex)
-				}
+			if !dryRun && !showDiff {
+				return generate(cmd, args, diskWriter{})
 			}
-			linkedExamples, err := l.Link(examples...)
-			if err != nil {
-				return errors.Errorf("cannot build examples: %v", err.Error())
+			files := memoryWriter{}
+			if err = generate(cmd, args, files); err != nil {
+				return err
 			}
+			return preview(cmd.OutOrStdout(), files, showDiff)
+		},
+	}
 
-			suites := g.Generate(linkedExamples...)
+	gotestmdCmd.AddCommand(newGraphCommand())
+	gotestmdCmd.AddCommand(newTemplatesCommand())
+	gotestmdCmd.AddCommand(newListCommand())
+	gotestmdCmd.AddCommand(newTreeCommand())
+	gotestmdCmd.AddCommand(newVerifyCommand())
+	gotestmdCmd.AddCommand(newLintCommand())
+	gotestmdCmd.AddCommand(newRunCommand())
+	gotestmdCmd.AddCommand(newCleanCommand())
+	gotestmdCmd.AddCommand(newInitCommand())
+	gotestmdCmd.AddCommand(newStatsCommand())
+	gotestmdCmd.AddCommand(newVersionCommand())
+	gotestmdCmd.AddCommand(newRenderCommand())
+	gotestmdCmd.AddCommand(newGoGenerateCommand())
+	gotestmdCmd.AddCommand(newLSPCommand())
+	gotestmdCmd.AddCommand(newHookCommand())
+	gotestmdCmd.AddCommand(newServeCommand())
+	withUsage(gotestmdCmd)
 
-			if !bash {
-				return processGoSuites(suites)
-			}
+	addGenerateFlags(gotestmdCmd.Flags())
+	gotestmdCmd.Flags().Bool("clean", false, "removes files generated from markdown files that don't exist anymore from the output dirs, see gotestmd clean")
+	gotestmdCmd.Flags().Bool("dry-run", false, "prints the files that would be created, updated or left unchanged without writing them")
+	gotestmdCmd.Flags().Bool("diff", false, "prints unified diffs of the files that would be created or updated without writing them")
+	gotestmdCmd.Flags().String("cache", "", "keeps the digests of the inputs of the generated Go files in the file, e.g. .gotestmd-cache.json, and skips the files whose markdown files, dependencies and settings haven't changed since the last run")
+	gotestmdCmd.Flags().String("report", "", "writes a JSON report with the written, unchanged and failed files, the skipped examples, timings and the error of the generation to the file, e.g. summary.json")
 
-			matchRegex, err := regexp.Compile(match)
-			if err != nil {
+	return gotestmdCmd
+}
+
+// addGenerateFlags adds the flags of the generation shared by gotestmd and gotestmd verify
+func addGenerateFlags(flags *pflag.FlagSet) {
+	flags.String("config", "", "config file with the arguments and the flags, see "+config.FileName+". By default "+config.FileName+" of INPUT_DIR or of the current dir if there are no arguments is used")
+	flags.Bool("bash", false, "generates bash scripts for tests. Can be used only with --match flag")
+	flags.String("format", config.FormatTestify, "format of generated Go suites: testify, testing (standard library tests without testify) or ginkgo. json writes the linked examples to OUTPUT_DIR/examples.json instead. Formats registered with the backend package are also accepted")
+	flags.String("github-workflow", "", "generates a GitHub Actions workflow file with a job per suite, e.g. .github/workflows/examples.yaml")
+	flags.String("docker-compose", "", "generates docker-compose.yml running the bash suites in the IMAGE with the current dir mounted, e.g. ghcr.io/org/tools@sha256:...")
+	flags.Bool("makefile", false, "generates OUTPUT_DIR/gotestmd.mk with make targets running the suites and their tests")
+	flags.Bool("powershell", false, "generates suite.ps1 PowerShell scripts for suites and tests matching --match instead of bash scripts")
+	flags.Bool("validate", false, "runs shellcheck against generated bash scripts and fails on its findings")
+	flags.Int("bash-jobs", 1, "default number of tests of a suite run in parallel by suite.sh. Can be overridden by its -j flag")
+	flags.String("match", "", "regex for matching suite or test name. Go suites are generated for the matched suites with the suites they include and require. Required by --bash")
+	flags.StringArray("changed-files", nil, "changed file, e.g. from git diff --name-only. Only the suites of the examples containing the files in their dirs and of the examples depending on them are generated. Can be repeated")
+	flags.String("changed-since", "", "git revision, e.g. origin/main. The files changed since it and the untracked files are added to --changed-files")
+	flags.StringArray("root", nil, "additional input dir linked together with INPUT_DIR in INPUT[=OUTPUT] format. By default suites are generated into OUTPUT_DIR/<base name of INPUT>")
+	flags.Bool("fail-on-orphans", false, "fails if there are nested examples that don't end up in any generated suite: they have no steps and nothing running them includes or requires them. Can't be used with --include and --exclude")
+	flags.StringArray("prune", nil, "glob of example dirs to remove with their subtrees and all dependent examples, e.g. 'examples/experimental/**'")
+	flags.StringArray("run-tags", nil, "keeps only the examples having any of the front matter tags with the examples they include and require, e.g. smoke. Can be repeated")
+	flags.StringArray("skip-tags", nil, "removes the examples having any of the front matter tags like --prune, e.g. slow. Can be repeated")
+	flags.StringArray("include", nil, "glob of example dirs to parse, e.g. 'examples/basic/**'. All dirs are parsed by default. Can be repeated")
+	flags.StringArray("exclude", nil, "glob of example dirs to skip with their subtrees, e.g. 'examples/heal/**'. Can be repeated")
+	flags.String("excluded-deps", config.ExcludedDepsError, "handling of dependencies of selected examples on the ones skipped by --include and --exclude: error or include")
+	flags.Bool("skip-optional", false, "ignores optional dependencies even if they exist in the input dir")
+	flags.Bool("parallel", false, "marks all examples as safe to run in parallel with their siblings")
+	flags.String("templates", "", "dir of NAME.tmpl files overriding the built-in templates, e.g. suite.tmpl. See 'gotestmd templates'")
+	flags.StringToString("template-data", nil, "KEY=VALUE available in the templates as {{ data \"KEY\" }}. Can be repeated")
+	flags.Bool("steps", false, "runs each bash block of Run sections of tests as a step-N-description subtest. Supported by testify suites")
+	flags.Bool("log-steps", false, "logs the markdown file and line of each step before running it")
+	flags.String("skip-unless-env", "", "env variable that should be set to run generated suites, e.g. E2E. Suites are skipped if it's empty")
+	flags.Duration("suite-timeout", 0, "timeout of each generated testify suite with its tests and included suites, e.g. 30m. Commands still running when it expires are killed")
+	flags.Duration("command-timeout", 0, "timeout of each command of generated testify suites, e.g. 2m. A command still running when it expires is killed and fails the test. Overridden by command-timeout of the front matter")
+	flags.String("entrypoints", config.EntrypointsNone, "suites that get a generated suite.gen_test.go running them: none, roots (suites not included by other suites), leaves (suites without included suites) or all")
+	flags.String("name-case", config.NamingTitle, "case style of generated test and suite names: title (leaf-A becomes Leaf_a), camel (LeafA) or preserve (Leaf_A)")
+	flags.String("name-separator", "_", "replaces characters that can't be used in Go identifiers in title and preserve cases")
+	flags.String("name-prefix", "", "prefix of generated test and suite names")
+	flags.String("name-suffix", "", "suffix of generated test and suite names")
+	flags.String("name-digit-prefix", "_", "prefix of --single-package suite types starting with a digit")
+	flags.Bool("single-package", false, "generates all testify suites into the package of OUTPUT_DIR with unique type names instead of a package per example dir")
+	flags.StringArray("build-tag", nil, "go:build constraint added to all generated Go suites, e.g. integration. Can be repeated")
+	flags.String("base-suite", "", "suite type embedded by generated suites in IMPORT_PATH.TYPE format, e.g. github.com/org/repo/suites/base.Suite. Overrides BASE_PKG")
+	flags.String("module", "", "module path used instead of the one from the nearest go.mod of OUTPUT_DIR")
+	flags.String("import-prefix", "", "import path of OUTPUT_DIR, e.g. github.com/org/repo/tests/suites. By default it's detected from the nearest go.mod")
+	flags.String("url-cache", linker.DefaultURLCacheDir(), "directory for caching markdown files required by URL")
+	flags.Bool("offline", false, "uses only cached markdown files for Requires links with URLs")
+	flags.StringArray("rewrite", nil, "s/REGEX/REPLACEMENT/ rule rewriting each line of the bash blocks, e.g. 's|^kubectl |kubectl --kubeconfig $$KUBECONFIG2 |'. $ of env variables is written as $$. Can be repeated, the rules are applied in order")
+	flags.StringArray("artifact", nil, "NAME=COMMAND whose output is collected into NAME.log of the artifacts of failed tests of generated testify suites, e.g. 'nodes=kubectl get nodes -o wide'. Can be repeated. Overridden by artifacts of the front matter")
+	flags.Bool("k8s-diagnostics", false, "collects the pods, their descriptions and the events of the cluster into the artifacts of failed tests of generated testify suites")
+	flags.StringToString("remote-prefix", nil, "import path prefix of generated suites for a remote module, e.g. github.com/org/examples=github.com/org/tests/suites")
+}
+
+// parseArtifacts returns the artifacts of --k8s-diagnostics and --artifact flags
+func parseArtifacts(cmd *cobra.Command) (map[string]string, error) {
+	result := map[string]string{}
+	diagnostics, err := cmd.Flags().GetBool("k8s-diagnostics")
+	if err != nil {
+		return nil, err
+	}
+	if diagnostics {
+		for name, command := range config.KubernetesDiagnostics {
+			result[name] = command
+		}
+	}
+	artifacts, err := cmd.Flags().GetStringArray("artifact")
+	if err != nil {
+		return nil, err
+	}
+	for _, artifact := range artifacts {
+		name, command, err := config.ParseArtifact(artifact)
+		if err != nil {
+			return nil, err
+		}
+		result[name] = command
+	}
+	return result, nil
+}
+
+// generation contains the linked examples and the suites of the arguments and the flags of the command
+type generation struct {
+	config    config.Config
+	generator *generator.Generator
+	examples  []*linker.LinkedExample
+	// pruned are the examples removed by --prune, --run-tags and --skip-tags
+	pruned []*linker.Pruned
+	// suites are nil if the examples are exported as JSON
+	suites []*generator.Suite
+	// affected are the examples affected by --changed-files and --changed-since. All examples are affected if it's nil
+	affected []*linker.LinkedExample
+	// baseOptions load the examples without --prune, --include and --exclude
+	baseOptions []pkggenerator.Option
+	powershell  bool
+	validate    bool
+	image       string
+}
+
+// prepare parses the arguments and the flags of the command, loads the examples and generates the suites in memory
+func prepare(cmd *cobra.Command, args []string) (*generation, error) {
+	args, err := applyConfigFile(cmd, args)
+	if err != nil {
+		return nil, err
+	}
+	var result = &generation{config: config.FromArgs(args)}
+	if err = result.parseScriptFlags(cmd); err != nil {
+		return nil, err
+	}
+	if err = configFromFlags(cmd, &result.config); err != nil {
+		return nil, err
+	}
+	options, err := generatorOptions(&result.config)
+	if err != nil {
+		return nil, err
+	}
+	result.generator = generator.New(result.config, options...)
+	if err = result.link(cmd); err != nil {
+		return nil, err
+	}
+	if result.config.Format == config.FormatJSON || backend.Get(result.config.Format) != nil {
+		return result, nil
+	}
+
+	if result.suites, err = result.generator.Generate(result.examples...); err != nil {
+		return nil, err
+	}
+	if err = generator.CheckNames(result.suites); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// parseScriptFlags parses --match and the flags of the generated bash and PowerShell scripts
+func (gen *generation) parseScriptFlags(cmd *cobra.Command) error {
+	c := &gen.config
+	c.Match = cmd.Flag("match").Value.String()
+	bash := false
+	if value, err := cmd.Flags().GetBool("bash"); err == nil {
+		bash = value
+	}
+
+	var err error
+	if gen.powershell, err = cmd.Flags().GetBool("powershell"); err != nil {
+		return err
+	}
+	if gen.powershell && (bash || c.Match == "") {
+		return errors.New("Flag --powershell can be used only with flag --match and without --bash")
+	}
+
+	if bash && c.Match == "" {
+		return errors.New("Flag --bash can be used only with flag --match")
+	}
+
+	c.Bash = bash || gen.powershell
+	if c.BashJobs, err = cmd.Flags().GetInt("bash-jobs"); err != nil {
+		return err
+	}
+	if c.BashJobs < 1 {
+		return errors.Errorf("invalid --bash-jobs %v, expected a positive number", c.BashJobs)
+	}
+	return gen.parseBashOutputFlags(cmd, bash)
+}
+
+// parseBashOutputFlags parses the flags of the checks and the files generated together with the bash scripts
+func (gen *generation) parseBashOutputFlags(cmd *cobra.Command, bash bool) error {
+	var err error
+	if gen.validate, err = cmd.Flags().GetBool("validate"); err != nil {
+		return err
+	}
+	if gen.validate && !bash {
+		return errors.New("Flag --validate can be used only with flag --bash")
+	}
+	if gen.image, err = cmd.Flags().GetString("docker-compose"); err != nil {
+		return err
+	}
+	if gen.image != "" && !bash {
+		return errors.New("Flag --docker-compose can be used only with flag --bash")
+	}
+	if gen.image != "" && !strings.Contains(gen.image, "@sha256:") {
+		logrus.Warnf("image %v isn't pinned by a digest, containers may run different versions of the tools", gen.image)
+	}
+	return nil
+}
+
+// configFromFlags sets the fields of the config from the flags of the command
+func configFromFlags(cmd *cobra.Command, c *config.Config) error {
+	for _, parse := range []func(cmd *cobra.Command, c *config.Config) error{
+		parseSelectionFlags,
+		parseFormatFlags,
+		parsePackageFlags,
+		parseSuiteFlags,
+		parseRunFlags,
+	} {
+		if err := parse(cmd, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseSelectionFlags parses the flags selecting the examples that are loaded and linked
+func parseSelectionFlags(cmd *cobra.Command, c *config.Config) error {
+	var err error
+	if c.SkipOptional, err = cmd.Flags().GetBool("skip-optional"); err != nil {
+		return err
+	}
+	if c.Roots, err = parseRoots(cmd, c.OutputDir); err != nil {
+		return err
+	}
+	if c.FailOnOrphans, err = cmd.Flags().GetBool("fail-on-orphans"); err != nil {
+		return err
+	}
+	if c.Prune, err = cmd.Flags().GetStringArray("prune"); err != nil {
+		return err
+	}
+	if c.RunTags, err = cmd.Flags().GetStringArray("run-tags"); err != nil {
+		return err
+	}
+	if c.SkipTags, err = cmd.Flags().GetStringArray("skip-tags"); err != nil {
+		return err
+	}
+	if c.Include, err = cmd.Flags().GetStringArray("include"); err != nil {
+		return err
+	}
+	if c.Exclude, err = cmd.Flags().GetStringArray("exclude"); err != nil {
+		return err
+	}
+	if c.FailOnOrphans && len(c.Include)+len(c.Exclude) > 0 {
+		return usageError(cmd, errors.New("--fail-on-orphans can't be used with --include and --exclude: the parents of the selected examples can be excluded"))
+	}
+	if c.ExcludedDeps, err = cmd.Flags().GetString("excluded-deps"); err != nil {
+		return err
+	}
+	switch c.ExcludedDeps {
+	case config.ExcludedDepsError, config.ExcludedDepsInclude:
+	default:
+		return errors.Errorf("unknown excluded-deps %v, expected %v or %v", c.ExcludedDeps, config.ExcludedDepsError, config.ExcludedDepsInclude)
+	}
+	if c.RemotePrefixes, err = cmd.Flags().GetStringToString("remote-prefix"); err != nil {
+		return err
+	}
+	if c.URLCacheDir, err = cmd.Flags().GetString("url-cache"); err != nil {
+		return err
+	}
+	if c.Offline, err = cmd.Flags().GetBool("offline"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// parseFormatFlags parses the format of the generated suites and the suites getting entrypoints
+func parseFormatFlags(cmd *cobra.Command, c *config.Config) error {
+	var err error
+	if c.Format, err = cmd.Flags().GetString("format"); err != nil {
+		return err
+	}
+	switch c.Format {
+	case config.FormatTestify, config.FormatTesting, config.FormatGinkgo, config.FormatJSON:
+	default:
+		if backend.Get(c.Format) == nil {
+			formats := append([]string{config.FormatTestify, config.FormatTesting, config.FormatGinkgo, config.FormatJSON}, backend.Names()...)
+			return errors.Errorf("unknown format %v, expected one of: %v", c.Format, strings.Join(formats, ", "))
+		}
+	}
+	if (c.Format == config.FormatJSON || backend.Get(c.Format) != nil) && c.Bash {
+		return errors.Errorf("--format=%v can't be used with --bash and --powershell", c.Format)
+	}
+	if c.Entrypoints, err = cmd.Flags().GetString("entrypoints"); err != nil {
+		return err
+	}
+	switch c.Entrypoints {
+	case config.EntrypointsNone, config.EntrypointsRoots, config.EntrypointsLeaves, config.EntrypointsAll:
+	default:
+		return errors.Errorf("unknown entrypoints %v, expected one of: %v, %v, %v, %v", c.Entrypoints, config.EntrypointsNone, config.EntrypointsRoots, config.EntrypointsLeaves, config.EntrypointsAll)
+	}
+	return nil
+}
+
+// parsePackageFlags parses the flags of the packages and the imports of the generated suites
+func parsePackageFlags(cmd *cobra.Command, c *config.Config) error {
+	var err error
+	if baseSuite, _ := cmd.Flags().GetString("base-suite"); baseSuite != "" {
+		if c.BasePkg, c.BaseType, err = config.ParseBaseSuite(baseSuite); err != nil {
+			return err
+		}
+		c.PlainBase = false
+	}
+	bash, _ := cmd.Flags().GetBool("bash")
+	if c.SinglePackage, err = cmd.Flags().GetBool("single-package"); err != nil {
+		return err
+	}
+	if c.SinglePackage && (bash || c.Format != config.FormatTestify || len(c.Roots) > 0) {
+		return errors.Errorf("--single-package is supported only by the %v format without --bash and --root", config.FormatTestify)
+	}
+	if c.BuildTags, err = cmd.Flags().GetStringArray("build-tag"); err != nil {
+		return err
+	}
+	for _, tag := range c.BuildTags {
+		if _, err = config.ParseBuildTag(tag); err != nil {
+			return err
+		}
+	}
+	if c.Module, err = cmd.Flags().GetString("module"); err != nil {
+		return err
+	}
+	if c.ImportPrefix, err = cmd.Flags().GetString("import-prefix"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// parseSuiteFlags parses the flags of the content of the generated suites
+func parseSuiteFlags(cmd *cobra.Command, c *config.Config) error {
+	var err error
+	if c.Parallel, err = cmd.Flags().GetBool("parallel"); err != nil {
+		return err
+	}
+	if c.TemplatesDir, err = cmd.Flags().GetString("templates"); err != nil {
+		return err
+	}
+	if c.TemplateData, err = cmd.Flags().GetStringToString("template-data"); err != nil {
+		return err
+	}
+	if c.SkipUnlessEnv, err = cmd.Flags().GetString("skip-unless-env"); err != nil {
+		return err
+	}
+	if c.SkipUnlessEnv != "" && !config.IsEnvName(c.SkipUnlessEnv) {
+		return errors.Errorf("invalid env variable %v", c.SkipUnlessEnv)
+	}
+	if c.Naming, err = parseNaming(cmd); err != nil {
+		return err
+	}
+	if c.Rewrite, err = cmd.Flags().GetStringArray("rewrite"); err != nil {
+		return err
+	}
+	_, err = rewrite.ParseRules(c.Rewrite...)
+	return err
+}
+
+// parseRunFlags parses the flags of running the generated suites. They are supported only by the testify format
+func parseRunFlags(cmd *cobra.Command, c *config.Config) error {
+	var err error
+	if c.Steps, err = cmd.Flags().GetBool("steps"); err != nil {
+		return err
+	}
+	if c.Steps && c.Format != config.FormatTestify {
+		logrus.Warnf("--steps is supported only by the %v format", config.FormatTestify)
+	}
+	if c.LogSteps, err = cmd.Flags().GetBool("log-steps"); err != nil {
+		return err
+	}
+	if c.Timeout, err = cmd.Flags().GetDuration("suite-timeout"); err != nil {
+		return err
+	}
+	if c.Timeout != 0 && c.Format != config.FormatTestify {
+		logrus.Warnf("--suite-timeout is supported only by the %v format", config.FormatTestify)
+	}
+	if c.CommandTimeout, err = cmd.Flags().GetDuration("command-timeout"); err != nil {
+		return err
+	}
+	if c.CommandTimeout != 0 && c.Format != config.FormatTestify {
+		logrus.Warnf("--command-timeout is supported only by the %v format, it's ignored", config.FormatTestify)
+		// the command timeouts left in the suites are set by the front matter, which fails the generation
+		c.CommandTimeout = 0
+	}
+	if c.Artifacts, err = parseArtifacts(cmd); err != nil {
+		return err
+	}
+	if len(c.Artifacts) > 0 && c.Format != config.FormatTestify {
+		logrus.Warnf("--artifact and --k8s-diagnostics are supported only by the %v format", config.FormatTestify)
+	}
+	return nil
+}
+
+// generatorOptions returns the options of the generator of the config
+func generatorOptions(c *config.Config) ([]generator.Option, error) {
+	if c.TemplatesDir == "" {
+		return nil, nil
+	}
+	templates, err := generator.LoadTemplates(c.TemplatesDir)
+	if err != nil {
+		return nil, err
+	}
+	return []generator.Option{generator.WithTemplates(templates)}, nil
+}
+
+// link loads and links the examples of the config, rewrites their commands and finds the ones affected by the changed
+// files
+func (gen *generation) link(cmd *cobra.Command) error {
+	c := &gen.config
+	options, base := loadOptions(c)
+	gen.baseOptions = base
+	var err error
+	if gen.examples, gen.pruned, err = loadExamples(c.InputDir, options...); err != nil {
+		return err
+	}
+	rewriters, err := rewrite.ParseRules(c.Rewrite...)
+	if err != nil {
+		return err
+	}
+	rewrite.Examples(gen.examples, rewriters...)
+	changed, ok, err := changedFiles(cmd)
+	if err != nil {
+		return err
+	}
+	if ok {
+		// affected isn't nil if no examples are affected
+		gen.affected = append([]*linker.LinkedExample{}, affectedExamples(gen.examples, changed)...)
+	}
+	return nil
+}
+
+// generate generates the suites of the arguments and the flags of the command and saves them with the writer. Writes
+// the report of the generation if --report is set
+func generate(cmd *cobra.Command, args []string, w writer) error {
+	start := time.Now()
+	gen, err := prepare(cmd, args)
+	// The flag can be set by the config file applied by prepare
+	location, _ := cmd.Flags().GetString("report")
+	if location == "" {
+		if err != nil {
+			return err
+		}
+		return output(cmd, gen, w)
+	}
+	r := &reportWriter{writer: w}
+	prepared := time.Now()
+	if err == nil {
+		err = output(cmd, gen, r)
+	}
+	return saveReport(location, newReport(gen, r, err, prepared.Sub(start), time.Since(prepared)), err)
+}
+
+// output saves the generated files with the writer. Removes files generated from deleted markdown files from the
+// output dirs if --clean is set and the writer writes to the disk, otherwise they are only printed
+func output(cmd *cobra.Command, gen *generation, w writer) error {
+	if err := writeGeneration(cmd, gen, w); err != nil {
+		return err
+	}
+	if clean, _ := cmd.Flags().GetBool("clean"); clean {
+		outputDirs := []string{gen.config.OutputDir}
+		for _, root := range gen.config.Roots {
+			outputDirs = append(outputDirs, root.OutputDir)
+		}
+		return cleanOutput(cmd.OutOrStdout(), outputDirs, !writesToDisk(w))
+	}
+	return nil
+}
+
+// writeGeneration saves the generated files of the suites with the writer
+func writeGeneration(cmd *cobra.Command, gen *generation, w writer) error {
+	if gen.validate && !writesToDisk(w) {
+		return errors.New("Flag --validate can't be used with verify, --dry-run and --diff")
+	}
+	c, g := gen.config, gen.generator
+	if c.Format == config.FormatJSON {
+		return writeJSON(w, c.OutputDir, gen.examples)
+	}
+	if b := backend.Get(c.Format); b != nil {
+		return writeBackend(w, b, c.OutputDir, gen.examples)
+	}
+	suites, err := gen.matchedSuites()
+	if err != nil {
+		return err
+	}
+
+	makefile, err := cmd.Flags().GetBool("makefile")
+	if err != nil {
+		return err
+	}
+
+	workflow, err := cmd.Flags().GetString("github-workflow")
+	if err != nil {
+		return err
+	}
+
+	if !c.Bash {
+		var cache *generationCache
+		if cache, err = openCache(cmd, gen, w); err != nil {
+			return err
+		}
+		if err = processGoSuites(w, suites, c.Format, cache); err != nil {
+			return err
+		}
+		if err = cache.save(); err != nil {
+			return err
+		}
+		if (makefile || workflow != "") && c.Entrypoints == config.EntrypointsNone {
+			logrus.Warn("--makefile and --github-workflow run only suites with entrypoints, see --entrypoints")
+		}
+		if makefile {
+			if err = writeMakefile(w, g, c.OutputDir, suites); err != nil {
 				return err
 			}
+		}
+		return writeGitHubWorkflow(w, g, workflow, suites)
+	}
 
-			return processBashSuites(suites, matchRegex)
-		},
+	if gen.powershell {
+		return processPowerShellSuites(w, suites)
+	}
+	scripts, err := processBashSuites(w, g, c.OutputDir, suites)
+	if err != nil {
+		return err
+	}
+	if makefile {
+		if err = writeMakefile(w, g, c.OutputDir, suites); err != nil {
+			return err
+		}
+	}
+	if err = writeGitHubWorkflow(w, g, workflow, suites); err != nil {
+		return err
 	}
+	if gen.image != "" {
+		locations, err := writeDockerCompose(w, g, c.OutputDir, gen.image, suites)
+		if err != nil {
+			return err
+		}
+		scripts = append(scripts, locations...)
+	}
+	if !gen.validate {
+		return nil
+	}
+	return validateBashScripts(scripts)
+}
 
-	gotestmdCmd.Flags().Bool("bash", false, "generates bash scripts for tests. Can be used only with --match flag")
-	gotestmdCmd.Flags().String("match", "", "regex for matching suite or test name. Can be used only with --bash flag")
+// applyConfigFile sets the flags not passed in the command line from the config file and returns the arguments completed by it
+func applyConfigFile(cmd *cobra.Command, args []string) ([]string, error) {
+	location, err := cmd.Flags().GetString("config")
+	if err != nil {
+		return nil, err
+	}
+	if location == "" {
+		location = config.FileName
+		if len(args) > 0 {
+			location = filepath.Join(args[0], config.FileName)
+		}
+		if _, err = os.Stat(location); os.IsNotExist(err) {
+			return completeArgs(cmd, args, location)
+		}
+	}
+	file, err := config.LoadFile(location)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for name := range file.Flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || name == "config" {
+			return nil, errors.Errorf("unknown option %v in config file %v", name, location)
+		}
+		if flag.Changed {
+			continue
+		}
+		values := file.Flags[name]
+		if len(values) > 1 && !isListFlag(flag.Value.Type()) {
+			return nil, errors.Errorf("option %v in config file %v expects a single value", name, location)
+		}
+		for _, value := range values {
+			if err = cmd.Flags().Set(name, value); err != nil {
+				return nil, errors.Wrapf(err, "invalid option %v in config file %v", name, location)
+			}
+		}
+	}
+	var result = file.Args()
+	for i, arg := range args {
+		if i < len(result) {
+			result[i] = arg
+			continue
+		}
+		result = append(result, arg)
+	}
+	return completeArgs(cmd, result, location)
+}
 
-	return gotestmdCmd
+// optionalOutput is the annotation of the commands that don't write files, so OUTPUT_DIR is optional for them. It's
+// INPUT_DIR by default and only sets the import paths and the locations of the suites
+const optionalOutput = "optional-output"
+
+// completeArgs checks that the arguments completed by the config file at the location are INPUT_DIR, OUTPUT_DIR and
+// optional BASE_PKG. Sets OUTPUT_DIR to INPUT_DIR if it's missing and optional for the command
+func completeArgs(cmd *cobra.Command, args []string, location string) ([]string, error) {
+	if cmd.Annotations[optionalOutput] != "" {
+		if len(args) == 0 || args[0] == "" {
+			return nil, usageError(cmd, errors.Errorf("expected INPUT_DIR argument or %v in config file %v", config.InputKey, location))
+		}
+		if len(args) == 1 {
+			args = append(args, args[0])
+		}
+		if args[1] == "" {
+			args[1] = args[0]
+		}
+	}
+	if len(args) < 2 || args[0] == "" || args[1] == "" {
+		return nil, usageError(cmd, errors.Errorf("expected INPUT_DIR and OUTPUT_DIR arguments or %v and %v in config file %v", config.InputKey, config.OutputKey, location))
+	}
+	if len(args) > 3 {
+		return nil, usageError(cmd, errors.Errorf("expected INPUT_DIR, OUTPUT_DIR and optional BASE_PKG arguments, got %v arguments", len(args)))
+	}
+	return args, nil
+}
+
+// isListFlag returns true if the flag of the type accepts several values
+func isListFlag(typ string) bool {
+	return strings.HasSuffix(typ, "Array") || strings.HasSuffix(typ, "Slice") || typ == "stringToString"
+}
+
+func parseNaming(cmd *cobra.Command) (config.Naming, error) {
+	var result config.Naming
+	var err error
+	for flag, value := range map[string]*string{
+		"name-case":         &result.Case,
+		"name-separator":    &result.Separator,
+		"name-prefix":       &result.Prefix,
+		"name-suffix":       &result.Suffix,
+		"name-digit-prefix": &result.DigitPrefix,
+	} {
+		if *value, err = cmd.Flags().GetString(flag); err != nil {
+			return result, err
+		}
+	}
+	return result, result.Validate()
 }
 
-func processGoSuites(suites []*generator.Suite) error {
+func parseRoots(cmd *cobra.Command, outputDir string) ([]config.Root, error) {
+	values, err := cmd.Flags().GetStringArray("root")
+	if err != nil {
+		return nil, err
+	}
+	var roots []config.Root
+	for _, value := range values {
+		root := config.Root{InputDir: value}
+		if i := strings.Index(value, "="); i >= 0 {
+			root.InputDir, root.OutputDir = value[:i], value[i+1:]
+		}
+		if root.InputDir == "" {
+			return nil, errors.Errorf("invalid root %q, expected INPUT[=OUTPUT]", value)
+		}
+		if root.OutputDir == "" {
+			root.OutputDir = filepath.Join(outputDir, filepath.Base(root.InputDir))
+		}
+		roots = append(roots, root)
+	}
+	return roots, nil
+}
+
+// processGoSuites saves the Go files of the suites. Files of the cache generated from the same inputs are skipped
+func processGoSuites(w writer, suites []*generator.Suite, format string, cache *generationCache) error {
 	for _, suite := range suites {
-		dir, _ := filepath.Split(suite.Location)
-		_ = os.MkdirAll(dir, os.ModePerm)
-		err := os.WriteFile(suite.Location, []byte(suite.String()), os.ModePerm)
-		if err != nil {
-			return errors.Errorf("cannot save suite %v, : %v", suite.Name(), err.Error())
+		if err := writeGoFile(w, cache, suite.Dir, suite.Location, func(w io.Writer) error { return suite.WriteFormat(w, format) }); err != nil {
+			return errors.Wrapf(err, "suite %v", suite.Name())
+		}
+		if !suite.Entrypoint {
+			continue
+		}
+		if err := writeGoFile(w, cache, suite.Dir, suite.EntrypointLocation(), func(w io.Writer) error { return suite.WriteEntrypoint(w, format) }); err != nil {
+			return errors.Wrapf(err, "entrypoint of suite %v", suite.Name())
 		}
 	}
 
 	return nil
 }
 
-func processBashSuites(suites []*generator.Suite, matchRegex *regexp.Regexp) error {
+// writeGoFile generates, formats and saves the Go file of the suite in the dir unless the cache has it. generate writes
+// the unformatted source into the buffer of the formatter
+func writeGoFile(w writer, cache *generationCache, dir, location string, generate func(w io.Writer) error) error {
+	if cache.fresh(dir, location) {
+		if r, ok := w.(*reportWriter); ok {
+			r.files = append(r.files, &reportFile{Path: location, Status: fileUnchanged})
+		}
+		return nil
+	}
+	var source bytes.Buffer
+	if err := generate(&source); err != nil {
+		return err
+	}
+	formatted, err := generator.Format(location, source.String())
+	if err != nil {
+		// Keep the unformatted source to make the problem easy to find
+		_ = w.WriteFile(location, source.Bytes(), false)
+		return errors.Wrap(err, "cannot format")
+	}
+	if err = w.WriteFile(location, []byte(formatted), false); err != nil {
+		return err
+	}
+	cache.update(dir, location, []byte(formatted))
+	return nil
+}
+
+// openCache loads the cache file set by --cache if the writer saves files on the disk. Returns nil otherwise
+func openCache(cmd *cobra.Command, gen *generation, w writer) (*generationCache, error) {
+	location, _ := cmd.Flags().GetString("cache")
+	if location == "" || !writesToDisk(w) {
+		return nil, nil
+	}
+	return loadCache(location, &gen.config, gen.examples)
+}
+
+// matchedSuites returns the suites selected by --match. Bash scripts are generated for the matched suites and tests,
+// Go suites for the matched suites and the suites they depend on
+func (gen *generation) matchedSuites() ([]*generator.Suite, error) {
+	suites, err := gen.affectedSuites()
+	if err != nil || gen.config.Match == "" {
+		return suites, err
+	}
+	matchRegex, err := regexp.Compile(gen.config.Match)
+	if err != nil {
+		return nil, err
+	}
+	if gen.config.Bash {
+		return matchSuites(suites, matchRegex)
+	}
+	return matchGoSuites(suites, matchRegex)
+}
+
+// affectedSuites returns the suites generated from the examples affected by --changed-files and --changed-since. Fails
+// with ExitNoMatch if no suites are affected
+func (gen *generation) affectedSuites() ([]*generator.Suite, error) {
+	if gen.affected == nil {
+		return gen.suites, nil
+	}
+	var sources []string
+	for _, e := range gen.affected {
+		sources = append(sources, e.Source)
+	}
+	result := affectedSuites(gen.suites, sources)
+	if len(result) == 0 {
+		return nil, withExitCode(ExitNoMatch, errors.New("No suites are affected by the changed files"))
+	}
+	return result, nil
+}
+
+// matchGoSuites returns the suites matching the regex by name or having tests matching it with all their tests, and
+// the suites they include and require recursively, so the generated suites compile
+func matchGoSuites(suites []*generator.Suite, matchRegex *regexp.Regexp) ([]*generator.Suite, error) {
+	result := generator.Match(suites, matchRegex)
+	if len(result) == 0 {
+		return nil, withExitCode(ExitNoMatch, errors.Errorf("No matches found for pattern: %s", matchRegex.String()))
+	}
+	return result, nil
+}
+
+// matchSuites returns the suites matching the regex by name without their tests and the suites with tests matching it
+// with only these tests
+func matchSuites(suites []*generator.Suite, matchRegex *regexp.Regexp) ([]*generator.Suite, error) {
+	var matched = map[*generator.Suite]bool{}
 	matchFound := false
 
 	for _, suite := range suites {
@@ -115,12 +855,7 @@ func processBashSuites(suites []*generator.Suite, matchRegex *regexp.Regexp) err
 		}
 		matchFound = true
 		suite.Tests = nil
-		dir, _ := filepath.Split(suite.Location)
-		_ = os.MkdirAll(dir, os.ModePerm)
-		err := os.WriteFile(suite.Location, []byte(suite.BashString()), os.ModePerm)
-		if err != nil {
-			return errors.Errorf("cannot save suite %v, : %v", suite.Name(), err.Error())
-		}
+		matched[suite] = true
 	}
 
 	for _, suite := range suites {
@@ -136,48 +871,169 @@ func processBashSuites(suites []*generator.Suite, matchRegex *regexp.Regexp) err
 		}
 
 		suite.Tests = matchedTests
-		dir, _ := filepath.Split(suite.Location)
-		_ = os.MkdirAll(dir, os.ModePerm)
-		err := os.WriteFile(suite.Location, []byte(suite.BashString()), os.ModePerm)
+		matched[suite] = true
+	}
+
+	if !matchFound {
+		return nil, withExitCode(ExitNoMatch, errors.Errorf("No matches found for pattern: %s", matchRegex.String()))
+	}
+
+	var result []*generator.Suite
+	for _, suite := range suites {
+		if matched[suite] {
+			result = append(result, suite)
+		}
+	}
+	return result, nil
+}
+
+// processBashSuites writes the scripts of the suites with run-all.sh running them and returns the locations of the scripts
+func processBashSuites(w writer, g *generator.Generator, outputDir string, suites []*generator.Suite) ([]string, error) {
+	var scripts []string
+	for _, suite := range suites {
+		locations, err := writeBashScripts(w, suite)
 		if err != nil {
-			return errors.Errorf("cannot save suite %v, : %v", suite.Name(), err.Error())
+			return nil, err
 		}
+		scripts = append(scripts, locations...)
 	}
 
-	if !matchFound {
-		return errors.Errorf("No matches found for pattern: %s", matchRegex.String())
+	location := filepath.Join(outputDir, generator.BashRunAllScript)
+	script, err := g.BashRunAllString(suites)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.WriteFile(location, []byte(script), true); err != nil {
+		return nil, err
 	}
 
+	return append(scripts, location), nil
+}
+
+// processPowerShellSuites writes suite.ps1 of the suites into their dirs
+func processPowerShellSuites(w writer, suites []*generator.Suite) error {
+	for _, suite := range suites {
+		location := filepath.Join(suite.BashDir(), generator.PowerShellScript)
+		script, err := suite.PowerShellString()
+		if err != nil {
+			return errors.Wrapf(err, "suite %v", suite.Name())
+		}
+		if err := w.WriteFile(location, []byte(script), false); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func getFilter(root string) func(string) bool {
-	var ignored []string
-	ignored = append(ignored, filepath.Join(root, ".git"))
+// writeGitHubWorkflow writes the GitHub Actions workflow running the suites into the file if it's set. The workflow is
+// named after the file
+func writeGitHubWorkflow(w writer, g *generator.Generator, location string, suites []*generator.Suite) error {
+	if location == "" {
+		return nil
+	}
+	name := strings.TrimSuffix(filepath.Base(location), filepath.Ext(location))
+	workflow, err := g.GitHubWorkflowString(name, suites)
+	if err != nil {
+		return err
+	}
+	return w.WriteFile(location, []byte(workflow), false)
+}
 
-	return func(s string) bool {
-		for _, line := range ignored {
-			if strings.HasPrefix(s, line) {
-				return true
-			}
+// writeDockerCompose writes docker-compose.yml running the suites in the image with the current dir mounted and
+// docker-entrypoint.sh of each suite. Returns the locations of the entrypoints
+func writeDockerCompose(w writer, g *generator.Generator, outputDir, image string, suites []*generator.Suite) ([]string, error) {
+	repoDir, err := os.Getwd()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get the current dir")
+	}
+	var locations []string
+	for _, suite := range suites {
+		location := filepath.Join(suite.BashDir(), generator.DockerEntrypointScript)
+		entrypoint, err := suite.DockerEntrypointString()
+		if err != nil {
+			return nil, errors.Wrapf(err, "suite %v", suite.Name())
 		}
-		return false
+		if err := w.WriteFile(location, []byte(entrypoint), true); err != nil {
+			return nil, err
+		}
+		locations = append(locations, location)
+	}
+	location := filepath.Join(outputDir, generator.DockerComposeFile)
+	compose, err := g.DockerComposeString(image, repoDir, suites)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.WriteFile(location, []byte(compose), false); err != nil {
+		return nil, err
 	}
+	return locations, nil
 }
 
-func getRecursiveDirectories(root string) []string {
-	var result []string
-	var isIgnored = getFilter(root)
-	_ = filepath.Walk(root,
-		func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return err
-			}
-			if info.IsDir() && !isIgnored(path) {
-				result = append(result, path)
-			}
-			return nil
-		})
+// writeJSON exports the linked examples to OUTPUT_DIR/examples.json
+func writeJSON(w writer, outputDir string, examples []*linker.LinkedExample) error {
+	var sb strings.Builder
+	if err := export.Write(&sb, examples); err != nil {
+		return errors.Wrap(err, "cannot export examples")
+	}
+	return w.WriteFile(filepath.Join(outputDir, export.File), []byte(sb.String()), false)
+}
 
-	return result
+// writeBackend saves the files generated by the registered backend
+func writeBackend(w writer, b backend.Backend, outputDir string, examples []*linker.LinkedExample) error {
+	files, err := b.Generate(outputDir, examples)
+	if err != nil {
+		return errors.Wrapf(err, "format %v", b.Name())
+	}
+	for _, f := range files {
+		if err = w.WriteFile(f.Path, f.Content, f.Executable); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeMakefile writes the Makefile fragment with targets running the suites into the output dir
+func writeMakefile(w writer, g *generator.Generator, outputDir string, suites []*generator.Suite) error {
+	makefile, err := g.MakefileString(suites)
+	if err != nil {
+		return err
+	}
+	return w.WriteFile(filepath.Join(outputDir, generator.MakefileName), []byte(makefile), false)
+}
+
+// writeBashScripts writes the executable scripts of the suite into its dir and returns their locations
+func writeBashScripts(w writer, suite *generator.Suite) ([]string, error) {
+	scripts, err := suite.BashScripts()
+	if err != nil {
+		return nil, errors.Wrapf(err, "suite %v", suite.Name())
+	}
+	var names []string
+	for name := range scripts {
+		names = append(names, name)
+	}
+	// the scripts are written, printed and checked in the same order on every run
+	sort.Strings(names)
+	var locations []string
+	for _, name := range names {
+		location := filepath.Join(suite.BashDir(), name)
+		if err := w.WriteFile(location, []byte(scripts[name]), true); err != nil {
+			return nil, errors.Wrapf(err, "cannot save suite %v", suite.Name())
+		}
+		locations = append(locations, location)
+	}
+	return locations, nil
+}
+
+// validateBashScripts runs shellcheck against the scripts and returns its findings as an error
+func validateBashScripts(scripts []string) error {
+	shellcheck, err := exec.LookPath("shellcheck")
+	if err != nil {
+		return errors.Wrap(err, "--validate requires shellcheck")
+	}
+	// #nosec
+	output, err := exec.Command(shellcheck, append([]string{"--shell=bash"}, scripts...)...).CombinedOutput()
+	if err != nil {
+		return errors.Errorf("shellcheck found problems in generated scripts:\n%s", output)
+	}
+	return nil
 }
@@ -0,0 +1,44 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/gotestmd/internal/graph"
+)
+
+func newGraphCommand() *cobra.Command {
+	graphCmd := &cobra.Command{
+		Use:   "graph INPUT_DIR...",
+		Short: "Prints the linked examples as a Graphviz DOT or Mermaid graph",
+		Args:  cobra.MinimumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format := cmd.Flag("format").Value.String()
+			examples, _, err := loadExamples(args[0], rootOptions(args)...)
+			if err != nil {
+				return err
+			}
+			return graph.Write(cmd.OutOrStdout(), format, examples)
+		},
+	}
+
+	graphCmd.Flags().String("format", graph.DOT, "output format: dot or mermaid")
+
+	return graphCmd
+}
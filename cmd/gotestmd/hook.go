@@ -0,0 +1,201 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/gotestmd/internal/generator"
+)
+
+func newHookCommand() *cobra.Command {
+	hookCmd := &cobra.Command{
+		Use:   "hook INPUT_DIR OUTPUT_DIR [BASE_PKG]",
+		Short: "Regenerates the Go suites of the staged markdown files and stages them, for pre-commit hooks",
+		Args:  cobra.ArbitraryArgs,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			files, err := cmd.Flags().GetStringArray("files")
+			if err != nil {
+				return err
+			}
+			noStage, err := cmd.Flags().GetBool("no-stage")
+			if err != nil {
+				return err
+			}
+			if len(files) == 0 {
+				if files, err = stagedMarkdownFiles(); err != nil {
+					return err
+				}
+			}
+			if len(files) == 0 {
+				return nil
+			}
+			gen, err := prepare(cmd, args)
+			if err != nil {
+				return err
+			}
+			if gen.suites == nil || gen.config.Bash {
+				return errors.New("hook generates only Go suites")
+			}
+			removed, err := removeDeletedSuites(gen.config.OutputDir, files)
+			if err != nil {
+				return err
+			}
+			// The changed examples and the examples depending on them, e.g. the subsuites of a changed parent
+			var sources = append([]string(nil), files...)
+			for _, e := range affectedExamples(gen.examples, files) {
+				sources = append(sources, e.Source)
+			}
+			w := &reportWriter{writer: diskWriter{}}
+			if err = processGoSuites(w, affectedSuites(gen.suites, sources), gen.config.Format, nil); err != nil {
+				return err
+			}
+			var updated []string
+			for _, f := range w.files {
+				if f.Status == fileCreated || f.Status == fileUpdated {
+					updated = append(updated, f.Path)
+				}
+			}
+			if len(updated)+len(removed) == 0 {
+				return nil
+			}
+			if noStage {
+				var commands []string
+				if len(updated) > 0 {
+					commands = append(commands, "git add "+strings.Join(updated, " "))
+				}
+				if len(removed) > 0 {
+					commands = append(commands, "git rm --cached --ignore-unmatch "+strings.Join(removed, " "))
+				}
+				return withExitCode(ExitDrift, errors.Errorf("updated %v generated files, review and stage them:\n%v", len(updated)+len(removed), strings.Join(commands, "\n")))
+			}
+			if len(updated) > 0 {
+				// #nosec
+				if out, err := exec.Command("git", append([]string{"add", "--"}, updated...)...).CombinedOutput(); err != nil {
+					return errors.Wrapf(err, "cannot stage the generated files: %s", out)
+				}
+			}
+			if len(removed) > 0 {
+				// #nosec
+				if out, err := exec.Command("git", append([]string{"rm", "--cached", "--quiet", "--ignore-unmatch", "--"}, removed...)...).CombinedOutput(); err != nil {
+					return errors.Wrapf(err, "cannot stage the removed files: %s", out)
+				}
+			}
+			for _, location := range updated {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "staged %v\n", location)
+			}
+			for _, location := range removed {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "staged removal of %v\n", location)
+			}
+			return nil
+		},
+	}
+
+	addGenerateFlags(hookCmd.Flags())
+	hookCmd.Flags().StringArray("files", nil, "changed markdown file. Can be repeated. By default the staged .md files are taken from git")
+	hookCmd.Flags().Bool("no-stage", false, "fails with the list of updated files instead of staging them")
+
+	return hookCmd
+}
+
+// stagedMarkdownFiles returns the added, copied, modified, renamed and deleted .md files of the git index relative to the
+// current dir
+func stagedMarkdownFiles() ([]string, error) {
+	out, err := exec.Command("git", "diff", "--cached", "--name-only", "--relative", "--diff-filter=ACMRD").Output()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get the staged files")
+	}
+	var result []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasSuffix(line, ".md") {
+			result = append(result, line)
+		}
+	}
+	return result, nil
+}
+
+// affectedSuites returns the suites generated from the files
+func affectedSuites(suites []*generator.Suite, files []string) []*generator.Suite {
+	var result []*generator.Suite
+	for _, suite := range suites {
+		for _, source := range suite.Sources() {
+			if containsPath(files, source.File) {
+				result = append(result, suite)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// removeDeletedSuites removes the files of the output dir generated from the deleted files and the dirs left empty, and
+// returns the removed files
+func removeDeletedSuites(outputDir string, files []string) ([]string, error) {
+	var deleted []string
+	for _, file := range files {
+		if _, err := os.Stat(file); os.IsNotExist(err) {
+			deleted = append(deleted, file)
+		}
+	}
+	if len(deleted) == 0 {
+		return nil, nil
+	}
+	root := generator.ModuleRoot(outputDir)
+	if root == "" {
+		return nil, errors.Errorf("cannot find go.mod of %v: the sources of generated files are relative to the module root", outputDir)
+	}
+	stale, err := findStaleFiles(outputDir, root)
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	var dirs = map[string]bool{}
+	for _, location := range stale {
+		source := filepath.FromSlash(location.source)
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(root, source)
+		}
+		if !containsPath(deleted, source) {
+			continue
+		}
+		if err := os.Remove(location.path); err != nil {
+			return nil, withExitCode(ExitWrite, errors.Wrapf(err, "cannot remove %v", location.path))
+		}
+		result = append(result, location.path)
+		dirs[filepath.Dir(location.path)] = true
+	}
+	removeEmptyDirs(outputDir, dirs)
+	return result, nil
+}
+
+// containsPath returns true if the list has a path pointing to the same location as the path
+func containsPath(list []string, path string) bool {
+	for _, item := range list {
+		if samePath(item, path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestmd
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
+)
+
+func newInitCommand() *cobra.Command {
+	var requires, includes []string
+	initCmd := &cobra.Command{
+		Use:   "init DIR",
+		Short: "Creates DIR/README.md of a new example with Requires, Includes, Run and Cleanup sections",
+		Args:  cobra.ExactArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			location := filepath.Join(args[0], "README.md")
+			if _, err := os.Stat(location); err == nil {
+				return errors.Errorf("%v already exists", location)
+			}
+			for _, link := range append(append([]string(nil), requires...), includes...) {
+				warnMissingLink(args[0], link)
+			}
+			if err := os.MkdirAll(args[0], os.ModePerm); err != nil {
+				return errors.Wrapf(err, "cannot create dir %v", args[0])
+			}
+			if err := os.WriteFile(location, []byte(exampleSkeleton(args[0], requires, includes)), 0o600); err != nil {
+				return errors.Wrapf(err, "cannot save %v", location)
+			}
+			// The skeleton should be a valid example
+			if _, err := parser.New().ParseFile(location); err != nil {
+				return errors.Wrapf(err, "created %v can't be parsed", location)
+			}
+			logrus.Infof("created %v", location)
+			return nil
+		},
+	}
+
+	initCmd.Flags().StringArrayVar(&requires, "requires", nil, "link to an example to set up before the new one, e.g. ../basic. Can be repeated")
+	initCmd.Flags().StringArrayVar(&includes, "includes", nil, "link to an example run in the context of the new one, e.g. ./feature. Can be repeated")
+
+	return initCmd
+}
+
+// exampleSkeleton returns README.md of a new example in the dir with the links and placeholder blocks
+func exampleSkeleton(dir string, requires, includes []string) string {
+	var result strings.Builder
+	_, _ = result.WriteString("# " + title(filepath.Base(filepath.Clean(dir))) + "\n\n")
+	_, _ = result.WriteString("Describe what the example shows.\n\n")
+	writeLinks := func(section string, links []string) {
+		if len(links) == 0 {
+			return
+		}
+		_, _ = result.WriteString("## " + section + "\n\n")
+		for _, link := range links {
+			_, _ = result.WriteString("- [" + linkTitle(dir, link) + "](" + link + ")\n")
+		}
+		_, _ = result.WriteString("\n")
+	}
+	writeLinks("Requires", requires)
+	writeLinks("Includes", includes)
+	_, _ = result.WriteString("## Run\n\nDescribe the step.\n\n```bash\necho \"TODO: run the example\"\n```\n\n")
+	_, _ = result.WriteString("## Cleanup\n\n```bash\necho \"TODO: clean up the example\"\n```\n")
+	return result.String()
+}
+
+// linkTitle returns a readable name of the example linked from the dir, e.g. Basic for ../basic or Kernel2Kernel for
+// ../basic#Kernel2Kernel
+func linkTitle(dir, link string) string {
+	if i := strings.LastIndex(link, "#"); i >= 0 && i+1 < len(link) {
+		return link[i+1:]
+	}
+	switch remote, ok := linker.ParseRemote(link); {
+	case ok:
+		return title(path.Base(remote.Path))
+	case linker.IsAlias(link):
+		return title(strings.TrimPrefix(link, "@"))
+	case linker.IsURL(link):
+		return title(strings.TrimSuffix(path.Base(link), path.Ext(link)))
+	}
+	return title(filepath.Base(filepath.Join(dir, link)))
+}
+
+// title returns the name with spaces instead of dashes and underscores starting with a capital letter
+func title(name string) string {
+	name = strings.NewReplacer("-", " ", "_", " ").Replace(name)
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// warnMissingLink warns if the local link of the example in the dir points to a dir without README.md
+func warnMissingLink(dir, link string) {
+	if linker.IsAlias(link) || linker.IsRemote(link) || linker.IsURL(link) {
+		return
+	}
+	if i := strings.LastIndex(link, "#"); i >= 0 {
+		link = link[:i]
+	}
+	if _, err := os.Stat(filepath.Join(dir, link, "README.md")); err != nil {
+		logrus.Warnf("%v doesn't point to an example: %v", link, err)
+	}
+}
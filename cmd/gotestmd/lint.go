@@ -0,0 +1,55 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestmd
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/gotestmd/internal/lint"
+)
+
+func newLintCommand() *cobra.Command {
+	var failOn, shell string
+	lintCmd := &cobra.Command{
+		Use:   "lint INPUT_DIR...",
+		Short: "Checks the structure of the examples and fails on findings with the --fail-on severity or higher",
+		Args:  cobra.MinimumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if failOn != string(lint.SeverityError) && failOn != string(lint.SeverityWarning) {
+				return errors.Errorf("unknown --fail-on severity %v, use %v or %v", failOn, lint.SeverityError, lint.SeverityWarning)
+			}
+			findings := lint.Lint(args[0], exampleDirs(args...), lint.WithShell(shell))
+			for _, f := range findings {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), f)
+			}
+			errorCount, warningCount := lint.Count(findings, lint.SeverityError), lint.Count(findings, lint.SeverityWarning)
+			if errorCount > 0 || failOn == string(lint.SeverityWarning) && warningCount > 0 {
+				return withExitCode(ExitLint, errors.Errorf("found %v errors and %v warnings", errorCount, warningCount))
+			}
+			return nil
+		},
+	}
+
+	lintCmd.Flags().StringVar(&failOn, "fail-on", string(lint.SeverityError), "the lowest severity of findings failing the command: error or warning")
+	lintCmd.Flags().StringVar(&shell, "shell", "bash", "shell running the blocks without the shell=NAME attribute, bashisms are reported if it's sh")
+
+	return lintCmd
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/gotestmd/internal/generator"
+)
+
+func newListCommand() *cobra.Command {
+	listCmd := &cobra.Command{
+		Use:         "list INPUT_DIR [OUTPUT_DIR [BASE_PKG]]",
+		Short:       "Lists the suites with their tests, tags and dependencies without writing files",
+		Args:        cobra.ArbitraryArgs,
+		Annotations: map[string]string{optionalOutput: "true"},
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			asJSON, err := cmd.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+			gen, err := prepare(cmd, args)
+			if err != nil {
+				return err
+			}
+			if gen.suites == nil {
+				return errors.Errorf("--format=%v can't be used with list", gen.config.Format)
+			}
+			suites, err := gen.matchedSuites()
+			if err != nil {
+				return err
+			}
+			infos := gen.generator.List(suites)
+			if asJSON {
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(infos)
+			}
+			return writeList(cmd.OutOrStdout(), infos)
+		},
+	}
+
+	addGenerateFlags(listCmd.Flags())
+	listCmd.Flags().Bool("json", false, "prints the suites as a JSON array")
+
+	return listCmd
+}
+
+// writeList prints the suites with their non-empty properties indented below them
+func writeList(w io.Writer, suites []*generator.SuiteInfo) error {
+	var sb strings.Builder
+	for _, s := range suites {
+		_, _ = fmt.Fprintf(&sb, "%v %v\n", s.Name, s.Source)
+		for _, line := range [][]string{
+			{"tests", strings.Join(s.Tests, " ")},
+			{"includes", strings.Join(s.Includes, " ")},
+			{"requires", strings.Join(s.Requires, " ")},
+			{"build", s.BuildConstraint},
+			{"envs", strings.Join(s.Envs, " ")},
+		} {
+			if line[1] != "" {
+				_, _ = fmt.Fprintf(&sb, "  %v: %v\n", line[0], line[1])
+			}
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
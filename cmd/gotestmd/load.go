@@ -0,0 +1,79 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestmd
+
+import (
+	"github.com/networkservicemesh/gotestmd/internal/config"
+	pkggenerator "github.com/networkservicemesh/gotestmd/pkg/generator"
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+)
+
+// loadOptions returns the options loading the examples of the config and the base options loading them without
+// --prune, --run-tags, --skip-tags, --include and --exclude
+func loadOptions(c *config.Config) (options, base []pkggenerator.Option) {
+	base = []pkggenerator.Option{
+		pkggenerator.WithURLFetcher(linker.NewURLCache(c.URLCacheDir, c.Offline)),
+	}
+	for _, root := range c.Roots {
+		base = append(base, pkggenerator.WithRoot(root.InputDir, root.OutputDir))
+	}
+	if c.SkipOptional {
+		base = append(base, pkggenerator.WithoutOptional())
+	}
+	options = append(append([]pkggenerator.Option{}, base...),
+		pkggenerator.WithPrune(c.Prune...),
+		pkggenerator.WithTags(c.RunTags, c.SkipTags),
+		pkggenerator.WithInclude(c.Include...),
+		pkggenerator.WithExclude(c.Exclude...),
+		pkggenerator.WithOrphanCheck(c.FailOnOrphans),
+	)
+	if c.ExcludedDeps == config.ExcludedDepsInclude {
+		options = append(options, pkggenerator.WithExcludedDeps())
+	}
+	return options, base
+}
+
+// rootOptions returns the options linking the input dirs with the first one
+func rootOptions(inputDirs []string) []pkggenerator.Option {
+	var result []pkggenerator.Option
+	for _, inputDir := range inputDirs[1:] {
+		result = append(result, pkggenerator.WithRoot(inputDir, ""))
+	}
+	return result
+}
+
+// loadExamples parses and links the examples of the input dir with the options. Returns the linked examples and the
+// examples removed by --prune, --run-tags and --skip-tags
+func loadExamples(inputDir string, options ...pkggenerator.Option) ([]*linker.LinkedExample, []*linker.Pruned, error) {
+	examples, pruned, err := pkggenerator.Load(inputDir, options...)
+	switch err.(type) {
+	case *pkggenerator.ParseError:
+		return nil, nil, withExitCode(ExitParse, err)
+	case *pkggenerator.LinkError:
+		return nil, nil, withExitCode(ExitLink, err)
+	}
+	return examples, pruned, err
+}
+
+// exampleDirs returns the dirs of the input dirs README.md files of the examples are searched in
+func exampleDirs(inputDirs ...string) []string {
+	var result []string
+	for _, inputDir := range inputDirs {
+		result = append(result, pkggenerator.Dirs(inputDir)...)
+	}
+	return result
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/gotestmd/internal/lsp"
+)
+
+func newLSPCommand() *cobra.Command {
+	lspCmd := &cobra.Command{
+		Use:   "lsp [INPUT_DIR]",
+		Short: "Runs a language server on stdin and stdout reporting parse errors, broken links and other lint findings of the examples as diagnostics",
+		Args:  cobra.MaximumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := "."
+			if len(args) > 0 {
+				root = args[0]
+			}
+			dirs := func() []string {
+				return exampleDirs(root)
+			}
+			return lsp.New(root, dirs).Serve(cmd.InOrStdin(), cmd.OutOrStdout())
+		},
+	}
+
+	return lspCmd
+}
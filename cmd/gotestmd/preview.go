@@ -0,0 +1,68 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// preview prints a line per generated file telling if it would be created, updated or left unchanged. If showDiff is
+// set, unified diffs of the created and the updated files are printed instead
+func preview(out io.Writer, files memoryWriter, showDiff bool) error {
+	for _, location := range files.locations() {
+		// #nosec
+		actual, err := os.ReadFile(location)
+		action := "update"
+		switch {
+		case os.IsNotExist(err):
+			action = "create"
+		case err != nil:
+			return errors.Wrapf(err, "cannot read %v", location)
+		case bytes.Equal(actual, files[location]):
+			action = "unchanged"
+		}
+		if !showDiff {
+			_, _ = fmt.Fprintf(out, "%v %v\n", action, location)
+			continue
+		}
+		if action == "unchanged" {
+			continue
+		}
+		fromFile := "a/" + location
+		if action == "create" {
+			fromFile = "/dev/null"
+		}
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(actual)),
+			B:        difflib.SplitLines(string(files[location])),
+			FromFile: fromFile,
+			ToFile:   "b/" + location,
+			Context:  3,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "cannot compare %v", location)
+		}
+		_, _ = io.WriteString(out, diff)
+	}
+	return nil
+}
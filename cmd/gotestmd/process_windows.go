@@ -1,4 +1,4 @@
-// Copyright (c) 2020-2021 Doc.ai and/or its affiliates.
+// Copyright (c) 2026 Cisco and/or its affiliates.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -14,13 +14,12 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package parser
+package gotestmd
 
-// Example represents a markdown example. Contains all needed for generating suites content.
-type Example struct {
-	Includes []string
-	Requires []string
-	Run      []string
-	Cleanup  []string
-	Dir      string
+import "os/exec"
+
+func setProcessGroup(*exec.Cmd) {}
+
+func interruptProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
 }
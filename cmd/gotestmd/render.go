@@ -0,0 +1,133 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/gotestmd/internal/config"
+	"github.com/networkservicemesh/gotestmd/internal/generator"
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
+)
+
+func newRenderCommand() *cobra.Command {
+	var format string
+	var bash, powershell bool
+	renderCmd := &cobra.Command{
+		Use:   "render [FILE]",
+		Short: "Prints the suite generated from a single markdown file or from stdin if FILE is - or not set",
+		Args:  cobra.MaximumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch format {
+			case config.FormatTestify, config.FormatTesting, config.FormatGinkgo:
+			default:
+				return errors.Errorf("unknown format %v, expected one of: %v, %v, %v", format, config.FormatTestify, config.FormatTesting, config.FormatGinkgo)
+			}
+			if bash && powershell {
+				return errors.New("Flags --bash and --powershell can't be used together")
+			}
+			var location string
+			if len(args) > 0 && args[0] != "-" {
+				location = args[0]
+			}
+			e, err := parseDocument(cmd.InOrStdin(), location)
+			if err != nil {
+				return withExitCode(ExitParse, err)
+			}
+			if len(e.Includes)+len(e.Requires)+len(e.OptionalRequires) > 0 {
+				logrus.Warn("Includes and Requires links are ignored by render")
+				e.Includes, e.Requires, e.OptionalRequires = nil, nil, nil
+			}
+
+			// The example is generated as the only example of its parent dir
+			c := config.FromArgs([]string{filepath.Dir(e.Dir), "."})
+			c.Format, c.Bash, c.ImportPrefix = format, bash || powershell, "gotestmd/render"
+			linked, err := linker.New(c.InputDir).Link(e)
+			if err != nil {
+				return withExitCode(ExitLink, err)
+			}
+			suites, err := generator.New(c).Generate(linked...)
+			if err != nil {
+				return err
+			}
+			if len(suites) != 1 {
+				return errors.New("the document doesn't generate a suite")
+			}
+			suite := suites[0]
+
+			var result string
+			switch {
+			case bash:
+				var scripts map[string]string
+				if scripts, err = suite.BashScripts(); err != nil {
+					return err
+				}
+				result = scripts[generator.BashSuiteScript]
+			case powershell:
+				if result, err = suite.PowerShellString(); err != nil {
+					return err
+				}
+			default:
+				var source strings.Builder
+				if err = suite.WriteFormat(&source, format); err != nil {
+					return err
+				}
+				if result, err = generator.Format(suite.Location, source.String()); err != nil {
+					return errors.Wrap(err, "cannot format")
+				}
+			}
+			_, err = io.WriteString(cmd.OutOrStdout(), result)
+			return err
+		},
+	}
+
+	renderCmd.Flags().StringVar(&format, "format", config.FormatTestify, "format of the generated Go suite: testify, testing or ginkgo")
+	renderCmd.Flags().BoolVar(&bash, "bash", false, "prints suite.sh instead of the Go suite")
+	renderCmd.Flags().BoolVar(&powershell, "powershell", false, "prints suite.ps1 instead of the Go suite")
+
+	return renderCmd
+}
+
+// parseDocument parses the markdown file or the reader if the location is empty. The examples read from the reader
+// and the files of the current dir are located in the absolute current dir, so they get its name
+func parseDocument(r io.Reader, location string) (*parser.Example, error) {
+	var e *parser.Example
+	var err error
+	if location == "" {
+		e, err = parser.New().Parse(r)
+	} else {
+		e, err = parser.New().ParseFile(location)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if e.Dir == "" || e.Dir == "." {
+		if e.Dir, err = os.Getwd(); err != nil {
+			return nil, errors.Wrap(err, "cannot get the current dir")
+		}
+	}
+	return e, nil
+}
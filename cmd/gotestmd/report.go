@@ -0,0 +1,130 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Statuses of the files in the report
+const (
+	fileCreated   = "created"
+	fileUpdated   = "updated"
+	fileUnchanged = "unchanged"
+	fileFailed    = "failed"
+)
+
+// report is the summary of the generation written by --report
+type report struct {
+	// Error is the error the generation failed with
+	Error   string           `json:"error,omitempty"`
+	Timings *reportTimings   `json:"timings"`
+	Files   []*reportFile    `json:"files"`
+	Skipped []*reportSkipped `json:"skipped"`
+}
+
+type reportTimings struct {
+	// Load is the time of parsing, linking and generating the suites in memory
+	Load  string `json:"load"`
+	Write string `json:"write"`
+	Total string `json:"total"`
+}
+
+type reportFile struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// reportSkipped is an example without generated files
+type reportSkipped struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// reportWriter saves files with the writer and records their statuses
+type reportWriter struct {
+	writer
+	files []*reportFile
+}
+
+func (w *reportWriter) WriteFile(location string, content []byte, executable bool) error {
+	var file = &reportFile{Path: location, Status: fileUpdated}
+	// #nosec
+	switch actual, err := os.ReadFile(location); {
+	case os.IsNotExist(err):
+		file.Status = fileCreated
+	case err == nil && bytes.Equal(actual, content):
+		file.Status = fileUnchanged
+	}
+	w.files = append(w.files, file)
+	if err := w.writer.WriteFile(location, content, executable); err != nil {
+		file.Status, file.Error = fileFailed, err.Error()
+		return err
+	}
+	return nil
+}
+
+// newReport returns the report of the generation. The generation is nil if the examples can't be loaded
+func newReport(gen *generation, w *reportWriter, err error, load, write time.Duration) *report {
+	var result = &report{
+		Timings: &reportTimings{
+			Load:  load.String(),
+			Write: write.String(),
+			Total: (load + write).String(),
+		},
+		Files:   append([]*reportFile{}, w.files...),
+		Skipped: []*reportSkipped{},
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	sort.SliceStable(result.Files, func(i, j int) bool {
+		return result.Files[i].Path < result.Files[j].Path
+	})
+	if gen == nil {
+		return result
+	}
+	for name, reason := range skipMarks(gen) {
+		if name == "" {
+			name = "."
+		}
+		result.Skipped = append(result.Skipped, &reportSkipped{Name: name, Reason: reason})
+	}
+	sort.Slice(result.Skipped, func(i, j int) bool {
+		return result.Skipped[i].Name < result.Skipped[j].Name
+	})
+	return result
+}
+
+// saveReport writes the report to the location and returns the error of the generation if it's set
+func saveReport(location string, r *report, err error) error {
+	content, marshalErr := json.MarshalIndent(r, "", "  ")
+	if marshalErr != nil {
+		return errors.Wrap(marshalErr, "cannot create the report")
+	}
+	if saveErr := (diskWriter{}).WriteFile(location, append(content, '\n'), false); err == nil {
+		return saveErr
+	}
+	return err
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/gotestmd/internal/generator"
+)
+
+// cleanupDelay is the time given to an interrupted suite for its cleanup
+const cleanupDelay = time.Minute
+
+func newRunCommand() *cobra.Command {
+	var timeout time.Duration
+	var retries int
+	runCmd := &cobra.Command{
+		Use:   "run INPUT_DIR [PATH|PATTERN]",
+		Short: "Runs the examples located in the PATH or the suites and tests matching the PATTERN regex without generating files",
+		Args:  cobra.RangeArgs(1, 2),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if retries < 0 {
+				return errors.Errorf("invalid --retries %v, expected a non-negative number", retries)
+			}
+			outputDir, err := os.MkdirTemp("", "gotestmd-run-")
+			if err != nil {
+				return errors.Wrap(err, "cannot create a dir for the scripts")
+			}
+			defer func() {
+				_ = os.RemoveAll(outputDir)
+			}()
+			// The scripts are generated with --bash, the import path of the temporary dir isn't used
+			for name, value := range map[string]string{"bash": "true", "match": ".*", "import-prefix": "gotestmd/run"} {
+				if !cmd.Flags().Changed(name) {
+					if err = cmd.Flags().Set(name, value); err != nil {
+						return err
+					}
+				}
+			}
+			gen, err := prepare(cmd, []string{args[0], outputDir})
+			if err != nil {
+				return err
+			}
+			target := gen.config.Match
+			if len(args) > 1 {
+				target = args[1]
+			}
+			suites, err := selectSuites(gen.suites, target)
+			if err != nil {
+				return err
+			}
+			if _, err = processBashSuites(diskWriter{}, gen.generator, outputDir, suites); err != nil {
+				return err
+			}
+			for _, suite := range suites {
+				if err = runSuite(cmd, suite, outputDir, timeout, retries); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	addGenerateFlags(runCmd.Flags())
+	runCmd.Flags().DurationVar(&timeout, "timeout", 0, "time limit of each suite run. By default the timeout directive of the suite is used")
+	runCmd.Flags().IntVar(&retries, "retries", 0, "number of times a failed suite is run again")
+
+	return runCmd
+}
+
+// selectSuites returns the suites with the tests located in the path if it's a dir, otherwise the suites and the tests
+// matching the path as a regex
+func selectSuites(suites []*generator.Suite, target string) ([]*generator.Suite, error) {
+	if info, err := os.Stat(target); err != nil || !info.IsDir() {
+		matchRegex, err := regexp.Compile(target)
+		if err != nil {
+			return nil, err
+		}
+		return matchSuites(suites, matchRegex)
+	}
+	var result []*generator.Suite
+	for _, suite := range suites {
+		if isSubdir(target, suite.Dir) {
+			result = append(result, suite)
+			continue
+		}
+		var tests []*generator.Test
+		for _, test := range suite.Tests {
+			if isSubdir(target, test.Dir) {
+				tests = append(tests, test)
+			}
+		}
+		if len(tests) > 0 {
+			suite.Tests = tests
+			result = append(result, suite)
+		}
+	}
+	if len(result) == 0 {
+		return nil, withExitCode(ExitNoMatch, errors.Errorf("no examples found in %v", target))
+	}
+	return result, nil
+}
+
+// isSubdir returns true if the dir is the parent dir or located in it
+func isSubdir(parent, dir string) bool {
+	rel, err := filepath.Rel(parent, dir)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// runSuite runs setup, tests and cleanup of the suite with its output streamed to the command output. Failed runs are
+// repeated up to retries times
+func runSuite(cmd *cobra.Command, suite *generator.Suite, outputDir string, timeout time.Duration, retries int) error {
+	if timeout == 0 {
+		timeout = suite.Timeout
+	}
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			logrus.Warnf("suite %v failed: %v, attempt %v of %v", suite.Dir, err, attempt+1, retries+1)
+		}
+		if err = runSuiteScript(context.Background(), cmd.OutOrStdout(), cmd.ErrOrStderr(), suite, outputDir, timeout); err == nil {
+			return nil
+		}
+	}
+	return withExitCode(ExitRun, errors.Wrapf(err, "suite %v failed", suite.Dir))
+}
+
+// runSuiteScript runs the bash script of the suite once, the script is interrupted when the ctx is done. If the tests
+// are set, only they run after the setup of the suite
+func runSuiteScript(ctx context.Context, stdout, stderr io.Writer, suite *generator.Suite, outputDir string, timeout time.Duration, tests ...string) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	// #nosec
+	script := exec.CommandContext(ctx, "bash", append([]string{filepath.Join(suite.BashDir(), generator.BashSuiteScript), "all"}, tests...)...)
+	script.Stdout, script.Stderr = stdout, stderr
+	script.Env = append(os.Environ(), "GOTESTMD_JUNIT=", "GOTESTMD_LOGS="+filepath.Join(outputDir, "logs"))
+	// Interrupted scripts run their cleanup, they are killed if it takes longer than cleanupDelay
+	setProcessGroup(script)
+	script.Cancel = func() error {
+		return interruptProcessGroup(script)
+	}
+	script.WaitDelay = cleanupDelay
+	err := script.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return errors.Errorf("timed out after %v", timeout)
+	}
+	return err
+}
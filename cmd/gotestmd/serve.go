@@ -0,0 +1,127 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/gotestmd/internal/generator"
+	"github.com/networkservicemesh/gotestmd/internal/portal"
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+)
+
+func newServeCommand() *cobra.Command {
+	var addr string
+	var allowRun bool
+	serveCmd := &cobra.Command{
+		Use:   "serve INPUT_DIR",
+		Short: "Serves the example tree as web pages showing each document with the examples set up before it",
+		Long: `Serves the example tree as web pages showing each document with the examples set up before it.
+With --allow-run the pages have a button running the suite of the example on the machine of the server and streaming
+its output, so expose the server only to the operators allowed to run the examples.`,
+		Args: cobra.ExactArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			outputDir, err := os.MkdirTemp("", "gotestmd-serve-")
+			if err != nil {
+				return errors.Wrap(err, "cannot create a dir for the scripts")
+			}
+			defer func() {
+				_ = os.RemoveAll(outputDir)
+			}()
+			// The scripts are generated with --bash, the import path of the temporary dir isn't used
+			for name, value := range map[string]string{"bash": "true", "match": ".*", "import-prefix": "gotestmd/serve"} {
+				if !cmd.Flags().Changed(name) {
+					if err = cmd.Flags().Set(name, value); err != nil {
+						return err
+					}
+				}
+			}
+			gen, err := prepare(cmd, []string{args[0], outputDir})
+			if err != nil {
+				return err
+			}
+			var options = []portal.Option{portal.WithAddr(addr)}
+			if allowRun {
+				if _, err = processBashSuites(diskWriter{}, gen.generator, outputDir, gen.suites); err != nil {
+					return err
+				}
+				options = append(options, portal.WithRun(newPortalRun(gen.suites, outputDir)))
+			}
+			server := &http.Server{
+				Addr:              addr,
+				Handler:           portal.New(gen.examples, options...),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+			logrus.Infof("serving the examples on http://%v", addr)
+			return server.ListenAndServe()
+		},
+	}
+
+	addGenerateFlags(serveCmd.Flags())
+	serveCmd.Flags().StringVar(&addr, "addr", "localhost:8080", "address to listen on")
+	serveCmd.Flags().BoolVar(&allowRun, "allow-run", false, "allows running the suites from the pages")
+
+	return serveCmd
+}
+
+// newPortalRun returns a function running the suite of the example or the suite of its parent with only the example
+// test. Suites run one at a time since they share the environment
+func newPortalRun(suites []*generator.Suite, outputDir string) portal.RunFunc {
+	var mu sync.Mutex
+	return func(ctx context.Context, e *linker.LinkedExample, w io.Writer) error {
+		suite, tests := findSuite(suites, e)
+		if suite == nil {
+			return errors.Errorf("no suite is generated from %v", e.Dir)
+		}
+		if !mu.TryLock() {
+			return errors.New("another suite is running")
+		}
+		defer mu.Unlock()
+		if len(tests) > 0 {
+			_, _ = fmt.Fprintf(w, "running test %v of suite %v\n", tests[0], suite.Dir)
+		}
+		return runSuiteScript(ctx, w, w, suite, outputDir, suite.Timeout, tests...)
+	}
+}
+
+// findSuite returns the suite generated from the example or the suite with the test generated from it
+func findSuite(suites []*generator.Suite, e *linker.LinkedExample) (*generator.Suite, []string) {
+	for _, suite := range suites {
+		if suite.Dir == e.Dir {
+			return suite, nil
+		}
+	}
+	for _, suite := range suites {
+		for _, test := range suite.Tests {
+			if test.Dir == e.Dir {
+				return suite, []string{test.Name}
+			}
+		}
+	}
+	return nil, nil
+}
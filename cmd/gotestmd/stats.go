@@ -0,0 +1,58 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestmd
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/gotestmd/internal/graph"
+)
+
+func newStatsCommand() *cobra.Command {
+	var asJSON bool
+	var top int
+	statsCmd := &cobra.Command{
+		Use:   "stats INPUT_DIR...",
+		Short: "Prints the numbers of examples, tests and commands, the maximum dependency depth and the largest setups",
+		Args:  cobra.MinimumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if top < 0 {
+				return errors.Errorf("invalid --top %v, expected a non-negative number", top)
+			}
+			examples, _, err := loadExamples(args[0], rootOptions(args)...)
+			if err != nil {
+				return err
+			}
+			stats := graph.NewStats(examples, top)
+			if asJSON {
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(stats)
+			}
+			return graph.WriteStats(cmd.OutOrStdout(), stats)
+		},
+	}
+
+	statsCmd.Flags().BoolVar(&asJSON, "json", false, "prints the stats as a JSON object")
+	statsCmd.Flags().IntVar(&top, "top", 5, "number of the examples with the most examples set up before them to print")
+
+	return statsCmd
+}
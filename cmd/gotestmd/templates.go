@@ -0,0 +1,55 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/gotestmd/internal/generator"
+)
+
+func newTemplatesCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "templates [DIR]",
+		Short: "Lists the built-in templates or writes them to DIR as NAME.tmpl files to be customized and used with --templates",
+		Args:  cobra.MaximumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				for _, name := range generator.TemplateNames() {
+					_, _ = fmt.Fprintln(cmd.OutOrStdout(), name)
+				}
+				return nil
+			}
+			if err := os.MkdirAll(args[0], os.ModePerm); err != nil {
+				return errors.Wrapf(err, "cannot create templates dir %v", args[0])
+			}
+			for _, name := range generator.TemplateNames() {
+				source, _ := generator.BuiltinTemplate(name)
+				if err := os.WriteFile(filepath.Join(args[0], name+".tmpl"), []byte(source), 0o600); err != nil {
+					return errors.Wrapf(err, "cannot write template %v", name)
+				}
+			}
+			return nil
+		},
+	}
+}
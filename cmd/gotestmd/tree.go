@@ -0,0 +1,97 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestmd
+
+import (
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/gotestmd/internal/generator"
+	"github.com/networkservicemesh/gotestmd/internal/graph"
+)
+
+func newTreeCommand() *cobra.Command {
+	treeCmd := &cobra.Command{
+		Use:         "tree INPUT_DIR [OUTPUT_DIR [BASE_PKG]]",
+		Short:       "Prints the examples as a tree of includes with their requires, marking the ones skipped by the flags",
+		Args:        cobra.ArbitraryArgs,
+		Annotations: map[string]string{optionalOutput: "true"},
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			gen, err := prepare(cmd, args)
+			if err != nil {
+				return err
+			}
+			all, _, err := loadExamples(gen.config.InputDir, gen.baseOptions...)
+			if err != nil {
+				return err
+			}
+			marks := skipMarks(gen)
+			var loaded = map[string]bool{}
+			for _, e := range gen.examples {
+				loaded[e.Name] = true
+			}
+			for _, e := range all {
+				if _, ok := marks[e.Name]; !ok && !loaded[e.Name] {
+					marks[e.Name] = "excluded"
+				}
+			}
+			return graph.WriteTree(cmd.OutOrStdout(), all, marks)
+		},
+	}
+
+	addGenerateFlags(treeCmd.Flags())
+
+	return treeCmd
+}
+
+// skipMarks returns the reasons of skipping examples by --prune, --run-tags, --skip-tags and --match by the names of the examples
+func skipMarks(gen *generation) map[string]string {
+	var result = map[string]string{}
+	for _, pruned := range gen.pruned {
+		result[pruned.Example.Name] = "pruned, " + pruned.Reason
+	}
+	if (gen.config.Match == "" && gen.affected == nil) || gen.suites == nil {
+		return result
+	}
+	// Matching fails if nothing matches, then all examples are skipped
+	affected, _ := gen.affectedSuites()
+	matched, _ := gen.matchedSuites()
+	affectedDirs, matchedDirs := suiteDirs(affected), suiteDirs(matched)
+	for _, e := range gen.examples {
+		switch {
+		case !affectedDirs[filepath.Clean(e.Dir)]:
+			result[e.Name] = "not affected by the changed files"
+		case !matchedDirs[filepath.Clean(e.Dir)]:
+			result[e.Name] = "not matched by --match"
+		}
+	}
+	return result
+}
+
+// suiteDirs returns the dirs of the suites and their tests
+func suiteDirs(suites []*generator.Suite) map[string]bool {
+	var result = map[string]bool{}
+	for _, suite := range suites {
+		result[filepath.Clean(suite.Dir)] = true
+		for _, test := range suite.Tests {
+			result[filepath.Clean(test.Dir)] = true
+		}
+	}
+	return result
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestmd
+
+import "github.com/spf13/cobra"
+
+// withUsage prints the usage of the commands for the errors of their arguments and flags. Other failures print only
+// the error, since the root command silences the usage
+func withUsage(cmd *cobra.Command) {
+	cmd.SetFlagErrorFunc(usageError)
+	for _, c := range append([]*cobra.Command{cmd}, cmd.Commands()...) {
+		if args := c.Args; args != nil {
+			c.Args = func(cmd *cobra.Command, a []string) error {
+				if err := args(cmd, a); err != nil {
+					return usageError(cmd, err)
+				}
+				return nil
+			}
+		}
+	}
+}
+
+// usageError prints the usage of the command and returns the error of its arguments or flags
+func usageError(cmd *cobra.Command, err error) error {
+	cmd.PrintErrln(cmd.UsageString())
+	return err
+}
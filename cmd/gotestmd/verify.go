@@ -0,0 +1,111 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// sourceRegex matches the Source: PATH HASH provenance headers of generated files
+var sourceRegex = regexp.MustCompile(`(?m)^(?://|#) Source: (.+) (sha256:[0-9a-f]+)$`)
+
+func newVerifyCommand() *cobra.Command {
+	verifyCmd := &cobra.Command{
+		Use:   "verify INPUT_DIR OUTPUT_DIR [BASE_PKG]",
+		Short: "Regenerates the suites in memory and fails if the generated files on the disk are out of date",
+		Args:  cobra.ArbitraryArgs,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			files := memoryWriter{}
+			if err := generate(cmd, args, files); err != nil {
+				return err
+			}
+			return verify(cmd.OutOrStdout(), files)
+		},
+	}
+
+	addGenerateFlags(verifyCmd.Flags())
+
+	return verifyCmd
+}
+
+// verify compares the generated files with the files on the disk and prints a line per out of date file
+func verify(out io.Writer, files memoryWriter) error {
+	var outdated int
+	for _, location := range files.locations() {
+		// #nosec
+		actual, err := os.ReadFile(location)
+		switch {
+		case os.IsNotExist(err):
+			_, _ = fmt.Fprintf(out, "missing %v\n", location)
+		case err != nil:
+			return errors.Wrapf(err, "cannot read %v", location)
+		case bytes.Equal(actual, files[location]):
+			continue
+		default:
+			_, _ = fmt.Fprintf(out, "outdated %v: %v\n", location, describeChanges(string(actual), string(files[location])))
+		}
+		outdated++
+	}
+	if outdated > 0 {
+		return withExitCode(ExitDrift, errors.Errorf("%v of %v generated files are out of date, run gotestmd with the same arguments to update them", outdated, len(files)))
+	}
+	return nil
+}
+
+// describeChanges compares the sources in the provenance headers of the files
+func describeChanges(actual, expected string) string {
+	actualSources, expectedSources := parseSources(actual), parseSources(expected)
+	var result []string
+	for path, hash := range expectedSources {
+		switch actualSources[path] {
+		case hash:
+		case "":
+			result = append(result, path+" added")
+		default:
+			result = append(result, path+" changed")
+		}
+	}
+	for path := range actualSources {
+		if _, ok := expectedSources[path]; !ok {
+			result = append(result, path+" removed")
+		}
+	}
+	if len(result) == 0 {
+		return "sources are the same, the file is edited or generated by another version of gotestmd"
+	}
+	sort.Strings(result)
+	return strings.Join(result, ", ")
+}
+
+// parseSources returns hashes of the sources of the generated file by their paths
+func parseSources(content string) map[string]string {
+	var result = map[string]string{}
+	for _, match := range sourceRegex.FindAllStringSubmatch(content, -1) {
+		result[match[1]] = match[2]
+	}
+	return result
+}
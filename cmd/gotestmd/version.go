@@ -0,0 +1,50 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/gotestmd/internal/version"
+)
+
+func newVersionCommand() *cobra.Command {
+	var asJSON bool
+	versionCmd := &cobra.Command{
+		Use:   "version",
+		Short: "Prints the version of gotestmd with the commit and the date it's built from",
+		Args:  cobra.NoArgs,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info := version.Get()
+			if asJSON {
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(info)
+			}
+			_, err := fmt.Fprintf(cmd.OutOrStdout(), "gotestmd %v %v\n", info, info.GoVersion)
+			return err
+		},
+	}
+
+	versionCmd.Flags().BoolVar(&asJSON, "json", false, "prints the version as a JSON object")
+
+	return versionCmd
+}
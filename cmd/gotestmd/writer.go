@@ -0,0 +1,80 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// writer saves generated files
+type writer interface {
+	// WriteFile saves the content into the location. Executable files get 0755 permissions
+	WriteFile(location string, content []byte, executable bool) error
+}
+
+// diskWriter saves files on the disk creating their dirs. Files with the same content aren't rewritten, so repeated
+// generation keeps their modification times
+type diskWriter struct{}
+
+func (diskWriter) WriteFile(location string, content []byte, executable bool) error {
+	if err := os.MkdirAll(filepath.Dir(location), os.ModePerm); err != nil {
+		return withExitCode(ExitWrite, errors.Wrapf(err, "cannot create dir of %v", location))
+	}
+	// #nosec
+	if current, err := os.ReadFile(location); err != nil || !bytes.Equal(current, content) {
+		if err = os.WriteFile(location, content, os.ModePerm); err != nil {
+			return withExitCode(ExitWrite, errors.Wrapf(err, "cannot save %v", location))
+		}
+	}
+	if !executable {
+		return nil
+	}
+	// WriteFile keeps the permissions of existing files
+	return withExitCode(ExitWrite, errors.Wrapf(os.Chmod(location, 0o755), "cannot make %v executable", location))
+}
+
+// writesToDisk returns true if the writer saves files on the disk
+func writesToDisk(w writer) bool {
+	if r, ok := w.(*reportWriter); ok {
+		w = r.writer
+	}
+	_, ok := w.(diskWriter)
+	return ok
+}
+
+// memoryWriter keeps files in memory by their cleaned locations
+type memoryWriter map[string][]byte
+
+func (w memoryWriter) WriteFile(location string, content []byte, _ bool) error {
+	w[filepath.Clean(location)] = content
+	return nil
+}
+
+// locations returns the sorted locations of the files
+func (w memoryWriter) locations() []string {
+	var result []string
+	for location := range w {
+		result = append(result, location)
+	}
+	sort.Strings(result)
+	return result
+}
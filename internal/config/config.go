@@ -20,16 +20,186 @@
 package config
 
 import (
+	"go/build/constraint"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
+// Formats of generated Go suites
+const (
+	// FormatTestify generates testify suites
+	FormatTestify = "testify"
+	// FormatTesting generates standard library tests without testify
+	FormatTesting = "testing"
+	// FormatGinkgo generates ginkgo specs
+	FormatGinkgo = "ginkgo"
+	// FormatJSON exports the linked examples as JSON instead of generating suites
+	FormatJSON = "json"
+)
+
+// Suites that get generated entrypoint tests
+const (
+	// EntrypointsNone doesn't generate entrypoint tests
+	EntrypointsNone = "none"
+	// EntrypointsRoots generates entrypoint tests for suites not included by other suites
+	EntrypointsRoots = "roots"
+	// EntrypointsLeaves generates entrypoint tests for suites without included suites
+	EntrypointsLeaves = "leaves"
+	// EntrypointsAll generates entrypoint tests for all suites
+	EntrypointsAll = "all"
+)
+
+// Handling of dependencies on examples excluded by Include and Exclude
+const (
+	// ExcludedDepsError fails if selected examples depend on excluded ones
+	ExcludedDepsError = "error"
+	// ExcludedDepsInclude adds excluded examples required by the selected ones
+	ExcludedDepsInclude = "include"
+)
+
+// Case styles of generated test and suite names
+const (
+	// NamingTitle capitalizes the first letter of each word and lowercases the rest, e.g. leaf-A becomes Leaf_a
+	NamingTitle = "title"
+	// NamingCamel joins the words capitalizing their first letters, e.g. leaf-A becomes LeafA
+	NamingCamel = "camel"
+	// NamingPreserve keeps the case and capitalizes only the first letter, e.g. leaf-A becomes Leaf_A
+	NamingPreserve = "preserve"
+)
+
+// Naming contains the rules of generated test and suite names
+type Naming struct {
+	// Case is the case style of the names
+	Case string
+	// Separator replaces characters that can't be used in Go identifiers. Camel case drops them
+	Separator string
+	// Prefix and Suffix are added to the names of tests and suites
+	Prefix string
+	Suffix string
+	// DigitPrefix is added to suite types starting with a digit
+	DigitPrefix string
+}
+
+// DefaultNaming returns the naming rules used by default
+func DefaultNaming() Naming {
+	return Naming{
+		Case:        NamingTitle,
+		Separator:   "_",
+		DigitPrefix: "_",
+	}
+}
+
+var identifierPartRegex = regexp.MustCompile(`^[a-zA-Z0-9_]*$`)
+
+// Validate checks that the naming rules produce valid Go identifiers
+func (n Naming) Validate() error {
+	switch n.Case {
+	case NamingTitle, NamingCamel, NamingPreserve:
+	default:
+		return errors.Errorf("unknown name case %v, expected one of: %v, %v, %v", n.Case, NamingTitle, NamingCamel, NamingPreserve)
+	}
+	for _, part := range []string{n.Separator, n.Prefix, n.Suffix} {
+		if !identifierPartRegex.MatchString(part) {
+			return errors.Errorf("invalid name part %q, only letters, digits and _ are allowed", part)
+		}
+	}
+	if !envNameRegex.MatchString(n.DigitPrefix) {
+		return errors.Errorf("invalid digit prefix %q, it should start with a letter or _", n.DigitPrefix)
+	}
+	return nil
+}
+
 // Config contains input dir with .md examples and output dir for generated suites
 type Config struct {
 	InputDir  string
 	OutputDir string
-	BasePkg   string
-	Bash      bool
-	Match     string
+	// Roots are additional input dirs linked together with InputDir
+	Roots   []Root
+	BasePkg string
+	Bash    bool
+	Match   string
+	// BashJobs is the default number of tests of a suite run in parallel by generated bash scripts
+	BashJobs int
+	// BaseType is the type from BasePkg embedded by generated suites
+	BaseType string
+	// PlainBase is set if BaseType may be a plain testify suite, so the suites required by a suite are set up for it
+	// instead of being shared with SetupParents
+	PlainBase bool
+	// Module overrides the module path from go.mod used to build import paths of generated suites
+	Module string
+	// ImportPrefix is the import path of OutputDir. By default it's detected from the nearest go.mod
+	ImportPrefix string
+	// Format is the format of generated Go suites
+	Format string
+	// Parallel marks all examples as safe to run in parallel with their siblings
+	Parallel bool
+	// Steps runs each Run block of testify suite tests as a subtest
+	Steps bool
+	// LogSteps logs the location of each step in the markdown file before running it
+	LogSteps bool
+	// SkipUnlessEnv is an env variable that should be set to run generated suites, e.g. E2E
+	SkipUnlessEnv string
+	// Timeout limits the time of each generated suite with its tests and included suites
+	Timeout time.Duration
+	// CommandTimeout limits the time of each command of generated testify suites
+	CommandTimeout time.Duration
+	// Entrypoints selects suites that get a generated *_test.go file running them
+	Entrypoints string
+	// TemplatesDir contains NAME.tmpl files overriding the built-in templates
+	TemplatesDir string
+	// TemplateData contains values available in the templates with the data function
+	TemplateData map[string]string
+	// SinglePackage generates all testify suites into the package of OutputDir with unique type names
+	SinglePackage bool
+	// Naming contains the rules of generated test and suite names
+	Naming Naming
+	// BuildTags are go:build constraints added to all generated Go suites, e.g. integration
+	BuildTags []string
+	// FailOnOrphans fails generation if there are nested examples that don't end up in any generated suite
+	FailOnOrphans bool
+	// Prune contains globs of example dirs to remove with their subtrees and all dependent examples
+	Prune []string
+	// RunTags keeps only the examples having any of the tags with the examples they include and need
+	RunTags []string
+	// SkipTags removes the examples having any of the tags like Prune
+	SkipTags []string
+	// Include contains globs of example dirs to parse. All dirs are parsed if it's empty
+	Include []string
+	// Exclude contains globs of example dirs to skip with their subtrees
+	Exclude []string
+	// ExcludedDeps is the handling of dependencies on excluded examples
+	ExcludedDeps string
+	// SkipOptional drops optional dependencies even if they exist in the input dir
+	SkipOptional bool
+	// RemotePrefixes maps a Go module to the import path prefix of its generated suites.
+	// By default suites of a remote module are imported by the module path.
+	RemotePrefixes map[string]string
+	// URLCacheDir is the directory for markdown files downloaded by URL
+	URLCacheDir string
+	// Offline uses only cached markdown files for Requires links with URLs
+	Offline bool
+	// Rewrite contains s/REGEX/REPLACEMENT/ rules applied in order to each line of the bash blocks of the examples
+	Rewrite []string
+	// Artifacts are the commands whose output is collected by name by all generated testify runners when a test fails.
+	// Artifacts of the front matter override them
+	Artifacts map[string]string
+}
+
+// KubernetesDiagnostics are the artifacts describing the state of the cluster added by --k8s-diagnostics
+var KubernetesDiagnostics = map[string]string{
+	"pods":          "kubectl get pods -A -o wide",
+	"events":        "kubectl get events -A --sort-by=.lastTimestamp",
+	"describe-pods": "kubectl describe pods -A",
+}
+
+// Root is an additional input dir with the output dir for its suites
+type Root struct {
+	InputDir  string
+	OutputDir string
 }
 
 // FromArgs returns Config from the os.Args
@@ -38,14 +208,59 @@ func FromArgs(args []string) Config {
 		logrus.Fatal("ARGs have wrong length. Expected: (string)input-dir (string)output-dir (string)base-pkg[optional]")
 	}
 	result := Config{
-		InputDir:  args[0],
-		OutputDir: args[1],
-		BasePkg:   "github.com/networkservicemesh/gotestmd/pkg/suites/shell",
+		InputDir:     args[0],
+		OutputDir:    args[1],
+		BasePkg:      "github.com/networkservicemesh/gotestmd/pkg/suites/shell",
+		BaseType:     "Suite",
+		Format:       FormatTestify,
+		Entrypoints:  EntrypointsNone,
+		ExcludedDeps: ExcludedDepsError,
+		Naming:       DefaultNaming(),
 	}
 
 	if len(args) == 3 {
 		result.BasePkg = args[2]
+		result.PlainBase = true
 	}
 
 	return result
 }
+
+// ParseBaseSuite parses the base suite in the IMPORT_PATH.TYPE format, e.g. github.com/org/repo/suites/base.Suite
+func ParseBaseSuite(s string) (pkg, typ string, err error) {
+	i := strings.LastIndex(s, ".")
+	if i <= strings.LastIndex(s, "/") || i == len(s)-1 {
+		return "", "", errors.Errorf("invalid base suite %v, expected IMPORT_PATH.TYPE", s)
+	}
+	return s[:i], s[i+1:], nil
+}
+
+var envNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// IsEnvName checks that s can be used as an env variable name in bash and generated code
+func IsEnvName(s string) bool {
+	return envNameRegex.MatchString(s)
+}
+
+var artifactNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+// ParseArtifact parses the artifact in NAME=COMMAND format, e.g. pods=kubectl get pods -A
+func ParseArtifact(s string) (name, cmd string, err error) {
+	name, cmd, ok := strings.Cut(s, "=")
+	if !ok || strings.TrimSpace(cmd) == "" {
+		return "", "", errors.Errorf("invalid artifact %q, expected NAME=COMMAND", s)
+	}
+	if !artifactNameRegex.MatchString(name) {
+		return "", "", errors.Errorf("invalid artifact name %q, only letters, digits, _, . and - are allowed", name)
+	}
+	return name, cmd, nil
+}
+
+// ParseBuildTag checks that the tag is a valid go:build expression, e.g. integration or linux && !arm
+func ParseBuildTag(tag string) (constraint.Expr, error) {
+	expr, err := constraint.Parse("//go:build " + tag)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid build tag %v", tag)
+	}
+	return expr, nil
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/gotestmd/internal/config"
+)
+
+func TestParseBaseSuite(t *testing.T) {
+	pkg, typ, err := config.ParseBaseSuite("github.com/org/repo/suites/base.Suite")
+	require.NoError(t, err)
+	require.Equal(t, "github.com/org/repo/suites/base", pkg)
+	require.Equal(t, "Suite", typ)
+
+	for _, s := range []string{"github.com/org/repo/suites/base", "base.", "github.com/org.repo/base"} {
+		_, _, err = config.ParseBaseSuite(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestParseArtifact(t *testing.T) {
+	name, cmd, err := config.ParseArtifact("pods=kubectl get pods -o custom-columns=NAME:.metadata.name,NODE:.spec.nodeName")
+	require.NoError(t, err)
+	require.Equal(t, "pods", name)
+	require.Equal(t, "kubectl get pods -o custom-columns=NAME:.metadata.name,NODE:.spec.nodeName", cmd)
+
+	for _, s := range []string{"pods", "pods=", "../pods=ls", "=ls"} {
+		_, _, err = config.ParseArtifact(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestNamingValidate(t *testing.T) {
+	require.NoError(t, config.DefaultNaming().Validate())
+
+	naming := config.DefaultNaming()
+	naming.Case = config.NamingCamel
+	naming.Separator = ""
+	naming.Prefix = "E2E_"
+	require.NoError(t, naming.Validate())
+
+	for _, n := range []config.Naming{
+		{Case: "kebab", DigitPrefix: "_"},
+		{Case: config.NamingTitle, Separator: "-", DigitPrefix: "_"},
+		{Case: config.NamingTitle, Suffix: "!", DigitPrefix: "_"},
+		{Case: config.NamingTitle, DigitPrefix: "1"},
+		{Case: config.NamingTitle},
+	} {
+		require.Error(t, n.Validate(), n)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	location := filepath.Join(t.TempDir(), config.FileName)
+	require.NoError(t, os.WriteFile(location, []byte(`input: examples
+output: suites
+bash: true
+bash-jobs: 4
+prune:
+  - examples/a/**
+  - examples/b/**
+template-data:
+  owner: team
+  image: alpine
+`), 0o600))
+
+	file, err := config.LoadFile(location)
+	require.NoError(t, err)
+	require.Equal(t, []string{"examples", "suites"}, file.Args())
+	require.Equal(t, map[string][]string{
+		"bash":          {"true"},
+		"bash-jobs":     {"4"},
+		"prune":         {"examples/a/**", "examples/b/**"},
+		"template-data": {"image=alpine", "owner=team"},
+	}, file.Flags)
+
+	for _, content := range []string{"input: [a]", "match:", "prune: [[a]]", "{"} {
+		require.NoError(t, os.WriteFile(location, []byte(content), 0o600))
+		_, err = config.LoadFile(location)
+		require.Error(t, err, content)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the config file discovered in the input dir
+const FileName = ".gotestmd.yaml"
+
+// Keys of the config file used instead of the arguments
+const (
+	InputKey   = "input"
+	OutputKey  = "output"
+	BasePkgKey = "base-pkg"
+)
+
+// File is an optional config file with the arguments and the flags of the command
+type File struct {
+	Path    string
+	Input   string
+	Output  string
+	BasePkg string
+	// Flags contains values of the flags by their names. Lists and maps are split into values passed to the flag one by one
+	Flags map[string][]string
+}
+
+// LoadFile loads the config file. All keys except the arguments are names of the flags
+func LoadFile(path string) (*File, error) {
+	// #nosec
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read config file %v", path)
+	}
+	var values map[string]interface{}
+	if err = yaml.Unmarshal(content, &values); err != nil {
+		return nil, errors.Wrapf(err, "invalid config file %v", path)
+	}
+	var result = &File{Path: path, Flags: map[string][]string{}}
+	for key, value := range values {
+		args := map[string]*string{InputKey: &result.Input, OutputKey: &result.Output, BasePkgKey: &result.BasePkg}
+		if arg, ok := args[key]; ok {
+			s, ok := value.(string)
+			if !ok {
+				return nil, errors.Errorf("invalid %v in config file %v, expected a string", key, path)
+			}
+			*arg = s
+			continue
+		}
+		if result.Flags[key], err = flagValues(value); err != nil {
+			return nil, errors.Wrapf(err, "invalid %v in config file %v", key, path)
+		}
+	}
+	return result, nil
+}
+
+// Args returns INPUT_DIR OUTPUT_DIR [BASE_PKG] arguments set in the file
+func (f *File) Args() []string {
+	var result = []string{f.Input, f.Output, f.BasePkg}
+	for len(result) > 0 && result[len(result)-1] == "" {
+		result = result[:len(result)-1]
+	}
+	return result
+}
+
+// flagValues converts a YAML value to the values of a flag: a list is converted item by item, a map to sorted KEY=VALUE items
+func flagValues(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, errors.New("empty value")
+	case []interface{}:
+		var result []string
+		for _, item := range v {
+			if !isScalar(item) {
+				return nil, errors.Errorf("unexpected list item %v", item)
+			}
+			result = append(result, fmt.Sprint(item))
+		}
+		return result, nil
+	case map[string]interface{}:
+		var result []string
+		for key, item := range v {
+			if !isScalar(item) {
+				return nil, errors.Errorf("unexpected value of %v", key)
+			}
+			result = append(result, fmt.Sprintf("%v=%v", key, item))
+		}
+		sort.Strings(result)
+		return result, nil
+	}
+	if !isScalar(value) {
+		return nil, errors.Errorf("unexpected value %v", value)
+	}
+	return []string{fmt.Sprint(value)}, nil
+}
+
+func isScalar(value interface{}) bool {
+	switch value.(type) {
+	case string, bool, int, float64:
+		return true
+	}
+	return false
+}
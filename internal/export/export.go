@@ -0,0 +1,237 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package export converts linked examples to a stable JSON model for external tools
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
+)
+
+// Version is the version of the JSON schema. It's increased on incompatible changes only
+const Version = 1
+
+// File is the default name of the exported file
+const File = "examples.json"
+
+// Sections of the blocks
+const (
+	SectionRun        = "run"
+	SectionCleanup    = "cleanup"
+	SectionBeforeEach = "before-each"
+	SectionAfterEach  = "after-each"
+)
+
+// sections are the sections of the blocks by the sections of the examples
+var sections = map[string]string{
+	parser.SectionRun:        SectionRun,
+	parser.SectionCleanup:    SectionCleanup,
+	parser.SectionBeforeEach: SectionBeforeEach,
+	parser.SectionAfterEach:  SectionAfterEach,
+}
+
+// Model is the root of the exported JSON
+type Model struct {
+	Version  int        `json:"version"`
+	Examples []*Example `json:"examples"`
+}
+
+// Example is an exported example with its blocks, directives and resolved dependencies
+type Example struct {
+	// Name is the name of the example relative to the main input dir. The root example has the "" name
+	Name       string      `json:"name"`
+	Dir        string      `json:"dir"`
+	Root       string      `json:"root"`
+	Source     string      `json:"source,omitempty"`
+	Hash       string      `json:"hash,omitempty"`
+	Remote     *Remote     `json:"remote,omitempty"`
+	Directives *Directives `json:"directives"`
+	Blocks     []*Block    `json:"blocks"`
+	// Leaf examples are generated as tests of their parents
+	Leaf         bool          `json:"leaf"`
+	Dependencies *Dependencies `json:"dependencies"`
+}
+
+// Remote is a reference to an example located in another Go module
+type Remote struct {
+	Module  string `json:"module"`
+	Path    string `json:"path"`
+	Version string `json:"version,omitempty"`
+}
+
+// Directives are the front matter fields of an example
+type Directives struct {
+	ID             string            `json:"id,omitempty"`
+	Priority       int               `json:"priority,omitempty"`
+	Parallel       bool              `json:"parallel,omitempty"`
+	Timeout        string            `json:"timeout,omitempty"`
+	CommandTimeout string            `json:"commandTimeout,omitempty"`
+	SkipUnlessEnv  string            `json:"skipUnlessEnv,omitempty"`
+	SoftFail       bool              `json:"softFail,omitempty"`
+	BuildTags      []string          `json:"buildTags,omitempty"`
+	Version        string            `json:"version,omitempty"`
+	Constraints    []string          `json:"constraints,omitempty"`
+	Tags           []string          `json:"tags,omitempty"`
+	Retry          *Retry            `json:"retry,omitempty"`
+	ExitCode       string            `json:"exitCode,omitempty"`
+	Env            map[string]string `json:"env,omitempty"`
+	Artifacts      map[string]string `json:"artifacts,omitempty"`
+	Image          string            `json:"image,omitempty"`
+	Isolate        *Isolate          `json:"isolate,omitempty"`
+}
+
+// Isolate sets the state checked and restored after each test of a suite
+type Isolate struct {
+	Env        bool     `json:"env,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// Retry sets how the failed commands of Run blocks are retried. Empty durations are the defaults of the runner
+type Retry struct {
+	Timeout     string  `json:"timeout,omitempty"`
+	Interval    string  `json:"interval,omitempty"`
+	Backoff     float64 `json:"backoff,omitempty"`
+	MaxInterval string  `json:"maxInterval,omitempty"`
+	Until       string  `json:"until,omitempty"`
+}
+
+// Block is a bash block of a section of an example
+type Block struct {
+	Section string `json:"section"`
+	// Line is the line of the first command of the block in the source
+	Line   int    `json:"line,omitempty"`
+	Script string `json:"script"`
+	// Shell is the shell set by the shell=NAME attribute of the block. The block is run by bash if it's empty
+	Shell string `json:"shell,omitempty"`
+}
+
+// Dependencies are the resolved links of an example. All values are names of examples
+type Dependencies struct {
+	Includes         []string `json:"includes"`
+	Requires         []string `json:"requires"`
+	OptionalRequires []string `json:"optionalRequires"`
+	// RequiredTests are leaf examples whose steps run before the example
+	RequiredTests []string `json:"requiredTests"`
+	Parents       []string `json:"parents"`
+	// Setup are the suites to set up before the example in order
+	Setup []string `json:"setup"`
+}
+
+// New converts the linked examples to the model. Examples are sorted by name
+func New(examples []*linker.LinkedExample) *Model {
+	var result = &Model{Version: Version, Examples: []*Example{}}
+	for _, e := range examples {
+		result.Examples = append(result.Examples, newExample(e))
+	}
+	sort.Slice(result.Examples, func(i, j int) bool {
+		return result.Examples[i].Name < result.Examples[j].Name
+	})
+	return result
+}
+
+// Write writes the model of the linked examples as indented JSON
+func Write(w io.Writer, examples []*linker.LinkedExample) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(New(examples))
+}
+
+func newExample(e *linker.LinkedExample) *Example {
+	var result = &Example{
+		Name:   e.Name,
+		Dir:    e.Dir,
+		Root:   e.Root,
+		Source: e.Source,
+		Hash:   e.Hash,
+		Directives: &Directives{
+			ID:            e.ID,
+			Priority:      e.Priority,
+			Parallel:      e.Parallel,
+			SkipUnlessEnv: e.SkipUnlessEnv,
+			SoftFail:      e.SoftFail,
+			BuildTags:     e.BuildTags,
+			Version:       e.FrontMatter.Version,
+			Constraints:   e.Constraints,
+			Tags:          e.Tags,
+			ExitCode:      e.ExitCode,
+			Env:           e.Env,
+			Artifacts:     e.Artifacts,
+			Image:         e.Image,
+		},
+		Blocks: []*Block{},
+		Leaf:   e.IsLeaf(),
+		Dependencies: &Dependencies{
+			Includes:         names(e.Children),
+			Requires:         copyStrings(e.Requires),
+			OptionalRequires: copyStrings(e.OptionalRequires),
+			RequiredTests:    names(e.RequiredTests),
+			Parents:          names(e.Parents),
+			Setup:            copyStrings(e.Dependencies()),
+		},
+	}
+	if e.Timeout != 0 {
+		result.Directives.Timeout = e.Timeout.String()
+	}
+	if e.CommandTimeout != 0 {
+		result.Directives.CommandTimeout = e.CommandTimeout.String()
+	}
+	if e.Retry != nil {
+		result.Directives.Retry = &Retry{
+			Timeout:     optionalDuration(e.Retry.Timeout),
+			Interval:    optionalDuration(e.Retry.Interval),
+			Backoff:     e.Retry.Backoff,
+			MaxInterval: optionalDuration(e.Retry.MaxInterval),
+			Until:       e.Retry.Until,
+		}
+	}
+	if e.Isolate != nil {
+		result.Directives.Isolate = &Isolate{Env: e.Isolate.Env, Namespaces: copyStrings(e.Isolate.Namespaces)}
+	}
+	if e.Remote != nil {
+		result.Remote = &Remote{Module: e.Remote.Module, Path: e.Remote.Path, Version: e.Remote.Version}
+	}
+	for _, b := range e.Blocks() {
+		result.Blocks = append(result.Blocks, &Block{Section: sections[b.Section], Line: b.Pos.Line, Script: b.Script, Shell: b.Shell})
+	}
+	return result
+}
+
+// optionalDuration returns the duration as a string or an empty string for zero duration
+func optionalDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
+
+func names(examples []*linker.LinkedExample) []string {
+	var result = []string{}
+	for _, e := range examples {
+		result = append(result, e.Name)
+	}
+	return result
+}
+
+// copyStrings returns a non-nil copy of the slice, so empty lists are exported as [] instead of null
+func copyStrings(values []string) []string {
+	return append([]string{}, values...)
+}
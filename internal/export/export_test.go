@@ -0,0 +1,83 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/gotestmd/internal/export"
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
+)
+
+func TestWrite(t *testing.T) {
+	base := &parser.Example{Dir: "examples/base"}
+	base.AddBlock(&parser.Block{Section: parser.SectionRun, Pos: parser.Position{Line: 7}, Script: "kubectl apply -f base.yaml"})
+	base.Timeout = 10 * time.Minute
+	base.CommandTimeout = 2 * time.Minute
+	base.Retry = &parser.Retry{Timeout: 5 * time.Minute, Until: "Running"}
+	base.ExitCode = parser.NonZeroExitCode
+	base.Env = map[string]string{"NAMESPACE": "ns-1"}
+	base.Artifacts = map[string]string{"pods": "kubectl get pods -A"}
+	base.Image = "golang:1.20"
+	suite := &parser.Example{Dir: "examples/suite", Includes: []string{"leaf"}, Requires: []string{"../base"}}
+	suite.AddBlock(&parser.Block{Section: parser.SectionCleanup, Pos: parser.Position{Line: 12}, Script: "kubectl delete ns suite"})
+	suite.Isolate = &parser.Isolate{Env: true, Namespaces: []string{"suite"}}
+	leaf := &parser.Example{Dir: "examples/suite/leaf"}
+	leaf.AddBlock(&parser.Block{Section: parser.SectionRun, Pos: parser.Position{Line: 3}, Script: "echo leaf"})
+	examples, err := linker.New("examples/").Link(base, suite, leaf)
+	require.NoError(t, err)
+
+	var sb strings.Builder
+	require.NoError(t, export.Write(&sb, examples))
+
+	var model export.Model
+	require.NoError(t, json.Unmarshal([]byte(sb.String()), &model))
+	require.Equal(t, export.Version, model.Version)
+	require.Len(t, model.Examples, 3)
+
+	require.Equal(t, "base", model.Examples[0].Name)
+	require.Equal(t, "10m0s", model.Examples[0].Directives.Timeout)
+	require.Equal(t, "2m0s", model.Examples[0].Directives.CommandTimeout)
+	require.Equal(t, &export.Retry{Timeout: "5m0s", Until: "Running"}, model.Examples[0].Directives.Retry)
+	require.Equal(t, "non-zero", model.Examples[0].Directives.ExitCode)
+	require.Equal(t, map[string]string{"NAMESPACE": "ns-1"}, model.Examples[0].Directives.Env)
+	require.Equal(t, map[string]string{"pods": "kubectl get pods -A"}, model.Examples[0].Directives.Artifacts)
+	require.Equal(t, "golang:1.20", model.Examples[0].Directives.Image)
+	require.Equal(t, []*export.Block{{Section: export.SectionRun, Line: 7, Script: "kubectl apply -f base.yaml"}}, model.Examples[0].Blocks)
+
+	suiteModel := model.Examples[1]
+	require.Equal(t, "suite", suiteModel.Name)
+	require.False(t, suiteModel.Leaf)
+	require.Equal(t, []string{"suite/leaf"}, suiteModel.Dependencies.Includes)
+	require.Equal(t, []string{"base"}, suiteModel.Dependencies.Requires)
+	require.Equal(t, []string{"base"}, suiteModel.Dependencies.Setup)
+	require.Equal(t, export.SectionCleanup, suiteModel.Blocks[0].Section)
+	require.Equal(t, &export.Isolate{Env: true, Namespaces: []string{"suite"}}, suiteModel.Directives.Isolate)
+	require.Nil(t, model.Examples[0].Directives.Isolate)
+
+	leafModel := model.Examples[2]
+	require.Equal(t, "suite/leaf", leafModel.Name)
+	require.True(t, leafModel.Leaf)
+	require.Equal(t, []string{"suite"}, leafModel.Dependencies.Parents)
+	require.Contains(t, sb.String(), `"requiredTests": []`)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// bashSteps returns the blocks of the bash body each preceded by the log_step call with the location of the block in
+// the section of the source
+func (b Body) bashSteps(source Source, section string, lines []int) Body {
+	var result Body
+	for i, block := range b {
+		result = append(result, "log_step "+bashQuote(b.stepLocation(i, source, section, lines))+" "+bashQuote(block), block)
+	}
+	return result
+}
+
+// stepLocation returns the location of the i-th block in the section of the source printed before running it
+func (b Body) stepLocation(i int, source Source, section string, lines []int) string {
+	location := source.Path
+	if len(lines) == len(b) {
+		location += ":" + strconv.Itoa(lines[i])
+	}
+	return strings.TrimSpace(location + " " + section)
+}
+
+// bashQuote returns the string as a single-quoted bash word
+func bashQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// bashScriptDir is the variable of the bash scripts keeping their dir
+const bashScriptDir = "gotestmd_dir"
+
+// bashCd returns the command changing the dir. The dir is relative to the dir of the script, so the scripts can be run
+// in any checkout of the repository
+func bashCd(scriptDir, dir string) string {
+	absScriptDir, _ := filepath.Abs(scriptDir)
+	absDir, _ := filepath.Abs(dir)
+	rel, err := filepath.Rel(absScriptDir, absDir)
+	if err != nil {
+		return "cd " + bashQuote(absDir)
+	}
+	return `cd "$` + bashScriptDir + `"/` + bashQuote(filepath.ToSlash(rel))
+}
+
+// bashEcho returns the command printing the message
+func bashEcho(message string) string {
+	return "echo " + bashQuote(message)
+}
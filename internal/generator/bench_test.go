@@ -0,0 +1,126 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/networkservicemesh/gotestmd/internal/config"
+	"github.com/networkservicemesh/gotestmd/internal/generator"
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
+)
+
+// benchmarkSuite returns the suite with the tests requiring another suite
+func benchmarkSuite(b *testing.B) *generator.Suite {
+	const tests = 20
+	var examples = []*parser.Example{
+		{Dir: "examples/producer", Run: []string{"echo producer"}, Cleanup: []string{"echo cleanup"}},
+		{Dir: "examples/consumer", Requires: []string{"../producer"}, Run: []string{"echo consumer"}, Cleanup: []string{"echo cleanup"}},
+	}
+	for i := 0; i < tests; i++ {
+		test := fmt.Sprintf("test%v", i)
+		examples[1].Includes = append(examples[1].Includes, test)
+		examples = append(examples, &parser.Example{Dir: "examples/consumer/" + test, Run: []string{"echo " + test, "echo done"}})
+	}
+	linked, err := linker.New("examples/").Link(examples...)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, suite := range generate(b, generator.New(config.Config{
+		OutputDir:    "suites",
+		BasePkg:      "example.com/base",
+		ImportPrefix: "example.com/suites",
+	}), linked...) {
+		if len(suite.Tests) == tests {
+			return suite
+		}
+	}
+	b.Fatal("no suite with tests")
+	return nil
+}
+
+func BenchmarkSuiteWriteTo(b *testing.B) {
+	suite := benchmarkSuite(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := suite.WriteTo(io.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTestWriteTo(b *testing.B) {
+	test := benchmarkSuite(b).Tests[0]
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := test.WriteTo(io.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSuiteBashString(b *testing.B) {
+	suite := benchmarkSuite(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := suite.BashString(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkLargeBlock generates and formats the suite applying a manifest of n lines
+func BenchmarkLargeBlock(b *testing.B) {
+	for _, n := range []int{1000, 10000, 100000} {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			var lines []string
+			for i := 0; i < n; i++ {
+				lines = append(lines, fmt.Sprintf("  key%v: value%v", i, i))
+			}
+			linked, err := linker.New("examples/").Link(&parser.Example{
+				Dir: "examples/manifest",
+				Run: []string{"kubectl apply -f - <<EOF\n" + strings.Join(lines, "\n") + "\nEOF"},
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+			suite := generate(b, generator.New(config.Config{
+				OutputDir:    "suites",
+				BasePkg:      "example.com/base",
+				ImportPrefix: "example.com/suites",
+			}), linked...)[0]
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				var source strings.Builder
+				if _, err := suite.WriteTo(&source); err != nil {
+					b.Fatal(err)
+				}
+				if _, err := generator.Format(suite.Location, source.String()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
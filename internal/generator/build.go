@@ -0,0 +1,83 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"go/build/constraint"
+	"sort"
+)
+
+// BuildConstraint returns the go:build expression of the suite or an empty string if there are no build tags.
+// It also includes the tags of the tests and of all suites the suite imports, as the package can't be built without them
+func (s *Suite) BuildConstraint() string {
+	var tags = map[string]struct{}{}
+	s.collectBuildTags(tags, map[*Suite]bool{})
+
+	var sorted []string
+	for tag := range tags {
+		sorted = append(sorted, tag)
+	}
+	sort.Strings(sorted)
+
+	var result constraint.Expr
+	for _, tag := range sorted {
+		expr, err := constraint.Parse("//go:build " + tag)
+		if err != nil {
+			// Tags are validated by the parser and the config
+			continue
+		}
+		if result == nil {
+			result = expr
+			continue
+		}
+		result = &constraint.AndExpr{X: result, Y: expr}
+	}
+	if result == nil {
+		return ""
+	}
+	return result.String()
+}
+
+func (s *Suite) collectBuildTags(tags map[string]struct{}, visited map[*Suite]bool) {
+	if s == nil || visited[s] {
+		return
+	}
+	visited[s] = true
+	for _, tag := range s.BuildTags {
+		tags[tag] = struct{}{}
+	}
+	for _, t := range append(append([]*Test(nil), s.RequiredTests...), s.Tests...) {
+		for _, tag := range t.BuildTags {
+			tags[tag] = struct{}{}
+		}
+	}
+	for _, child := range s.Children {
+		child.collectBuildTags(tags, visited)
+	}
+	for _, parent := range s.Parents {
+		parent.collectBuildTags(tags, visited)
+	}
+}
+
+// goHeader returns the sources of the suite followed by its go:build constraint
+func (s *Suite) goHeader() string {
+	header := s.header("//")
+	if c := s.BuildConstraint(); c != "" {
+		header += "\n//go:build " + c + "\n"
+	}
+	return header
+}
@@ -18,6 +18,7 @@ package generator
 
 import (
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -29,17 +30,47 @@ func (d Dependency) Pkg() string {
 	return string(d)
 }
 
-// Name returns pkg name
+// Name returns pkg name. Names starting with a digit get the _ prefix
 func (d Dependency) Name() string {
 	_, name := filepath.Split(d.Pkg())
-	return normalizeName(name)
+	return withDigitPrefix("_", normalizeName(name))
 }
 
 // Dependencies represent an array of Dependency
 type Dependencies []Dependency
 
-// FieldsString returns a string that contains a declaration of suite dependencies as fields
-func (d Dependencies) FieldsString() string {
+func (d Dependencies) unique() Dependencies {
+	var result Dependencies
+	var visited = map[Dependency]bool{}
+	for _, dep := range d {
+		if !visited[dep] {
+			visited[dep] = true
+			result = append(result, dep)
+		}
+	}
+	return result
+}
+
+func (d Dependencies) contains(dep Dependency) bool {
+	for _, item := range d {
+		if item == dep {
+			return true
+		}
+	}
+	return false
+}
+
+func (d Dependencies) sorted() Dependencies {
+	result := append(Dependencies(nil), d...)
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+	return result
+}
+
+// FieldsString returns a string that contains a declaration of suite dependencies as fields.
+// The first dependency is the base package, its baseType is embedded
+func (d Dependencies) FieldsString(baseType string) string {
 	var result strings.Builder
 	for i := 0; i < len(d); i++ {
 		if i != 0 {
@@ -47,7 +78,12 @@ func (d Dependencies) FieldsString() string {
 			_, _ = result.WriteString("Suite ")
 		}
 		_, _ = result.WriteString(d[i].Name())
-		_, _ = result.WriteString(".Suite")
+		if i == 0 {
+			_, _ = result.WriteString(".")
+			_, _ = result.WriteString(baseType)
+		} else {
+			_, _ = result.WriteString(".Suite")
+		}
 		if i+1 < len(d) {
 			_, _ = result.WriteString("\n")
 		}
@@ -56,26 +92,31 @@ func (d Dependencies) FieldsString() string {
 	return result.String()
 }
 
-// SetupString returns a string that contains a declaration of suite dependencies as part of setup function
-func (d Dependencies) SetupString() string {
+// SetupString returns a string that contains a declaration of suite dependencies as part of setup function.
+// The first dependency is the base package, its embedded baseType is set up first
+func (d Dependencies) SetupString(baseType string, plainBase bool) string {
 	if len(d) == 0 {
 		return ""
 	}
+	var fields []string
+	for _, dep := range d[1:] {
+		fields = append(fields, "&s."+dep.Name()+"Suite")
+	}
+	return parentsSetupString(baseType, fields, plainBase)
+}
 
+// parentsSetupString returns the setup of the embedded baseType and the parent suites in the fields. The parents are
+// shared between all suites that require them unless the base is a plain testify suite without SetupParents
+func parentsSetupString(baseType string, fields []string, plainBase bool) string {
 	var result strings.Builder
 
-	result.WriteString("parents := []interface{}{")
-	result.WriteString("&s.Suite")
-	for i := 1; i < len(d); i++ {
-		if i < len(d) {
-			result.WriteString(",")
-		}
-		result.WriteString("&s.")
-		result.WriteString(d[i].Name())
-		result.WriteString("Suite")
+	parents := []string{"&s." + baseType}
+	if plainBase {
+		parents = append(parents, fields...)
 	}
+	result.WriteString("parents := []interface{}{")
+	result.WriteString(strings.Join(parents, ", "))
 	result.WriteString("}\n")
-
 	result.WriteString(`for _, p := range parents {
 		if v, ok := p.(suite.TestingSuite); ok {
 			v.SetT(s.T())
@@ -86,6 +127,12 @@ func (d Dependencies) SetupString() string {
 	}
 `)
 
+	if !plainBase && len(fields) > 0 {
+		result.WriteString("s.SetupParents(")
+		result.WriteString(strings.Join(fields, ", "))
+		result.WriteString(")\n")
+	}
+
 	return result.String()
 }
 
@@ -0,0 +1,103 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Names of the files running the bash suites in containers
+const (
+	DockerComposeFile      = "docker-compose.yml"
+	DockerEntrypointScript = "docker-entrypoint.sh"
+)
+
+// dockerWorkDir is the dir the repo is mounted at in the containers
+const dockerWorkDir = "/work"
+
+// DockerComposeString returns docker-compose.yml with a service per suite running its suite.sh in the image. The repo
+// dir is mounted at /work by its path relative to docker-compose.yml, so the file works in any checkout of the repo
+func (g *Generator) DockerComposeString(image, repoDir string, suites []*Suite) (string, error) {
+	type service struct {
+		Name       string
+		Entrypoint string
+	}
+	repoDir, err := filepath.Abs(repoDir)
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot get the absolute path of %v", repoDir)
+	}
+	var services []service
+	for _, s := range suites {
+		entrypoint, err := relDir(repoDir, filepath.Join(s.BashDir(), DockerEntrypointScript))
+		if err != nil {
+			return "", err
+		}
+		if strings.HasPrefix(entrypoint, "../") {
+			return "", errors.Errorf("suite %v is outside of the mounted dir %v", s.Name(), repoDir)
+		}
+		services = append(services, service{Name: g.suiteID(s), Entrypoint: entrypoint})
+	}
+	mount, err := relDir(filepath.Join(repoDir, g.conf.OutputDir), repoDir)
+	if err != nil {
+		return "", err
+	}
+
+	return g.templates.executeString(DockerComposeTemplateName, struct {
+		File     string
+		Image    string
+		Mount    string
+		WorkDir  string
+		Services []service
+	}{
+		File:     filepath.ToSlash(filepath.Join(g.conf.OutputDir, DockerComposeFile)),
+		Image:    image,
+		Mount:    mount,
+		WorkDir:  dockerWorkDir,
+		Services: services,
+	})
+}
+
+// relDir returns the path of target relative to base starting with ./ or ../ as compose expects from relative paths
+func relDir(base, target string) (string, error) {
+	target, err := filepath.Abs(target)
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot get the absolute path of %v", target)
+	}
+	rel, err := filepath.Rel(base, target)
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot get the path of %v relative to %v", target, base)
+	}
+	rel = filepath.ToSlash(rel)
+	if rel != "." && rel != ".." && !strings.HasPrefix(rel, "../") {
+		rel = "./" + rel
+	}
+	return rel, nil
+}
+
+// DockerEntrypointString returns docker-entrypoint.sh running suite.sh of the suite in a container
+func (s *Suite) DockerEntrypointString() (string, error) {
+	return s.templates.executeString(DockerEntrypointTemplateName, struct {
+		Header string
+		Name   string
+	}{
+		Header: s.header("#"),
+		Name:   s.scriptName(),
+	})
+}
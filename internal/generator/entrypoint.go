@@ -0,0 +1,84 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/networkservicemesh/gotestmd/internal/config"
+)
+
+// markEntrypoints selects suites that get entrypoint tests
+func (g *Generator) markEntrypoints(suites []*Suite) {
+	var included = map[*Suite]bool{}
+	for _, s := range suites {
+		for _, child := range s.Children {
+			included[child] = true
+		}
+	}
+	for _, s := range suites {
+		switch g.conf.Entrypoints {
+		case config.EntrypointsRoots:
+			s.Entrypoint = !included[s]
+		case config.EntrypointsLeaves:
+			s.Entrypoint = len(s.Children) == 0
+		case config.EntrypointsAll:
+			s.Entrypoint = true
+		}
+	}
+}
+
+// EntrypointLocation returns the path of the test file running the suite
+func (s *Suite) EntrypointLocation() string {
+	if s.Package != "" {
+		return strings.TrimSuffix(s.Location, ".go") + "_test.go"
+	}
+	return filepath.Join(filepath.Dir(s.Location), "suite.gen_test.go")
+}
+
+// entrypointTitle returns the name of the entrypoint test without the Test prefix. Entrypoints of the suites from a single
+// package are named by the unique suite types
+func (s *Suite) entrypointTitle() string {
+	if s.Package != "" {
+		return strings.TrimSuffix(s.Type, "Suite")
+	}
+	return s.Title()
+}
+
+// EntrypointString returns a test file running the suite generated in the format
+func (s *Suite) EntrypointString(format string) (string, error) {
+	return writeString(func(w io.Writer) error { return s.WriteEntrypoint(w, format) })
+}
+
+// WriteEntrypoint writes a test file running the suite generated in the format into w
+func (s *Suite) WriteEntrypoint(w io.Writer, format string) error {
+	return s.templates.execute(&squashWriter{w: w}, EntrypointTemplateName, struct {
+		Header string
+		Name   string
+		Type   string
+		Title  string
+		Format string
+	}{
+		Header: s.goHeader(),
+		Name:   s.packageName(),
+		Type:   s.typeName(),
+		Title:  s.entrypointTitle(),
+		Format: format,
+	})
+}
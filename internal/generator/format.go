@@ -0,0 +1,59 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"io"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/imports"
+
+	"github.com/networkservicemesh/gotestmd/internal/config"
+)
+
+// declEndRegex matches the end of a top-level declaration followed by the next one
+var declEndRegex = regexp.MustCompile("\n}\n([^\n])")
+
+// Format formats generated Go source like gofmt and groups and sorts its imports like goimports.
+// Returns an error with the position of the problem if the source doesn't parse
+func Format(filename, source string) (string, error) {
+	result, err := imports.Process(filename, []byte(source), &imports.Options{
+		Comments:   true,
+		TabIndent:  true,
+		TabWidth:   8,
+		FormatOnly: true,
+	})
+	if err != nil {
+		return "", errors.Errorf("generated code is not valid Go: %v", err.Error())
+	}
+	// Templates are squashed, so top-level declarations are separated by blank lines here
+	return declEndRegex.ReplaceAllString(string(result), "\n}\n\n$1"), nil
+}
+
+// WriteFormat writes the unformatted Go code of the suite in the format into w
+func (s *Suite) WriteFormat(w io.Writer, format string) error {
+	switch format {
+	case config.FormatTesting:
+		return s.writeTesting(w)
+	case config.FormatGinkgo:
+		return s.writeGinkgo(w)
+	default:
+		_, err := s.WriteTo(w)
+		return err
+	}
+}
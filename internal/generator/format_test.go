@@ -0,0 +1,51 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/gotestmd/internal/generator"
+)
+
+func TestFormat(t *testing.T) {
+	source := "package a\nimport(\n\"strings\"\n\"fmt\"\n)\nfunc A() {\nfmt.Println(strings.ToUpper(`a`))\n}\nfunc B() {}"
+
+	result, err := generator.Format("a.go", source)
+	require.NoError(t, err)
+	require.Equal(t, `package a
+
+import (
+	"fmt"
+	"strings"
+)
+
+func A() {
+	fmt.Println(strings.ToUpper(`+"`a`"+`))
+}
+
+func B() {}
+`, result)
+}
+
+func TestFormatInvalidSource(t *testing.T) {
+	_, err := generator.Format("a.go", "package a\nfunc A() {\n")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "a.go:2:12")
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/networkservicemesh/gotestmd/internal/version"
+)
+
+// defaultFuncs returns the functions available in all templates. data values are returned by the data function, version
+// returns the version of gotestmd
+func defaultFuncs(data map[string]string) template.FuncMap {
+	return template.FuncMap{
+		"env":        os.Getenv,
+		"data":       func(key string) string { return data[key] },
+		"base":       path.Base,
+		"dir":        path.Dir,
+		"join":       path.Join,
+		"lower":      strings.ToLower,
+		"upper":      strings.ToUpper,
+		"replace":    strings.ReplaceAll,
+		"trimPrefix": strings.TrimPrefix,
+		"trimSuffix": strings.TrimSuffix,
+		"quote":      strconv.Quote,
+		"version":    func() string { return version.Get().Version },
+	}
+}
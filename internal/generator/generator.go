@@ -23,70 +23,180 @@ package generator
 import (
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
+	"text/template"
+	"time"
 
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
+	"github.com/sirupsen/logrus"
 
 	"github.com/networkservicemesh/gotestmd/internal/config"
-	"github.com/networkservicemesh/gotestmd/internal/linker"
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
 )
 
 // Generator can generate suites from the slice of linker.LinedExample
 type Generator struct {
-	conf config.Config
+	conf      config.Config
+	modules   map[string]string
+	templates *templateSet
+	// root is the module root of the input dir. Sources and dirs of the generated code are relative to it
+	root string
+}
+
+// Option is an option for the Generator
+type Option func(g *Generator)
+
+// WithTemplates overrides the built-in templates
+func WithTemplates(templates Templates) Option {
+	return func(g *Generator) {
+		g.templates.overrides = templates
+	}
+}
+
+// WithFuncs adds functions available in the templates. They override the default functions with the same names
+func WithFuncs(funcs template.FuncMap) Option {
+	return func(g *Generator) {
+		for name, f := range funcs {
+			g.templates.funcs[name] = f
+		}
+	}
+}
+
+// WithRoot sets the dir the sources and the dirs of the generated code are relative to. It's the module root of the
+// input dir by default
+func WithRoot(root string) Option {
+	return func(g *Generator) {
+		g.root = root
+	}
 }
 
 // New creates new Generator instance
-func New(conf config.Config) *Generator {
-	return &Generator{
-		conf: conf,
+func New(conf config.Config, options ...Option) *Generator {
+	if conf.BaseType == "" {
+		conf.BaseType = "Suite"
+	}
+	if conf.Naming.Case == "" {
+		conf.Naming = config.DefaultNaming()
+	}
+	g := &Generator{
+		conf:      conf,
+		modules:   map[string]string{},
+		templates: &templateSet{funcs: defaultFuncs(conf.TemplateData)},
 	}
+	for _, o := range options {
+		o(g)
+	}
+	if g.root == "" && conf.InputDir != "" {
+		g.root = ModuleRoot(conf.InputDir)
+	}
+	return g
 }
 
-// Generate generates suites based on passed examples
-func (g *Generator) Generate(examples ...*linker.LinkedExample) []*Suite {
+// Generate generates suites based on passed examples. Fails if the templates can't be compiled
+func (g *Generator) Generate(examples ...*linker.LinkedExample) ([]*Suite, error) {
+	if err := g.templates.compile(); err != nil {
+		return nil, err
+	}
 	var result []*Suite
 	var tests = map[string][]*Test{}
 	var index = map[string]*Suite{}
 	var children = map[string][]*Suite{}
-	moduleName := moduleName(g.conf.OutputDir)
+	var examplesIndex = map[string]*linker.LinkedExample{}
+	for _, e := range examples {
+		examplesIndex[e.Name] = e
+	}
 	for _, e := range examples {
 		if e.IsLeaf() {
-			_, name := path.Split(e.Name)
+			if len(e.BeforeEach)+len(e.AfterEach) > 0 {
+				logrus.Warnf("example %v has no tests, its Before each and After each sections are ignored", e.Dir)
+			}
 			for _, parent := range e.Parents {
-				tests[parent.Name] = append(tests[parent.Name], &Test{
-					Dir:     e.Dir,
-					Name:    cases.Title(language.AmericanEnglish).String(nameRegex.ReplaceAllString(name, "_")),
-					Cleanup: e.Cleanup,
-					Run:     e.Run,
-				})
+				test := g.newTest(e)
+				test.Parallel = e.Parallel || g.conf.Parallel
+				if test.Image == "" {
+					test.Image = containerImage(parent)
+				}
+				tests[parent.Name] = append(tests[parent.Name], test)
 			}
 			continue
 		}
 
 		// Dependencies to import
 		var deps = Dependencies([]Dependency{Dependency(g.conf.BasePkg)})
-		deps = append(deps, normalizeDeps(moduleName, e.Dependencies())...)
+		deps = append(deps, g.dependencies(examplesIndex, e.Dependencies())...)
 
 		// Parent suites to setup first
 		var depsToSetup = Dependencies([]Dependency{Dependency(g.conf.BasePkg)})
-		depsToSetup = append(depsToSetup, normalizeDeps(moduleName, e.ParentDependencies())...)
+		depsToSetup = append(depsToSetup, g.dependencies(examplesIndex, e.ParentDependencies())...)
 
-		location := filepath.Join(g.conf.OutputDir, strings.ToLower(e.Name))
-		if g.conf.Bash {
-			location = filepath.Join(location, "suite.gen.sh")
-		} else {
-			location = filepath.Join(location, "suite.gen.go")
-		}
+		outputDir, name := g.locate(e)
+		location, suiteDir, dependency := g.suiteLocation(e, outputDir, name)
+		blocks := sectionBlocks(e)
 		s := &Suite{
-			Dir:         e.Dir,
-			Location:    location,
-			Dependency:  Dependency(path.Join(g.conf.OutputDir, strings.ToLower(e.Name))),
-			Cleanup:     e.Cleanup,
-			Run:         e.Run,
-			Deps:        deps,
-			DepsToSetup: depsToSetup,
+			Dir:            e.Dir,
+			RunnerDir:      g.relPath(e.Dir),
+			Location:       location,
+			Path:           suiteDir,
+			Dependency:     dependency,
+			Cleanup:        blocks[parser.SectionCleanup].scripts,
+			Run:            blocks[parser.SectionRun].scripts,
+			BeforeEach:     blocks[parser.SectionBeforeEach].scripts,
+			AfterEach:      blocks[parser.SectionAfterEach].scripts,
+			Deps:           deps,
+			DepsToSetup:    depsToSetup,
+			Priority:       e.Priority,
+			BaseType:       g.conf.BaseType,
+			PlainBase:      g.conf.PlainBase,
+			Source:         g.newSource(e),
+			Parallel:       e.Parallel || g.conf.Parallel,
+			BuildTags:      append(append([]string(nil), g.conf.BuildTags...), e.BuildTags...),
+			Timeout:        g.conf.Timeout,
+			CommandTimeout: commandTimeout(g.conf, e),
+			Retry:          e.Retry,
+			ExitCode:       e.ExitCode,
+			Env:            e.Env,
+			Artifacts:      artifacts(g.conf, e),
+			Image:          containerImage(e),
+			Isolate:        e.Isolate,
+			BashJobs:       g.conf.BashJobs,
+			title:          identifier(g.conf.Naming, filepath.Base(e.Dir)),
+			templates:      g.templates,
+		}
+		if e.SkipUnlessEnv != "" {
+			s.SkipUnlessEnv = append(s.SkipUnlessEnv, e.SkipUnlessEnv)
+		}
+		if g.conf.SkipUnlessEnv != "" {
+			s.SkipUnlessEnv = append(s.SkipUnlessEnv, g.conf.SkipUnlessEnv)
+		}
+		if e.Timeout != 0 {
+			s.Timeout = e.Timeout
+		}
+		if e.Remote == nil {
+			s.importPath = g.dependencies(examplesIndex, []string{e.Name})[0]
+		}
+		if g.conf.SinglePackage && e.Remote == nil {
+			s.Type = typeName(g.conf.Naming, name)
+			_, s.Package = filepath.Split(filepath.Clean(outputDir))
+			s.Package = withDigitPrefix("_", normalizeName(s.Package))
+		}
+		if g.conf.LogSteps {
+			s.RunLines, s.CleanupLines = blocks[parser.SectionRun].lines, blocks[parser.SectionCleanup].lines
+			s.BeforeEachLines, s.AfterEachLines = blocks[parser.SectionBeforeEach].lines, blocks[parser.SectionAfterEach].lines
+		}
+		for _, test := range e.RequiredTests {
+			requiredTest := g.newTest(test)
+			if requiredTest.Image == "" {
+				requiredTest.Image = s.Image
+			}
+			s.RequiredTests = append(s.RequiredTests, requiredTest)
+		}
+
+		index[e.Name] = s
+
+		// Suites of remote examples are generated in their own modules
+		if e.Remote != nil {
+			continue
 		}
 
 		// Remember if suite is a subsuite
@@ -94,8 +204,6 @@ func (g *Generator) Generate(examples ...*linker.LinkedExample) []*Suite {
 			children[parent.Name] = append(children[parent.Name], s)
 		}
 		result = append(result, s)
-
-		index[e.Name] = s
 	}
 
 	// Apply tests to the suites
@@ -110,9 +218,210 @@ func (g *Generator) Generate(examples ...*linker.LinkedExample) []*Suite {
 
 	for _, e := range examples {
 		for _, require := range e.Requires {
+			// tests and the examples without suites have no parents to set up
+			if index[e.Name] == nil || index[require] == nil {
+				continue
+			}
 			index[e.Name].Parents = append(index[e.Name].Parents, index[require])
 		}
 	}
 
+	g.markEntrypoints(result)
+	sortSuites(result)
+
+	return result, nil
+}
+
+// sortSuites makes the output independent of the order of examples. The order of parents to set up is kept
+func sortSuites(suites []*Suite) {
+	for _, s := range suites {
+		sort.SliceStable(s.Tests, func(i, j int) bool {
+			return s.Tests[i].Name < s.Tests[j].Name
+		})
+		sort.SliceStable(s.Children, func(i, j int) bool {
+			return s.Children[i].Location < s.Children[j].Location
+		})
+		s.Deps = append(s.Deps[:1], s.Deps[1:].unique().sorted()...)
+		s.DepsToSetup = s.DepsToSetup.unique()
+	}
+	sort.SliceStable(suites, func(i, j int) bool {
+		return suites[i].Location < suites[j].Location
+	})
+}
+
+// suiteLocation returns the file of the suite located by locate, its dir relative to the output dir and its package.
+// Suites of remote examples are generated in their own modules, so they have no file and are named by the reference
+func (g *Generator) suiteLocation(e *linker.LinkedExample, outputDir, name string) (location, suiteDir string, dependency Dependency) {
+	if e.Remote != nil {
+		return "", e.Remote.String(), remoteDependency(e.Remote, g.conf.RemotePrefixes)
+	}
+	if g.conf.SinglePackage {
+		location = filepath.Join(outputDir, singlePackageFile(name))
+	} else {
+		location = filepath.Join(outputDir, strings.ToLower(name), "suite.gen.go")
+	}
+	return location, suitePath(outputDir, location), Dependency(path.Join(outputDir, strings.ToLower(name)))
+}
+
+// suitePath returns the dir of the location relative to the output dir
+func suitePath(outputDir, location string) string {
+	rel, err := filepath.Rel(outputDir, filepath.Dir(location))
+	if err != nil {
+		return filepath.ToSlash(filepath.Dir(location))
+	}
+	return filepath.ToSlash(rel)
+}
+
+// locate returns the output dir for the example and its name relative to the input dir
+func (g *Generator) locate(e *linker.LinkedExample) (outputDir, name string) {
+	for _, root := range g.conf.Roots {
+		if e.Root != root.InputDir {
+			continue
+		}
+		rel, err := filepath.Rel(root.InputDir, e.Dir)
+		if err != nil || rel == "." {
+			rel = ""
+		}
+		return root.OutputDir, rel
+	}
+	return g.conf.OutputDir, e.Name
+}
+
+// dependencies returns packages to import for the passed example names
+func (g *Generator) dependencies(index map[string]*linker.LinkedExample, names []string) Dependencies {
+	var result Dependencies
+	for _, name := range names {
+		if remote, ok := linker.ParseRemote(name); ok {
+			result = append(result, remoteDependency(remote, g.conf.RemotePrefixes))
+			continue
+		}
+		outputDir := g.conf.OutputDir
+		if e, ok := index[name]; ok {
+			outputDir, name = g.locate(e)
+		}
+		if _, ok := g.modules[outputDir]; !ok {
+			g.modules[outputDir] = g.importPath(outputDir)
+		}
+		result = append(result, normalizeDeps(g.modules[outputDir], []string{name})...)
+	}
 	return result
 }
+
+// importPath returns the import path of the output dir
+func (g *Generator) importPath(outputDir string) string {
+	if g.conf.ImportPrefix != "" {
+		rel, err := filepath.Rel(g.conf.OutputDir, outputDir)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return path.Join(g.conf.ImportPrefix, filepath.ToSlash(rel))
+		}
+	}
+	return moduleImportPath(outputDir, g.conf.Module)
+}
+
+func (g *Generator) newTest(e *linker.LinkedExample) *Test {
+	_, name := path.Split(e.Name)
+	blocks := sectionBlocks(e)
+	result := &Test{
+		Dir:            e.Dir,
+		RunnerDir:      g.relPath(e.Dir),
+		Name:           identifier(g.conf.Naming, name),
+		Cleanup:        blocks[parser.SectionCleanup].scripts,
+		Run:            blocks[parser.SectionRun].scripts,
+		Source:         g.newSource(e),
+		BuildTags:      e.BuildTags,
+		Steps:          g.conf.Steps,
+		SoftFail:       e.SoftFail,
+		CommandTimeout: commandTimeout(g.conf, e),
+		Retry:          e.Retry,
+		ExitCode:       e.ExitCode,
+		Env:            e.Env,
+		Artifacts:      artifacts(g.conf, e),
+		Image:          e.Image,
+		templates:      g.templates,
+	}
+	if g.conf.LogSteps {
+		result.RunLines, result.CleanupLines = blocks[parser.SectionRun].lines, blocks[parser.SectionCleanup].lines
+	}
+	return result
+}
+
+// sectionBody is the bash blocks of a section of an example. Lines are set only if the lines of all the blocks are known
+type sectionBody struct {
+	scripts Body
+	lines   []int
+}
+
+// sectionBlocks returns the sections of the example by their names. Blocks having the shell=NAME attribute are run by
+// NAME -c, variables exported by such blocks don't reach the next blocks
+func sectionBlocks(e *linker.LinkedExample) map[string]*sectionBody {
+	var result = map[string]*sectionBody{}
+	var unknownLines = map[string]bool{}
+	for _, name := range []string{parser.SectionRun, parser.SectionCleanup, parser.SectionBeforeEach, parser.SectionAfterEach} {
+		result[name] = &sectionBody{}
+	}
+	for _, b := range e.Blocks() {
+		s := result[b.Section]
+		script := b.Script
+		if b.Shell != "" {
+			script = b.Shell + " -c " + bashQuote(b.Script)
+		}
+		s.scripts = append(s.scripts, script)
+		s.lines = append(s.lines, b.Pos.Line)
+		if b.Pos.Line == 0 {
+			unknownLines[b.Section] = true
+		}
+	}
+	for name := range unknownLines {
+		result[name].lines = nil
+	}
+	return result
+}
+
+// containerImage returns the image of the example or the one of its closest parent having an image
+func containerImage(e *linker.LinkedExample) string {
+	if e.Image != "" {
+		return e.Image
+	}
+	for _, parent := range e.Parents {
+		if image := containerImage(parent); image != "" {
+			return image
+		}
+	}
+	return ""
+}
+
+// artifacts returns the artifacts of the config overridden by the ones of the example
+func artifacts(conf config.Config, e *linker.LinkedExample) map[string]string {
+	if len(conf.Artifacts) == 0 {
+		return e.Artifacts
+	}
+	result := map[string]string{}
+	for _, m := range []map[string]string{conf.Artifacts, e.Artifacts} {
+		for name, cmd := range m {
+			result[name] = cmd
+		}
+	}
+	return result
+}
+
+// mergeMaps returns the entries of base overridden by the ones of m
+func mergeMaps(base, m map[string]string) map[string]string {
+	if len(base) == 0 {
+		return m
+	}
+	result := map[string]string{}
+	for _, values := range []map[string]string{base, m} {
+		for k, v := range values {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// commandTimeout returns the command timeout of the example or the default one
+func commandTimeout(conf config.Config, e *linker.LinkedExample) time.Duration {
+	if e.CommandTimeout != 0 {
+		return e.CommandTimeout
+	}
+	return conf.CommandTimeout
+}
@@ -0,0 +1,1062 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/gotestmd/internal/config"
+	"github.com/networkservicemesh/gotestmd/internal/generator"
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
+)
+
+func link(t *testing.T) []*linker.LinkedExample {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/producer", Run: []string{"echo producer"}},
+		&parser.Example{Dir: "examples/consumer", Requires: []string{"../producer"}, Run: []string{"echo consumer"}},
+	)
+	require.NoError(t, err)
+	return examples
+}
+
+// goSource returns the unformatted testify suite
+func goSource(t testing.TB, s *generator.Suite) string {
+	var result strings.Builder
+	_, err := s.WriteTo(&result)
+	require.NoError(t, err)
+	return result.String()
+}
+
+func generate(t testing.TB, g *generator.Generator, examples ...*linker.LinkedExample) []*generator.Suite {
+	suites, err := g.Generate(examples...)
+	require.NoError(t, err)
+	return suites
+}
+
+func TestGenerateImportPrefix(t *testing.T) {
+	suites := generate(t, generator.New(config.Config{
+		OutputDir:    t.TempDir(),
+		BasePkg:      "example.com/base",
+		ImportPrefix: "example.com/tests/suites",
+	}), link(t)...)
+
+	require.Len(t, suites, 2)
+	require.Equal(t, generator.Dependencies{"example.com/base", "example.com/tests/suites/producer"}, suites[0].Deps)
+}
+
+func TestGenerateModule(t *testing.T) {
+	moduleDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, "go.mod"), []byte("// tests\nmodule example.com/tests\n"), 0o600))
+
+	suites := generate(t, generator.New(config.Config{
+		OutputDir: filepath.Join(moduleDir, "suites"),
+		BasePkg:   "example.com/base",
+	}), link(t)...)
+	require.Equal(t, generator.Dependency("example.com/tests/suites/producer"), suites[0].Deps[1])
+
+	suites = generate(t, generator.New(config.Config{
+		OutputDir: filepath.Join(moduleDir, "suites"),
+		BasePkg:   "example.com/base",
+		Module:    "example.com/other",
+	}), link(t)...)
+	require.Equal(t, generator.Dependency("example.com/other/suites/producer"), suites[0].Deps[1])
+}
+
+func TestGenerateBaseType(t *testing.T) {
+	suites := generate(t, generator.New(config.Config{
+		OutputDir:    t.TempDir(),
+		BasePkg:      "example.com/suites/base",
+		BaseType:     "E2ESuite",
+		ImportPrefix: "example.com/tests/suites",
+	}), link(t)...)
+
+	source := goSource(t, suites[0])
+	require.Contains(t, source, "base.E2ESuite")
+	require.Contains(t, source, "parents := []interface{}{&s.E2ESuite}")
+}
+
+func TestGenerateTimeout(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/producer", Run: []string{"echo producer"}, FrontMatter: parser.FrontMatter{Timeout: 90 * time.Second}},
+		&parser.Example{Dir: "examples/consumer", Requires: []string{"../producer"}, Run: []string{"echo consumer"}},
+	)
+	require.NoError(t, err)
+
+	suites := generate(t, generator.New(config.Config{
+		OutputDir:    t.TempDir(),
+		BasePkg:      "example.com/base",
+		ImportPrefix: "example.com/tests/suites",
+		Timeout:      30 * time.Minute,
+	}), examples...)
+
+	require.Len(t, suites, 2)
+	require.Contains(t, goSource(t, suites[0]), "ctx, cancel := context.WithTimeout(context.Background(), 30 * time.Minute)\ns.Cleanup(cancel)\ns.SetContext(ctx)")
+	require.Contains(t, goSource(t, suites[1]), "context.WithTimeout(context.Background(), 90 * time.Second)")
+
+	suites = generate(t, generator.New(config.Config{OutputDir: t.TempDir(), BasePkg: "example.com/base", ImportPrefix: "example.com/tests/suites"}), link(t)...)
+	require.NotContains(t, goSource(t, suites[0]), "context")
+}
+
+func TestGenerateCommandTimeout(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/tree", Includes: []string{"leaf", "diag"}, Run: []string{"echo tree"}, Cleanup: []string{"echo cleanup"}},
+		&parser.Example{Dir: "examples/tree/leaf", Run: []string{"echo leaf\necho done"}, FrontMatter: parser.FrontMatter{CommandTimeout: 2 * time.Minute}},
+		&parser.Example{Dir: "examples/tree/diag", Run: []string{"kubectl get pods"}, FrontMatter: parser.FrontMatter{SoftFail: true}},
+	)
+	require.NoError(t, err)
+
+	conf := config.Config{OutputDir: "suites", BasePkg: "example.com/base", ImportPrefix: "example.com/suites"}
+	source := goSource(t, generate(t, generator.New(conf), examples...)[0])
+	require.Contains(t, source, "\"time\"")
+	require.NotContains(t, source, "\"context\"")
+	require.Contains(t, source, "r := s.Runner(\"examples/tree\")\ns.Cleanup(func() {\nr.Run(`echo cleanup`)\n})\nr.Run(`echo tree`)")
+	require.Contains(t, source, "r.RunWithTimeout(`echo leaf`+\"\\n\"+`echo done`, 2 * time.Minute)")
+	require.Contains(t, source, "r.RunSoft(`kubectl get pods`)")
+
+	conf.CommandTimeout = 30 * time.Second
+	source = goSource(t, generate(t, generator.New(conf), examples...)[0])
+	require.Contains(t, source, "s.Cleanup(func() {\nr.RunWithTimeout(`echo cleanup`, 30 * time.Second)\n})\nr.RunWithTimeout(`echo tree`, 30 * time.Second)")
+	require.Contains(t, source, "r.RunWithTimeout(`echo leaf`+\"\\n\"+`echo done`, 2 * time.Minute)")
+	require.Contains(t, source, "r.RunSoftWithTimeout(`kubectl get pods`, 30 * time.Second)\nr.Report()")
+
+	require.NotContains(t, goSource(t, generate(t, generator.New(config.Config{OutputDir: "suites", BasePkg: "example.com/base", ImportPrefix: "example.com/suites"}), link(t)...)[0]), "time")
+
+	conf.CommandTimeout = 0
+	suites := generate(t, generator.New(conf), examples...)
+	_, err = suites[0].TestingString()
+	require.EqualError(t, err, "command-timeout of examples/tree/leaf is supported only by the testify format")
+	_, err = suites[0].GinkgoString()
+	require.Error(t, err)
+}
+
+func TestGenerateRetry(t *testing.T) {
+	retry := &parser.Retry{Timeout: 5 * time.Minute, Interval: time.Second, Backoff: 2, Until: "Running"}
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/tree", Includes: []string{"check"}, Run: []string{"echo tree"}, Cleanup: []string{"echo cleanup"}, FrontMatter: parser.FrontMatter{Retry: retry}},
+		&parser.Example{Dir: "examples/tree/check", Run: []string{"kubectl get pods"}, FrontMatter: parser.FrontMatter{SoftFail: true, CommandTimeout: 10 * time.Second, Retry: &parser.Retry{Until: "uptime."}}},
+	)
+	require.NoError(t, err)
+
+	conf := config.Config{OutputDir: "suites", BasePkg: "example.com/base", ImportPrefix: "example.com/suites"}
+	source := goSource(t, generate(t, generator.New(conf), examples...)[0])
+	require.Contains(t, source, "\"example.com/base\"\n\"github.com/networkservicemesh/gotestmd/pkg/suites/shell\"")
+	require.Contains(t, source, "\"time\"")
+	require.Contains(t, source, "s.Cleanup(func() {\nr.Run(`echo cleanup`)\n})\nr.RunRetry(`echo tree`, shell.WithRetryTimeout(5 * time.Minute), shell.WithInterval(1 * time.Second), shell.WithBackoff(2, 0), shell.WithOutputMatching(\"Running\"))")
+	require.Contains(t, source, "r.RunSoftRetry(`kubectl get pods`, shell.WithOutputMatching(\"uptime.\"), shell.WithAttemptTimeout(10 * time.Second))\nr.Report()")
+
+	conf.BasePkg = "github.com/networkservicemesh/gotestmd/pkg/suites/shell"
+	source = goSource(t, generate(t, generator.New(conf), examples...)[0])
+	require.Equal(t, 1, strings.Count(source, "\"github.com/networkservicemesh/gotestmd/pkg/suites/shell\""))
+
+	suites := generate(t, generator.New(conf), examples...)
+	_, err = suites[0].TestingString()
+	require.EqualError(t, err, "retry of examples/tree is supported only by the testify format")
+	_, err = suites[0].GinkgoString()
+	require.Error(t, err)
+
+	// Durations are rendered in the largest units dividing them
+	retry = &parser.Retry{Timeout: 1500 * time.Microsecond, Interval: 100 * time.Millisecond, Backoff: 2, MaxInterval: 1001 * time.Nanosecond}
+	examples, err = linker.New("examples/").Link(&parser.Example{Dir: "examples/tree", Run: []string{"echo tree"}, FrontMatter: parser.FrontMatter{Retry: retry}})
+	require.NoError(t, err)
+	source = goSource(t, generate(t, generator.New(conf), examples...)[0])
+	require.Contains(t, source, "shell.WithRetryTimeout(1500 * time.Microsecond), shell.WithInterval(100 * time.Millisecond), shell.WithBackoff(2, 1001 * time.Nanosecond)")
+}
+
+func TestGenerateExitCode(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/tree", Includes: []string{"denied"}, Run: []string{"echo tree"}, Cleanup: []string{"echo cleanup"}, FrontMatter: parser.FrontMatter{ExitCode: "2"}},
+		&parser.Example{Dir: "examples/tree/denied", Run: []string{"kubectl get secrets"}, FrontMatter: parser.FrontMatter{ExitCode: parser.NonZeroExitCode, Retry: &parser.Retry{Until: "Forbidden"}}},
+	)
+	require.NoError(t, err)
+
+	conf := config.Config{OutputDir: "suites", BasePkg: "example.com/base", ImportPrefix: "example.com/suites"}
+	source := goSource(t, generate(t, generator.New(conf), examples...)[0])
+	require.Contains(t, source, "\"github.com/networkservicemesh/gotestmd/pkg/suites/shell\"")
+	require.NotContains(t, source, "\"time\"")
+	require.Contains(t, source, "s.Cleanup(func() {\nr.Run(`echo cleanup`)\n})\nr.RunRetry(`echo tree`, shell.WithExpectedExitCode(2))")
+	require.Contains(t, source, "r.RunRetry(`kubectl get secrets`, shell.WithOutputMatching(\"Forbidden\"), shell.WithExpectedFailure())")
+
+	suites := generate(t, generator.New(conf), examples...)
+	_, err = suites[0].TestingString()
+	require.EqualError(t, err, "exit-code of examples/tree is supported only by the testify format")
+	_, err = suites[0].GinkgoString()
+	require.Error(t, err)
+}
+
+func TestGenerateEnv(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/tree", Includes: []string{"leaf"}, Run: []string{"kubectl create ns $NAMESPACE"}, BeforeEach: []string{"echo $NAMESPACE"}, FrontMatter: parser.FrontMatter{Env: map[string]string{"NAMESPACE": "ns-1", "KUBECONFIG": "/tmp/config"}}},
+		&parser.Example{Dir: "examples/tree/leaf", Run: []string{"echo $MESSAGE"}, FrontMatter: parser.FrontMatter{Env: map[string]string{"MESSAGE": "it's \"quoted\""}}},
+	)
+	require.NoError(t, err)
+
+	conf := config.Config{OutputDir: "suites", BasePkg: "example.com/base", ImportPrefix: "example.com/suites"}
+	source := goSource(t, generate(t, generator.New(conf), examples...)[0])
+	require.Contains(t, source, "r := s.Runner(\"examples/tree\")\nr.Setenv(\"KUBECONFIG\", \"/tmp/config\")\nr.Setenv(\"NAMESPACE\", \"ns-1\")\nr.Run(`kubectl create ns $NAMESPACE`)")
+	require.Contains(t, source, "func (s *Suite) SetupTest() {\nr := s.Runner(\"examples/tree\")\nr.Setenv(\"KUBECONFIG\", \"/tmp/config\")\nr.Setenv(\"NAMESPACE\", \"ns-1\")\nr.Run(`echo $NAMESPACE`)")
+	require.Contains(t, source, "r := s.Runner(\"examples/tree/leaf\")\nr.Setenv(\"MESSAGE\", \"it's \\\"quoted\\\"\")\nr.Run(`echo $MESSAGE`)")
+
+	suites := generate(t, generator.New(conf), examples...)
+	source, err = suites[0].TestingString()
+	require.NoError(t, err)
+	require.Contains(t, source, "r := base.NewRunner(t, \"examples/tree\")\nr.Setenv(\"KUBECONFIG\", \"/tmp/config\")\nr.Setenv(\"NAMESPACE\", \"ns-1\")\n")
+	require.Contains(t, source, "r := base.NewRunner(t, \"examples/tree/leaf\")\nr.Setenv(\"MESSAGE\", \"it's \\\"quoted\\\"\")\n")
+	source, err = suites[0].GinkgoString()
+	require.NoError(t, err)
+	require.Contains(t, source, "r := base.NewRunner(ginkgo.GinkgoT(), \"examples/tree\")\nr.Setenv(\"KUBECONFIG\", \"/tmp/config\")\n")
+}
+
+func TestGenerateArtifacts(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/tree", Includes: []string{"leaf"}, Run: []string{"kubectl apply -k ."}, FrontMatter: parser.FrontMatter{Env: map[string]string{"NAMESPACE": "ns-1"}, Artifacts: map[string]string{"pods": "kubectl get pods -A", "events": "kubectl get events -n $NAMESPACE"}}},
+		&parser.Example{Dir: "examples/tree/leaf", Run: []string{"echo leaf"}, FrontMatter: parser.FrontMatter{Artifacts: map[string]string{"logs": "kubectl logs -l app=nse"}}},
+	)
+	require.NoError(t, err)
+
+	conf := config.Config{OutputDir: "suites", BasePkg: "example.com/base", ImportPrefix: "example.com/suites"}
+	source := goSource(t, generate(t, generator.New(conf), examples...)[0])
+	require.Contains(t, source, "r.Setenv(\"NAMESPACE\", \"ns-1\")\nr.Artifact(\"events\", \"kubectl get events -n $NAMESPACE\")\nr.Artifact(\"pods\", \"kubectl get pods -A\")\nr.Run(`kubectl apply -k .`)")
+	require.Contains(t, source, "r := s.Runner(\"examples/tree/leaf\")\nr.Artifact(\"logs\", \"kubectl logs -l app=nse\")\nr.Run(`echo leaf`)")
+
+	conf.Artifacts = map[string]string{"pods": "kubectl get pods -A -o wide", "nodes": "kubectl get nodes"}
+	source = goSource(t, generate(t, generator.New(conf), examples...)[0])
+	require.Contains(t, source, "r.Artifact(\"events\", \"kubectl get events -n $NAMESPACE\")\nr.Artifact(\"nodes\", \"kubectl get nodes\")\nr.Artifact(\"pods\", \"kubectl get pods -A\")\nr.Run(`kubectl apply -k .`)")
+	require.Contains(t, source, "r.Artifact(\"logs\", \"kubectl logs -l app=nse\")\nr.Artifact(\"nodes\", \"kubectl get nodes\")\nr.Artifact(\"pods\", \"kubectl get pods -A -o wide\")\nr.Run(`echo leaf`)")
+}
+
+func TestGenerateImage(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/tree", Includes: []string{"sub", "leaf"}, Run: []string{"go version"}, FrontMatter: parser.FrontMatter{Image: "golang:1.20", Env: map[string]string{"CGO_ENABLED": "0"}}},
+		&parser.Example{Dir: "examples/tree/leaf", Run: []string{"go test ./..."}},
+		&parser.Example{Dir: "examples/tree/sub", Includes: []string{"alpine"}, Run: []string{"go build ./..."}},
+		&parser.Example{Dir: "examples/tree/sub/alpine", Run: []string{"apk info"}, FrontMatter: parser.FrontMatter{Image: "alpine:3"}},
+	)
+	require.NoError(t, err)
+
+	conf := config.Config{OutputDir: "suites", BasePkg: "example.com/base", ImportPrefix: "example.com/suites"}
+	suites := generate(t, generator.New(conf), examples...)
+	require.Len(t, suites, 2)
+	source := goSource(t, suites[1])
+	require.Contains(t, source, "r := s.Runner(\"examples/tree\")\nr.UseImage(\"golang:1.20\")\nr.Setenv(\"CGO_ENABLED\", \"0\")\nr.Run(`go version`)")
+	require.Contains(t, source, "r := s.Runner(\"examples/tree/leaf\")\nr.UseImage(\"golang:1.20\")\nr.Run(`go test ./...`)")
+	source = goSource(t, suites[0])
+	require.Contains(t, source, "r := s.Runner(\"examples/tree/sub\")\nr.UseImage(\"golang:1.20\")\nr.Run(`go build ./...`)")
+	require.Contains(t, source, "r := s.Runner(\"examples/tree/sub/alpine\")\nr.UseImage(\"alpine:3\")\nr.Run(`apk info`)")
+}
+
+func TestGenerateLogSteps(t *testing.T) {
+	tree := &parser.Example{Dir: "examples/tree", Includes: []string{"leaf"}, Source: "examples/tree/README.md"}
+	tree.AddBlock(&parser.Block{Section: parser.SectionRun, Pos: parser.Position{Line: 7}, Script: "echo tree"})
+	leaf := &parser.Example{Dir: "examples/tree/leaf", Source: "examples/tree/leaf/README.md"}
+	leaf.AddBlock(&parser.Block{Section: parser.SectionRun, Pos: parser.Position{Line: 5}, Script: "echo leaf"})
+	leaf.AddBlock(&parser.Block{Section: parser.SectionCleanup, Pos: parser.Position{Line: 11}, Script: "echo cleanup"})
+	examples, err := linker.New("examples/").Link(tree, leaf)
+	require.NoError(t, err)
+
+	conf := config.Config{OutputDir: "suites", BasePkg: "example.com/base", ImportPrefix: "example.com/suites", LogSteps: true}
+	source := goSource(t, generate(t, generator.New(conf), examples...)[0])
+	require.Contains(t, source, "s.T().Logf(\"running: %s\", \"examples/tree/README.md:7\")\nr.Run(`echo tree`)")
+	require.Contains(t, source, "s.T().Logf(\"running: %s\", \"examples/tree/leaf/README.md:11\")\nr.Run(`echo cleanup`)")
+	require.Contains(t, source, "s.T().Logf(\"running: %s\", \"examples/tree/leaf/README.md:5\")\nr.Run(`echo leaf`)")
+
+	source, err = generate(t, generator.New(conf), examples...)[0].TestingString()
+	require.NoError(t, err)
+	require.Contains(t, source, "t.Logf(\"running: %s\", \"examples/tree/leaf/README.md:5\")\nr.Run(`echo leaf`)")
+
+	conf.LogSteps = false
+	require.NotContains(t, goSource(t, generate(t, generator.New(conf), examples...)[0]), "running:")
+}
+
+func TestGenerateSteps(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/tree", Includes: []string{"leaf"}, Run: []string{"echo tree"}},
+		&parser.Example{Dir: "examples/tree/leaf", Run: []string{"# Deploy the NSE\nkubectl apply -k .", "kubectl wait --for=condition=ready --timeout=1m pod -l app=nse-kernel"}},
+	)
+	require.NoError(t, err)
+
+	source := goSource(t, generate(t, generator.New(config.Config{
+		OutputDir:    "suites",
+		BasePkg:      "example.com/base",
+		ImportPrefix: "example.com/suites",
+		Steps:        true,
+	}), examples...)[0])
+
+	require.Contains(t, source, "r := s.Runner(\"examples/tree\")\nr.Run(`echo tree`)\n}")
+	require.Contains(t, source, `if !s.Run("step-1-deploy-the-nse", func() {`)
+	require.Contains(t, source, `if !s.Run("step-2-kubectl-wait-for-condition-ready-timeout", func() {`)
+	require.Contains(t, source, "}) {\ns.T().FailNow()\n}")
+}
+
+func TestGenerateSoftFail(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/tree", Includes: []string{"leaf", "diag"}, Run: []string{"echo tree"}},
+		&parser.Example{Dir: "examples/tree/leaf", Run: []string{"echo leaf"}},
+		&parser.Example{Dir: "examples/tree/diag", Run: []string{"kubectl get pods", "kubectl get nodes"}, FrontMatter: parser.FrontMatter{SoftFail: true}},
+	)
+	require.NoError(t, err)
+
+	suite := generate(t, generator.New(config.Config{
+		OutputDir:    "suites",
+		BasePkg:      "example.com/base",
+		ImportPrefix: "example.com/suites",
+	}), examples...)[0]
+
+	testify := goSource(t, suite)
+	require.Contains(t, testify, "func (s *Suite) TestDiag() {\nr := s.Runner(\"examples/tree/diag\")\nr.RunSoft(`kubectl get pods`)\nr.RunSoft(`kubectl get nodes`)\nr.Report()\n}")
+	require.Contains(t, testify, "func (s *Suite) TestLeaf() {\nr := s.Runner(\"examples/tree/leaf\")\nr.Run(`echo leaf`)\n}")
+	generated, err := suite.TestingString()
+	require.NoError(t, err)
+	require.Contains(t, generated, "r.RunSoft(`kubectl get pods`)\nr.RunSoft(`kubectl get nodes`)\nr.Report()")
+}
+
+func TestGenerateSkipUnlessEnv(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/producer", Run: []string{"echo producer"}, FrontMatter: parser.FrontMatter{SkipUnlessEnv: "PRODUCER"}},
+		&parser.Example{Dir: "examples/consumer", Requires: []string{"../producer"}, Run: []string{"echo consumer"}},
+	)
+	require.NoError(t, err)
+
+	suites := generate(t, generator.New(config.Config{
+		OutputDir:     "suites",
+		BasePkg:       "example.com/base",
+		ImportPrefix:  "example.com/suites",
+		SkipUnlessEnv: "E2E",
+	}), examples...)
+
+	require.Len(t, suites, 2)
+	require.Equal(t, []string{"E2E", "PRODUCER"}, suites[0].Guards())
+	require.Equal(t, []string{"E2E", "PRODUCER"}, suites[1].Guards())
+	require.Contains(t, goSource(t, suites[0]), "func (s *Suite) SetupSuite() {\nif os.Getenv(\"E2E\") == \"\" {\ns.T().Skip(\"set E2E to run the suite\")\n}")
+	generated, err := suites[0].TestingString()
+	require.NoError(t, err)
+	require.Contains(t, generated, "func Run(t *testing.T) {\nif os.Getenv(\"E2E\") == \"\" {\nt.Skip(\"set E2E to run the suite\")\n}")
+
+	suites = generate(t, generator.New(config.Config{OutputDir: "suites", BasePkg: "example.com/base", ImportPrefix: "example.com/suites"}), examples...)
+	require.Equal(t, []string{"PRODUCER"}, suites[0].Guards())
+}
+
+func TestGenerateBeforeAfterEach(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/tree", Includes: []string{"leaf"}, Run: []string{"echo tree"}, BeforeEach: []string{"echo before"}, AfterEach: []string{"echo after"}},
+		&parser.Example{Dir: "examples/tree/leaf", Run: []string{"echo leaf"}},
+	)
+	require.NoError(t, err)
+
+	suites := generate(t, generator.New(config.Config{
+		OutputDir:    "suites",
+		BasePkg:      "example.com/base",
+		ImportPrefix: "example.com/suites",
+	}), examples...)
+	require.Len(t, suites, 1)
+
+	testify := goSource(t, suites[0])
+	require.Contains(t, testify, "func (s *Suite) SetupTest() {\nr := s.Runner(\"examples/tree\")\nr.Run(`echo before`)\n}")
+	require.Contains(t, testify, "func (s *Suite) TearDownTest() {\nr := s.Runner(\"examples/tree\")\nr.Run(`echo after`)\n}")
+
+	testing, err := suites[0].TestingString()
+	require.NoError(t, err)
+	require.Contains(t, testing, "r := base.NewRunner(t, \"examples/tree\")\nt.Cleanup(func() {\nr.Run(`echo after`)\n})\nr.Run(`echo before`)\n}")
+}
+
+func TestGenerateBashAbsoluteOutputDir(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples", Run: []string{"echo root"}},
+		&parser.Example{Dir: "examples/tree", Run: []string{"echo tree"}},
+	)
+	require.NoError(t, err)
+
+	outputDir, err := filepath.Abs("suites")
+	require.NoError(t, err)
+	suites := generate(t, generator.New(config.Config{
+		OutputDir:    outputDir,
+		BasePkg:      "example.com/base",
+		ImportPrefix: "example.com/suites",
+		Bash:         true,
+	}), examples...)
+	require.Len(t, suites, 2)
+
+	// Suites are named by their paths relative to the output dir, so the scripts don't depend on its location
+	for name, suite := range map[string]*generator.Suite{".": suites[0], "tree": suites[1]} {
+		source, err := suite.BashString()
+		require.NoError(t, err)
+		require.Contains(t, source, "suite_name='"+name+"'\n")
+		require.Contains(t, source, "echo 'setup suite "+name+"'\n")
+		require.NotContains(t, source, outputDir)
+	}
+}
+
+func TestGenerateRemote(t *testing.T) {
+	moduleDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(moduleDir, "examples", "basic"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, "examples", "basic", "README.md"), []byte("# Run\n```bash\necho basic\n```\n"), 0o600))
+	fetcher := linker.FetcherFunc(func(module, version string) (string, error) {
+		return moduleDir, nil
+	})
+
+	const remote = "github.com/org/repo//examples/basic@v1.0.0"
+	examples, err := linker.New("examples/", linker.WithFetcher(fetcher)).Link(
+		&parser.Example{Dir: "examples/a", Requires: []string{remote}, Run: []string{"echo a"}},
+	)
+	require.NoError(t, err)
+
+	g := generator.New(config.Config{
+		OutputDir:    "suites",
+		BasePkg:      "example.com/base",
+		ImportPrefix: "example.com/suites",
+		Bash:         true,
+	})
+	suites := generate(t, g, examples...)
+	require.Len(t, suites, 1)
+	require.Len(t, suites[0].Parents, 1)
+
+	// Remote suites are generated in their own modules, so they aren't located in the output dir
+	parent := suites[0].Parents[0]
+	require.Empty(t, parent.Location)
+	require.Equal(t, remote, parent.Path)
+	require.Equal(t, generator.Dependency("github.com/org/repo/examples/basic"), parent.Dependency)
+	require.Contains(t, goSource(t, suites[0]), `"github.com/org/repo/examples/basic"`)
+
+	source, err := suites[0].BashString()
+	require.NoError(t, err)
+	require.Contains(t, source, "echo 'setup suite "+remote+"'\n")
+
+	infos := g.List(suites)
+	require.Len(t, infos, 1)
+	require.Equal(t, []string{"github.com-org-repo--examples-basic@v1.0.0"}, infos[0].Requires)
+}
+
+func TestGenerateBash(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/tree", Includes: []string{"leaf"}, Requires: []string{"../spire"}, Run: []string{"echo tree"}},
+		&parser.Example{Dir: "examples/tree/leaf", Run: []string{"echo leaf\necho done"}, Cleanup: []string{"echo leaf cleanup"}},
+		&parser.Example{Dir: "examples/spire", Run: []string{"echo spire"}, Cleanup: []string{"echo spire cleanup"}},
+	)
+	require.NoError(t, err)
+
+	suites := generate(t, generator.New(config.Config{
+		OutputDir:    "suites",
+		BasePkg:      "example.com/base",
+		ImportPrefix: "example.com/suites",
+		Bash:         true,
+	}), examples...)
+	require.Len(t, suites, 2)
+	require.Equal(t, "suites/tree", suites[1].BashDir())
+
+	source, err := suites[1].BashString()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(source, "#!/usr/bin/env bash\n"))
+	require.Contains(t, source, "set -Eeuo pipefail\n")
+	require.Contains(t, source, "gotestmd_dir=$(cd \"$(dirname \"${BASH_SOURCE[0]}\")\" || exit; pwd)\n")
+	require.Contains(t, source, "trap summary EXIT\n")
+	require.Contains(t, source, "# shellcheck disable=SC2164,SC2317\n")
+	require.Contains(t, source, "suite_name='tree'\njunit=${GOTESTMD_JUNIT-junit.xml}\n")
+	require.Contains(t, source, "setup() {\n\tbegin setup\n\tsetup_dependencies\n\tsetup_main\n\tend\n}")
+	require.Contains(t, source, "\tlog_step 'Run' 'echo spire'\n\techo spire\n")
+	require.Contains(t, source, "cleanup_dependencies() {\n\tset +eu\n\techo 'cleanup suite spire'\n")
+	require.Contains(t, source, "\techo spire cleanup\n\tset -eu\n}")
+	require.Contains(t, source, "testLeaf() {\n\tbegin testLeaf\n\tcd \"$gotestmd_dir\"/'../../examples/tree/leaf'\n\tlog_step 'Run' 'echo leaf\n\techo done'\n\techo leaf\n\techo done\n\n"+
+		"\tset +eu\n\tcd \"$gotestmd_dir\"/'../../examples/tree/leaf'\n\tlog_step 'Cleanup' 'echo leaf cleanup'\n\techo leaf cleanup\n\tset -eu\n\tend\n}")
+	require.NotContains(t, source, "&&")
+
+	scripts, err := suites[1].BashScripts()
+	require.NoError(t, err)
+	require.Len(t, scripts, 4)
+	require.Equal(t, source+"\nsetup\nexit \"$status\"\n", scripts[generator.BashSetupScript])
+	require.Equal(t, source+"\ncleanup\nexit \"$status\"\n", scripts[generator.BashCleanupScript])
+	require.Equal(t, source+"\ntestLeaf\nexit \"$status\"\n", scripts["test_leaf.sh"])
+
+	cli := scripts[generator.BashSuiteScript]
+	require.True(t, strings.HasPrefix(cli, source))
+	require.Contains(t, cli, "\t\tleaf) tests+=(testLeaf) ;;\n")
+	require.Contains(t, cli, "max_jobs=${GOTESTMD_JOBS:-1}\n")
+	require.Contains(t, cli, "all)\n\tshift\n\tif [ $# -eq 0 ]; then\n\t\tset -- Leaf\n\tfi\n\ttrap 'cleanup; summary; exit \"$status\"' EXIT\n\tsetup\n\trun_tests \"$@\"\n\t;;")
+
+	suites = generate(t, generator.New(config.Config{
+		OutputDir:    "suites",
+		BasePkg:      "example.com/base",
+		ImportPrefix: "example.com/suites",
+		Bash:         true,
+		BashJobs:     4,
+	}), examples...)
+	generated, err := suites[1].BashScripts()
+	require.NoError(t, err)
+	require.Contains(t, generated[generator.BashSuiteScript], "max_jobs=${GOTESTMD_JOBS:-4}\n")
+}
+
+func TestPowerShell(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/tree", Includes: []string{"leaf"}, Run: []string{"echo tree"}, Cleanup: []string{"echo 'tree cleanup'"}},
+		&parser.Example{Dir: "examples/tree/leaf", Run: []string{"echo leaf"}, Cleanup: []string{"echo leaf cleanup"}},
+	)
+	require.NoError(t, err)
+
+	suites := generate(t, generator.New(config.Config{
+		OutputDir:    "suites",
+		BasePkg:      "example.com/base",
+		ImportPrefix: "example.com/suites",
+		Bash:         true,
+	}), examples...)
+	require.Len(t, suites, 1)
+
+	source, err := suites[0].PowerShellString()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(source, "#Requires -Version 5.1\n"))
+	require.Contains(t, source, "$Setup = {\n\tWrite-Host 'setup suite tree'\n\tSet-Location -LiteralPath (Join-Path $PSScriptRoot '../../examples/tree')\n"+
+		"\tWrite-Step 'Run' 'echo tree'\n\techo tree\n\tAssert-ExitCode 'Run'\n}")
+	require.Contains(t, source, "\ttry {\n\t\tWrite-Step 'Cleanup' 'echo ''tree cleanup'''\n\t\techo 'tree cleanup'\n\t\tAssert-ExitCode 'Cleanup'\n"+
+		"\t} catch {\n\t\tWrite-Failure $_\n\t}\n")
+	require.Contains(t, source, "\t'Leaf' = {\n\t\ttry {\n\t\t\tSet-Location -LiteralPath (Join-Path $PSScriptRoot '../../examples/tree/leaf')\n")
+	require.Contains(t, source, "\t\t} finally {\n\t\t\tSet-Location -LiteralPath (Join-Path $PSScriptRoot '../../examples/tree/leaf')\n\t\t\ttry {\n")
+}
+
+func TestList(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/base", Run: []string{"echo base"}, FrontMatter: parser.FrontMatter{SkipUnlessEnv: "E2E"}},
+		&parser.Example{Dir: "examples/tree", Includes: []string{"leaf", "sub"}, Requires: []string{"../base"}, Run: []string{"echo tree"}},
+		&parser.Example{Dir: "examples/tree/leaf", Run: []string{"echo leaf"}, FrontMatter: parser.FrontMatter{BuildTags: []string{"linux"}}},
+		&parser.Example{Dir: "examples/tree/sub", Includes: []string{"leaf"}, Run: []string{"echo sub"}},
+		&parser.Example{Dir: "examples/tree/sub/leaf", Run: []string{"echo sub leaf"}},
+	)
+	require.NoError(t, err)
+
+	g := generator.New(config.Config{
+		OutputDir:    "suites",
+		BasePkg:      "example.com/base",
+		ImportPrefix: "example.com/suites",
+	})
+	infos := g.List(generate(t, g, examples...))
+	require.Len(t, infos, 3)
+
+	var tree *generator.SuiteInfo
+	for _, info := range infos {
+		if info.Name == "tree" {
+			tree = info
+		}
+	}
+	require.NotNil(t, tree)
+	require.Equal(t, []string{"Leaf"}, tree.Tests)
+	require.Equal(t, []string{"tree-sub"}, tree.Includes)
+	require.Equal(t, []string{"base"}, tree.Requires)
+	require.Equal(t, "linux", tree.BuildConstraint)
+	require.Equal(t, []string{"E2E"}, tree.Envs)
+	require.Equal(t, filepath.Join("suites", "tree", "suite.gen.go"), tree.Location)
+}
+
+func TestDockerCompose(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/tree", Includes: []string{"leaf"}, Run: []string{"echo tree"}},
+		&parser.Example{Dir: "examples/tree/leaf", Run: []string{"echo leaf"}},
+	)
+	require.NoError(t, err)
+
+	g := generator.New(config.Config{
+		OutputDir:    "suites",
+		BasePkg:      "example.com/base",
+		ImportPrefix: "example.com/suites",
+		Bash:         true,
+	})
+	suites := generate(t, g, examples...)
+
+	compose, err := g.DockerComposeString("alpine@sha256:0123", ".", suites)
+	require.NoError(t, err)
+	require.Contains(t, compose, "services:\n  tree:\n    image: \"alpine@sha256:0123\"\n    working_dir: \"/work\"\n    volumes:\n      - \"..:/work\"\n")
+	require.Contains(t, compose, "    entrypoint: [\"./suites/tree/docker-entrypoint.sh\"]\n    command: [\"all\"]\n")
+	dir, err := os.Getwd()
+	require.NoError(t, err)
+	require.NotContains(t, compose, dir)
+
+	_, err = g.DockerComposeString("alpine@sha256:0123", "suites/tree/leaf", suites)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is outside of the mounted dir")
+
+	script, err := suites[0].DockerEntrypointString()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(script, "#!/usr/bin/env bash\n"))
+	require.True(t, strings.HasSuffix(script, "cd \"$(dirname \"$0\")\"\nexec ./suite.sh \"$@\"\n"))
+}
+
+func TestBashRunAll(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/tree/sub", Includes: []string{"leaf"}, Run: []string{"echo sub"}},
+		&parser.Example{Dir: "examples/tree/sub/leaf", Run: []string{"echo leaf"}},
+		&parser.Example{Dir: "examples/tree", Includes: []string{"sub"}, Requires: []string{"../spire"}, Run: []string{"echo tree"}},
+		&parser.Example{Dir: "examples/spire", Run: []string{"echo spire"}},
+	)
+	require.NoError(t, err)
+
+	g := generator.New(config.Config{
+		OutputDir:    "suites",
+		BasePkg:      "example.com/base",
+		ImportPrefix: "example.com/suites",
+		Bash:         true,
+	})
+	suites := generate(t, g, examples...)
+
+	source, err := g.BashRunAllString(suites)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(source, "#!/usr/bin/env bash\n"))
+	require.Contains(t, source, "all_suites=(\n\t'spire'\n\t'tree'\n\t'tree/sub'\n)\n")
+	require.Contains(t, source, "-k | --keep-going)")
+}
+
+func TestMakefile(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/tree", Includes: []string{"leaf"}, Run: []string{"echo tree"}},
+		&parser.Example{Dir: "examples/tree/leaf", Run: []string{"echo leaf"}},
+	)
+	require.NoError(t, err)
+
+	conf := config.Config{
+		OutputDir:    "suites",
+		BasePkg:      "example.com/base",
+		ImportPrefix: "example.com/suites",
+		Format:       config.FormatTestify,
+		Entrypoints:  config.EntrypointsRoots,
+	}
+	g := generator.New(conf)
+	makefile, err := g.MakefileString(generate(t, g, examples...))
+	require.NoError(t, err)
+	require.Contains(t, makefile, "test-tree:\n\tcd $(GOTESTMD_DIR) && go test $(GOTESTMD_GO_TEST_FLAGS) ./tree -run '^TestTree$$'\n")
+	require.Contains(t, makefile, "test-tree-leaf:\n\tcd $(GOTESTMD_DIR) && go test $(GOTESTMD_GO_TEST_FLAGS) ./tree -run '^TestTree$$/^TestLeaf$$'\n")
+
+	conf.Bash = true
+	g = generator.New(conf)
+	makefile, err = g.MakefileString(generate(t, g, examples...))
+	require.NoError(t, err)
+	require.Contains(t, makefile, "test-all:\n\t$(GOTESTMD_DIR)/run-all.sh\n")
+	require.Contains(t, makefile, "test-tree-leaf:\n\t$(GOTESTMD_DIR)/tree/suite.sh all Leaf\n")
+}
+
+func TestGitHubWorkflow(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/tree", Includes: []string{"sub"}, Run: []string{"echo tree"}},
+		&parser.Example{Dir: "examples/tree/sub", Includes: []string{"leaf"}, Requires: []string{"../../spire"}, Run: []string{"echo sub"}},
+		&parser.Example{Dir: "examples/tree/sub/leaf", Run: []string{"echo leaf"}},
+		&parser.Example{Dir: "examples/spire", Includes: []string{"agent"}, Run: []string{"echo spire"}},
+		&parser.Example{Dir: "examples/spire/agent", Run: []string{"echo agent"}},
+	)
+	require.NoError(t, err)
+
+	conf := config.Config{
+		OutputDir:    "suites",
+		BasePkg:      "example.com/base",
+		ImportPrefix: "example.com/suites",
+		Format:       config.FormatTestify,
+		Entrypoints:  config.EntrypointsRoots,
+	}
+	g := generator.New(conf)
+	workflow, err := g.GitHubWorkflowString("examples", generate(t, g, examples...))
+	require.NoError(t, err)
+	require.Contains(t, workflow, "name: examples\n")
+	require.Contains(t, workflow, "  spire:\n    name: spire\n    runs-on: ubuntu-latest\n    steps:\n")
+	require.Contains(t, workflow, "  tree:\n    name: tree\n    runs-on: ubuntu-latest\n    needs: [spire]\n")
+	require.Contains(t, workflow, "go test -v -count=1 ./suites/tree -run '^TestTree$' 2>&1 | tee gotestmd-logs/tree.log\n")
+	require.NotContains(t, workflow, "tree-sub:")
+
+	conf.Bash = true
+	g = generator.New(conf)
+	workflow, err = g.GitHubWorkflowString("examples", generate(t, g, examples...))
+	require.NoError(t, err)
+	require.Contains(t, workflow, "  tree-sub:\n    name: tree-sub\n    runs-on: ubuntu-latest\n    needs: [spire, tree]\n")
+	require.Contains(t, workflow, "./suites/tree/sub/suite.sh all\n")
+	require.Contains(t, workflow, "./suites/tree/sub/logs/\n            ./suites/tree/sub/junit.xml\n")
+	require.NotContains(t, workflow, "setup-go")
+}
+
+func TestGenerateSourceHeader(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/tree", Includes: []string{"leaf"}, Run: []string{"echo tree"}, Source: "examples/tree/README.md", Hash: "sha256:a"},
+		&parser.Example{Dir: "examples/tree/leaf", Run: []string{"echo leaf"}, Source: "examples/tree/leaf/README.md", Hash: "sha256:b"},
+	)
+	require.NoError(t, err)
+
+	suites := generate(t, generator.New(config.Config{
+		OutputDir:    t.TempDir(),
+		BasePkg:      "example.com/base",
+		ImportPrefix: "example.com/tests/suites",
+	}), examples...)
+
+	require.Len(t, suites, 1)
+	require.Contains(t, goSource(t, suites[0]), `// Code generated by gotestmd DO NOT EDIT.
+// Source: examples/tree/README.md sha256:a
+// Source: examples/tree/leaf/README.md sha256:b
+package tree`)
+}
+
+func TestGenerateSourceRelativeToModuleRoot(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/tests\n"), 0o600))
+	dir := filepath.Join(root, "docs", "tree")
+	examples, err := linker.New(filepath.Join(root, "docs")).Link(
+		&parser.Example{Dir: dir, Includes: []string{"leaf"}, Run: []string{"echo tree"}, Source: filepath.Join(dir, "README.md"), Hash: "sha256:a"},
+		&parser.Example{Dir: filepath.Join(dir, "leaf"), Run: []string{"echo leaf"}, Source: filepath.Join(dir, "leaf", "README.md"), Hash: "sha256:b"},
+	)
+	require.NoError(t, err)
+
+	suites := generate(t, generator.New(config.Config{
+		InputDir:     filepath.Join(root, "docs"),
+		OutputDir:    filepath.Join(root, "suites"),
+		BasePkg:      "example.com/base",
+		ImportPrefix: "example.com/tests/suites",
+	}), examples...)
+
+	require.Len(t, suites, 1)
+	source := goSource(t, suites[0])
+	require.Contains(t, source, "// Source: docs/tree/README.md sha256:a\n// Source: docs/tree/leaf/README.md sha256:b\n")
+	require.Contains(t, source, `r := s.Runner("docs/tree/leaf")`)
+	require.NotContains(t, source, root)
+}
+
+func TestModuleRoot(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "docs", "tree")
+	require.NoError(t, os.MkdirAll(dir, 0o700))
+
+	// The search stops at the root of the file system if there is no go.mod
+	if generator.ModuleRoot(root) == "" {
+		require.Empty(t, generator.ModuleRoot(dir))
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/tests\n"), 0o600))
+	require.Equal(t, root, generator.ModuleRoot(dir))
+	require.Equal(t, root, generator.ModuleRoot(root))
+}
+
+func TestGenerateBuildTags(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/tree", Includes: []string{"leaf", "sub"}, Run: []string{"echo tree"}},
+		&parser.Example{Dir: "examples/tree/leaf", Run: []string{"echo leaf"}, FrontMatter: parser.FrontMatter{BuildTags: []string{"linux || darwin"}}},
+		&parser.Example{Dir: "examples/tree/sub", Requires: []string{"../../spire"}, Run: []string{"echo sub"}},
+		&parser.Example{Dir: "examples/spire", Run: []string{"echo spire"}, FrontMatter: parser.FrontMatter{BuildTags: []string{"spire"}}},
+	)
+	require.NoError(t, err)
+
+	suites := generate(t, generator.New(config.Config{
+		OutputDir:    "suites",
+		BasePkg:      "example.com/base",
+		ImportPrefix: "example.com/suites",
+		BuildTags:    []string{"integration"},
+	}), examples...)
+
+	require.Len(t, suites, 3)
+	require.Equal(t, "suites/spire/suite.gen.go", suites[0].Location)
+	require.Equal(t, "integration && spire", suites[0].BuildConstraint())
+	require.Equal(t, "suites/tree/sub/suite.gen.go", suites[1].Location)
+	require.Equal(t, "integration && spire", suites[1].BuildConstraint())
+	require.Equal(t, "suites/tree/suite.gen.go", suites[2].Location)
+	require.Equal(t, "integration && (linux || darwin) && spire", suites[2].BuildConstraint())
+	require.Contains(t, goSource(t, suites[2]), "\n//go:build integration && (linux || darwin) && spire\n")
+}
+
+func TestGenerateEntrypoints(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/tree", Includes: []string{"leaf", "sub"}, Run: []string{"echo tree"}},
+		&parser.Example{Dir: "examples/tree/leaf", Run: []string{"echo leaf"}},
+		&parser.Example{Dir: "examples/tree/sub", Includes: []string{"leaf"}, Run: []string{"echo sub"}},
+		&parser.Example{Dir: "examples/tree/sub/leaf", Run: []string{"echo sub leaf"}},
+	)
+	require.NoError(t, err)
+
+	entrypoints := func(mode string) map[string]bool {
+		var result = map[string]bool{}
+		for _, s := range generate(t, generator.New(config.Config{
+			OutputDir:    "suites",
+			BasePkg:      "example.com/base",
+			ImportPrefix: "example.com/suites",
+			Entrypoints:  mode,
+		}), examples...) {
+			result[s.Name()] = s.Entrypoint
+		}
+		return result
+	}
+
+	require.Equal(t, map[string]bool{"tree": false, "sub": false}, entrypoints(config.EntrypointsNone))
+	require.Equal(t, map[string]bool{"tree": true, "sub": false}, entrypoints(config.EntrypointsRoots))
+	require.Equal(t, map[string]bool{"tree": false, "sub": true}, entrypoints(config.EntrypointsLeaves))
+	require.Equal(t, map[string]bool{"tree": true, "sub": true}, entrypoints(config.EntrypointsAll))
+
+	suites := generate(t, generator.New(config.Config{OutputDir: "suites", BasePkg: "example.com/base", ImportPrefix: "example.com/suites"}), examples...)
+	require.Equal(t, "suites/tree/suite.gen_test.go", suites[1].EntrypointLocation())
+	generated, err := suites[1].EntrypointString(config.FormatTestify)
+	require.NoError(t, err)
+	require.Contains(t, generated, "func TestTree(t *testing.T) {\nsuite.Run(t, new(Suite))\n}")
+	generated, err = suites[1].EntrypointString(config.FormatTesting)
+	require.NoError(t, err)
+	require.Contains(t, generated, "func TestTree(t *testing.T) {\nRun(t)\n}")
+	generated, err = suites[1].EntrypointString(config.FormatGinkgo)
+	require.NoError(t, err)
+	require.Contains(t, generated, `var _ = ginkgo.Describe("Tree", ginkgo.Ordered, Specs)`)
+}
+
+func TestGenerateSinglePackage(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/tree", Includes: []string{"leaf", "sub"}, Run: []string{"echo tree"}},
+		&parser.Example{Dir: "examples/tree/leaf", Run: []string{"echo leaf"}},
+		&parser.Example{Dir: "examples/tree/sub", Requires: []string{"../../spire"}, Run: []string{"echo sub"}},
+		&parser.Example{Dir: "examples/spire", Run: []string{"echo spire"}},
+	)
+	require.NoError(t, err)
+
+	suites := generate(t, generator.New(config.Config{
+		OutputDir:     "suites",
+		BasePkg:       "example.com/base",
+		ImportPrefix:  "example.com/suites",
+		SinglePackage: true,
+	}), examples...)
+
+	require.Len(t, suites, 3)
+	require.Equal(t, "suites/spire.gen.go", suites[0].Location)
+	require.Equal(t, "suites/tree.gen.go", suites[1].Location)
+	require.Equal(t, "suites/tree_sub.gen.go", suites[2].Location)
+	require.Equal(t, "suites/tree.gen_test.go", suites[1].EntrypointLocation())
+
+	tree := goSource(t, suites[1])
+	require.Contains(t, tree, "package suites\n")
+	require.Contains(t, tree, "type TreeSuite struct {\nbase.Suite\ntreeSubSuite TreeSubSuite\n}")
+	require.Contains(t, tree, "suite.Run(s.T(), &s.treeSubSuite)")
+	require.Contains(t, tree, "func (s *TreeSuite) TestLeaf() {")
+	require.NotContains(t, tree, "example.com/suites")
+
+	sub := goSource(t, suites[2])
+	require.Contains(t, sub, "type TreeSubSuite struct {\nbase.Suite\nspireSuite SpireSuite\n}")
+	require.Contains(t, sub, "s.SetupParents(&s.spireSuite)")
+	require.Contains(t, sub, "func (s *TreeSubSuite) Test() {}")
+	generated, err := suites[1].EntrypointString(config.FormatTestify)
+	require.NoError(t, err)
+	require.Contains(t, generated, "func TestTree(t *testing.T) {\nsuite.Run(t, new(TreeSuite))\n}")
+}
+
+func TestGeneratePlainBase(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/sub", Requires: []string{"../spire"}, Run: []string{"echo sub"}},
+		&parser.Example{Dir: "examples/spire", Run: []string{"echo spire"}, Cleanup: []string{"echo cleanup"}},
+	)
+	require.NoError(t, err)
+
+	suites := generate(t, generator.New(config.Config{
+		OutputDir:    "suites",
+		BasePkg:      "example.com/base",
+		ImportPrefix: "example.com/suites",
+		PlainBase:    true,
+		Timeout:      time.Minute,
+	}), examples...)
+
+	require.Len(t, suites, 2)
+	spire := goSource(t, suites[0])
+	require.Contains(t, spire, "s.T().Cleanup(cancel)")
+	require.Contains(t, spire, "s.T().Cleanup(func() {\nr.Run(`echo cleanup`)\n})")
+	sub := goSource(t, suites[1])
+	require.Contains(t, sub, "parents := []interface{}{&s.Suite, &s.spireSuite}\n")
+	require.NotContains(t, sub, "SetupParents")
+}
+
+func TestGenerateNaming(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/tree", Includes: []string{"leaf-A", "sub-tree"}, Run: []string{"echo tree"}},
+		&parser.Example{Dir: "examples/tree/leaf-A", Run: []string{"echo leaf"}},
+		&parser.Example{Dir: "examples/tree/sub-tree", Includes: []string{"leaf"}, Run: []string{"echo sub"}},
+		&parser.Example{Dir: "examples/tree/sub-tree/leaf", Run: []string{"echo leaf"}},
+	)
+	require.NoError(t, err)
+
+	names := func(naming config.Naming) []string {
+		suites := generate(t, generator.New(config.Config{
+			OutputDir:    "suites",
+			BasePkg:      "example.com/base",
+			ImportPrefix: "example.com/suites",
+			Naming:       naming,
+		}), examples...)
+		require.Len(t, suites, 2)
+		return []string{suites[1].Tests[0].Name, suites[0].Title()}
+	}
+
+	require.Equal(t, []string{"Leaf_a", "Sub_tree"}, names(config.Naming{}))
+	require.Equal(t, []string{"LeafA", "SubTree"}, names(config.Naming{Case: config.NamingCamel}))
+	require.Equal(t, []string{"Leaf_A", "Sub_tree"}, names(config.Naming{Case: config.NamingPreserve, Separator: "_"}))
+	require.Equal(t, []string{"E2ELeafaTest", "E2ESubtreeTest"}, names(config.Naming{Case: config.NamingTitle, Prefix: "E2E", Suffix: "Test"}))
+}
+
+func TestCheckNames(t *testing.T) {
+	generate := func(examples ...*parser.Example) []*generator.Suite {
+		linked, err := linker.New("examples/").Link(examples...)
+		require.NoError(t, err)
+		return generate(t, generator.New(config.Config{
+			OutputDir:    "suites",
+			BasePkg:      "example.com/base",
+			ImportPrefix: "example.com/suites",
+		}), linked...)
+	}
+
+	require.NoError(t, generator.CheckNames(generate(
+		&parser.Example{Dir: "examples/tree", Includes: []string{"leaf-a", "leaf-b"}, Run: []string{"echo tree"}},
+		&parser.Example{Dir: "examples/tree/leaf-a", Run: []string{"echo a"}},
+		&parser.Example{Dir: "examples/tree/leaf-b", Run: []string{"echo b"}},
+	)))
+
+	err := generator.CheckNames(generate(
+		&parser.Example{Dir: "examples/tree", Includes: []string{"leaf-a", "leaf_a"}, Run: []string{"echo tree"}},
+		&parser.Example{Dir: "examples/tree/leaf-a", Run: []string{"echo a"}},
+		&parser.Example{Dir: "examples/tree/leaf_a", Run: []string{"echo a"}},
+	))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "same test Leaf_a")
+
+	err = generator.CheckNames(generate(
+		&parser.Example{Dir: "examples/tree", Includes: []string{"sub-tree", "sub_tree"}, Run: []string{"echo tree"}},
+		&parser.Example{Dir: "examples/tree/sub-tree", Includes: []string{"leaf"}, Run: []string{"echo sub"}},
+		&parser.Example{Dir: "examples/tree/sub-tree/leaf", Run: []string{"echo leaf"}},
+		&parser.Example{Dir: "examples/tree/sub_tree", Includes: []string{"leaf"}, Run: []string{"echo sub"}},
+		&parser.Example{Dir: "examples/tree/sub_tree/leaf", Run: []string{"echo leaf"}},
+	))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "same package example.com/suites/tree/sub_tree")
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	newExamples := func() []*parser.Example {
+		return []*parser.Example{
+			{Dir: "examples/tree", Includes: []string{"b", "a", "sub2", "sub1"}, Run: []string{"echo tree"}},
+			{Dir: "examples/tree/a", Run: []string{"echo a"}},
+			{Dir: "examples/tree/b", Run: []string{"echo b"}},
+			{Dir: "examples/tree/sub1", Includes: []string{"leaf"}, Requires: []string{"../../spire", "../../spire"}, Run: []string{"echo sub1"}},
+			{Dir: "examples/tree/sub1/leaf", Run: []string{"echo leaf1"}},
+			{Dir: "examples/tree/sub2", Includes: []string{"leaf"}, Run: []string{"echo sub2"}},
+			{Dir: "examples/tree/sub2/leaf", Run: []string{"echo leaf2"}},
+			{Dir: "examples/spire", Run: []string{"echo spire"}},
+		}
+	}
+	generate := func(examples []*parser.Example) []string {
+		linked, err := linker.New("examples/").Link(examples...)
+		require.NoError(t, err)
+		var result []string
+		for _, s := range generate(t, generator.New(config.Config{
+			OutputDir:    "suites",
+			BasePkg:      "example.com/base",
+			ImportPrefix: "example.com/suites",
+		}), linked...) {
+			result = append(result, s.Location, goSource(t, s))
+		}
+		return result
+	}
+
+	expected := generate(newExamples())
+	require.Contains(t, expected, "suites/spire/suite.gen.go")
+
+	reversed := newExamples()
+	for i, j := 0, len(reversed)-1; i < j; i, j = i+1, j-1 {
+		reversed[i], reversed[j] = reversed[j], reversed[i]
+	}
+	require.Equal(t, expected, generate(reversed))
+}
+
+func TestGenerateShells(t *testing.T) {
+	posix := &parser.Example{Dir: "examples/posix"}
+	posix.AddBlock(&parser.Block{Section: parser.SectionRun, Pos: parser.Position{Line: 5}, Script: "echo 'posix'", Shell: "sh"})
+	posix.AddBlock(&parser.Block{Section: parser.SectionRun, Pos: parser.Position{Line: 9}, Script: "echo bash"})
+	examples, err := linker.New("examples/").Link(posix)
+	require.NoError(t, err)
+
+	conf := config.Config{OutputDir: "suites", BasePkg: "example.com/base", ImportPrefix: "example.com/suites"}
+	suites := generate(t, generator.New(conf), examples...)
+	require.Len(t, suites, 1)
+	source := goSource(t, suites[0])
+	require.Contains(t, source, "r.Run(`sh -c 'echo '\\''posix'\\'''`)\nr.Run(`echo bash`)")
+}
+
+func TestGenerateIsolate(t *testing.T) {
+	isolated := &parser.Example{Dir: "examples/isolated", Includes: []string{"leaf"}, FrontMatter: parser.FrontMatter{Isolate: &parser.Isolate{Env: true, Namespaces: []string{"ns-1", "ns-2"}}}}
+	isolated.Env = map[string]string{"NAMESPACE": "ns-1"}
+	isolated.Artifacts = map[string]string{"pods": "kubectl get pods -A"}
+	isolated.AddBlock(&parser.Block{Section: parser.SectionAfterEach, Pos: parser.Position{Line: 7}, Script: "kubectl get pods"})
+	leaf := &parser.Example{Dir: "examples/isolated/leaf"}
+	leaf.Env = map[string]string{"NAMESPACE": "ns-2"}
+	leaf.AddBlock(&parser.Block{Section: parser.SectionRun, Pos: parser.Position{Line: 3}, Script: "echo leaf"})
+	examples, err := linker.New("examples/").Link(isolated, leaf)
+	require.NoError(t, err)
+
+	conf := config.Config{OutputDir: "suites", BasePkg: "example.com/base", ImportPrefix: "example.com/suites"}
+	suites := generate(t, generator.New(conf), examples...)
+	require.Len(t, suites, 1)
+	source := goSource(t, suites[0])
+	require.Contains(t, source, "func (s *Suite) SetupTest() {\ns.SnapshotState(shell.SnapshotEnv(), shell.SnapshotNamespaces(\"ns-1\", \"ns-2\"))\n}")
+	require.Contains(t, source, "func (s *Suite) TearDownTest() {\nr := s.Runner(\"examples/isolated\")\nr.Setenv(\"NAMESPACE\", \"ns-1\")\nr.Artifact(\"pods\", \"kubectl get pods -A\")\nr.Run(`kubectl get pods`)\ns.RestoreState()\n}")
+	// The runners of the tests set the env and the artifacts of the suite, the ones of the tests override them
+	require.Contains(t, source, "r := s.Runner(\"examples/isolated/leaf\")\nr.Setenv(\"NAMESPACE\", \"ns-2\")\nr.Artifact(\"pods\", \"kubectl get pods -A\")\nr.Run(`echo leaf`)")
+	require.Contains(t, source, `"github.com/networkservicemesh/gotestmd/pkg/suites/shell"`)
+
+	_, err = suites[0].TestingString()
+	require.EqualError(t, err, "isolate of examples/isolated is supported only by the testify format")
+	_, err = suites[0].GinkgoString()
+	require.Error(t, err)
+}
+
+func TestGenerateLargeBlock(t *testing.T) {
+	var lines []string
+	for i := 0; i < 100000; i++ {
+		lines = append(lines, fmt.Sprintf("  key%v: `value`\t%v", i, i))
+	}
+	manifest := "kubectl apply -f - <<EOF\n" + strings.Join(lines, "\n") + "\nEOF"
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/manifest", Run: []string{"echo small\necho block", manifest}},
+	)
+	require.NoError(t, err)
+
+	conf := config.Config{OutputDir: "suites", BasePkg: "example.com/base", ImportPrefix: "example.com/suites"}
+	suite := generate(t, generator.New(conf), examples...)[0]
+	source := goSource(t, suite)
+	require.Contains(t, source, "r.Run(`echo small`+\"\\n\"+`echo block`)\n")
+	require.Contains(t, source, "r.Run("+strconv.Quote(manifest)+")\n")
+
+	_, err = generator.Format(suite.Location, source)
+	require.NoError(t, err)
+}
+
+func TestGenerateChildrenPriority(t *testing.T) {
+	var examples = []*parser.Example{
+		{Dir: "examples/tree", Includes: []string{"slow", "zeta", "alpha", "first"}},
+	}
+	for name, priority := range map[string]int{"slow": 10, "zeta": 0, "alpha": 0, "first": -1} {
+		examples = append(examples,
+			&parser.Example{Dir: "examples/tree/" + name, Includes: []string{"check"}, FrontMatter: parser.FrontMatter{Priority: priority}},
+			&parser.Example{Dir: "examples/tree/" + name + "/check", Run: []string{"echo " + name}},
+		)
+	}
+	linked, err := linker.New("examples/").Link(examples...)
+	require.NoError(t, err)
+
+	suites := generate(t, generator.New(config.Config{
+		OutputDir: "suites",
+		BasePkg:   "example.com/base",
+	}), linked...)
+
+	var titles []string
+	require.Equal(t, "tree", suites[3].Name())
+	for _, match := range regexp.MustCompile(`s\.Run\("(\w+)"`).FindAllStringSubmatch(goSource(t, suites[3]), -1) {
+		titles = append(titles, match[1])
+	}
+	require.Equal(t, []string{"First", "Alpha", "Zeta", "Slow"}, titles)
+}
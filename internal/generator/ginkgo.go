@@ -0,0 +1,28 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import "io"
+
+// GinkgoString returns a string that contains the suite as ginkgo specs
+func (s *Suite) GinkgoString() (string, error) {
+	return writeString(s.writeGinkgo)
+}
+
+func (s *Suite) writeGinkgo(w io.Writer) error {
+	return s.writePlain(w, GinkgoSuiteTemplateName, s.plainSuite("Setup()", "ginkgo.DeferCleanup", "ginkgo.GinkgoT().Logf"))
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+type githubJob struct {
+	ID    string
+	Name  string
+	Needs []string
+	Run   []string
+	Logs  []string
+}
+
+// GitHubWorkflowString returns a GitHub Actions workflow with a job per suite with an entrypoint, or per suite with bash
+// scripts if the suites are generated for bash. Jobs need the jobs running the suites they require and, for bash, the
+// suites including them. Logs of each job are uploaded as artifacts
+func (g *Generator) GitHubWorkflowString(name string, suites []*Suite) (string, error) {
+	var includers = map[*Suite][]*Suite{}
+	var jobs = map[*Suite]bool{}
+	for _, s := range suites {
+		for _, c := range s.Children {
+			includers[c] = append(includers[c], s)
+		}
+		jobs[s] = g.conf.Bash || s.Entrypoint
+	}
+	// jobOf returns the suite of the job running the suite as a part of it
+	var jobOf func(s *Suite, visited map[*Suite]bool) *Suite
+	jobOf = func(s *Suite, visited map[*Suite]bool) *Suite {
+		if jobs[s] {
+			return s
+		}
+		if visited[s] {
+			return nil
+		}
+		visited[s] = true
+		for _, p := range includers[s] {
+			if job := jobOf(p, visited); job != nil {
+				return job
+			}
+		}
+		return nil
+	}
+
+	var result []githubJob
+	for _, s := range bashOrder(suites) {
+		if !jobs[s] {
+			continue
+		}
+		var needs = map[string]bool{}
+		var dependencies = append([]*Suite(nil), s.Parents...)
+		if g.conf.Bash {
+			dependencies = append(dependencies, includers[s]...)
+		} else {
+			for _, c := range s.subtree() {
+				dependencies = append(dependencies, c.Parents...)
+			}
+		}
+		for _, d := range dependencies {
+			if job := jobOf(d, map[*Suite]bool{}); job != nil && job != s {
+				needs[g.suiteID(job)] = true
+			}
+		}
+
+		job := githubJob{ID: g.suiteID(s), Name: g.suiteKey(s)}
+		for id := range needs {
+			job.Needs = append(job.Needs, id)
+		}
+		sort.Strings(job.Needs)
+		dir := g.workflowPath(filepath.Dir(s.Location))
+		if g.conf.Bash {
+			job.Run = []string{dir + "/" + BashSuiteScript + " all"}
+			job.Logs = []string{dir + "/logs/", dir + "/junit.xml"}
+		} else {
+			log := "gotestmd-logs/" + job.ID + ".log"
+			job.Run = []string{
+				"mkdir -p gotestmd-logs",
+				"go test -v -count=1 " + dir + " -run '^Test" + s.entrypointTitle() + "$' 2>&1 | tee " + log,
+			}
+			job.Logs = []string{log}
+		}
+		result = append(result, job)
+	}
+
+	return g.templates.executeString(GitHubWorkflowTemplateName, struct {
+		Name string
+		Bash bool
+		Jobs []githubJob
+	}{
+		Name: name,
+		Bash: g.conf.Bash,
+		Jobs: result,
+	})
+}
+
+// workflowPath returns the path relative to the root of the repository with the ./ prefix
+func (g *Generator) workflowPath(p string) string {
+	p = filepath.ToSlash(p)
+	if path.IsAbs(p) {
+		return p
+	}
+	return "./" + path.Clean(p)
+}
+
+// subtree returns the suite with all the suites included by it
+func (s *Suite) subtree() []*Suite {
+	var result = []*Suite{s}
+	for _, c := range s.Children {
+		result = append(result, c.subtree()...)
+	}
+	return result
+}
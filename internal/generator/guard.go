@@ -0,0 +1,46 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import "sort"
+
+// Guards returns env variables that should be set to run the suite. The suite also can't run without the variables
+// of the suites it requires
+func (s *Suite) Guards() []string {
+	var envs = map[string]struct{}{}
+	s.collectGuards(envs, map[*Suite]bool{})
+
+	var result []string
+	for env := range envs {
+		result = append(result, env)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (s *Suite) collectGuards(envs map[string]struct{}, visited map[*Suite]bool) {
+	if s == nil || visited[s] {
+		return
+	}
+	visited[s] = true
+	for _, env := range s.SkipUnlessEnv {
+		envs[env] = struct{}{}
+	}
+	for _, parent := range s.Parents {
+		parent.collectGuards(envs, visited)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+// SuiteInfo describes a generated suite for listing
+type SuiteInfo struct {
+	// Name is the key of the suite used by the make targets, e.g. tree-subtree for OUTPUT_DIR/tree/subtree
+	Name     string `json:"name"`
+	Dir      string `json:"dir"`
+	Location string `json:"location"`
+	Source   string `json:"source"`
+	// Tests are the names of the tests in the order they run
+	Tests []string `json:"tests"`
+	// BuildConstraint is the go:build expression of the suite
+	BuildConstraint string `json:"buildConstraint,omitempty"`
+	// Envs are the env variables that should be set to run the suite
+	Envs []string `json:"envs,omitempty"`
+	// Includes and Requires are names of the included suites and the suites to set up first
+	Includes   []string `json:"includes"`
+	Requires   []string `json:"requires"`
+	Entrypoint bool     `json:"entrypoint"`
+}
+
+// List describes the suites in their order
+func (g *Generator) List(suites []*Suite) []*SuiteInfo {
+	var result = []*SuiteInfo{}
+	for _, s := range suites {
+		info := &SuiteInfo{
+			Name:            g.suiteKey(s),
+			Dir:             s.Dir,
+			Location:        s.Location,
+			Source:          s.Source.Path,
+			Tests:           []string{},
+			BuildConstraint: s.BuildConstraint(),
+			Envs:            s.Guards(),
+			Includes:        []string{},
+			Requires:        []string{},
+			Entrypoint:      s.Entrypoint,
+		}
+		for _, t := range s.Tests {
+			info.Tests = append(info.Tests, t.Name)
+		}
+		for _, child := range s.sortedChildren() {
+			info.Includes = append(info.Includes, g.suiteKey(child))
+		}
+		for _, parent := range s.Parents {
+			if parent != nil {
+				info.Requires = append(info.Requires, g.suiteKey(parent))
+			}
+		}
+		result = append(result, info)
+	}
+	return result
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/networkservicemesh/gotestmd/internal/config"
+)
+
+// MakefileName is the name of the Makefile fragment with targets running the generated suites
+const MakefileName = "gotestmd.mk"
+
+type makeTarget struct {
+	Name    string
+	Command string
+}
+
+// MakefileString returns the Makefile fragment with test-SUITE and test-SUITE-TEST targets running the suites with
+// go test or their bash scripts. Go suites get targets only if they have entrypoints
+func (g *Generator) MakefileString(suites []*Suite) (string, error) {
+	var all = "cd $(GOTESTMD_DIR) && go test $(GOTESTMD_GO_TEST_FLAGS) ./..."
+	if g.conf.Bash {
+		all = "$(GOTESTMD_DIR)/" + BashRunAllScript
+	}
+
+	var targets []makeTarget
+	for _, s := range suites {
+		if !g.conf.Bash && !s.Entrypoint {
+			continue
+		}
+		dir := g.relativeDir(s)
+		name := "test-" + g.suiteKey(s)
+
+		targets = append(targets, makeTarget{Name: name, Command: g.makeCommand(s, dir, nil)})
+		for _, t := range s.Tests {
+			if t.Name == "" {
+				continue
+			}
+			targets = append(targets, makeTarget{Name: name + "-" + strings.ToLower(t.Name), Command: g.makeCommand(s, dir, t)})
+		}
+	}
+
+	return g.templates.executeString(MakefileTemplateName, struct {
+		All     string
+		Targets []makeTarget
+	}{
+		All:     all,
+		Targets: targets,
+	})
+}
+
+// makeCommand returns the recipe running the suite or its test
+func (g *Generator) makeCommand(s *Suite, dir string, t *Test) string {
+	if g.conf.Bash {
+		command := "$(GOTESTMD_DIR)/" + dir + "/" + BashSuiteScript + " all"
+		if t != nil {
+			command += " " + t.Name
+		}
+		return command
+	}
+	run := "^Test" + s.entrypointTitle() + "$$"
+	if t != nil {
+		switch g.conf.Format {
+		case config.FormatTestify:
+			run += "/^Test" + t.Name + "$$"
+		case config.FormatTesting:
+			run += "/^" + t.Name + "$$"
+		}
+	}
+	return "cd $(GOTESTMD_DIR) && go test $(GOTESTMD_GO_TEST_FLAGS) ./" + dir + " -run '" + run + "'"
+}
+
+// relativeDir returns the dir of the suite relative to the output dir. Suites without a file return their Path
+func (g *Generator) relativeDir(s *Suite) string {
+	if s.Location == "" {
+		return s.Path
+	}
+	dir, err := filepath.Rel(g.conf.OutputDir, filepath.Dir(s.Location))
+	if err != nil {
+		dir = filepath.Dir(s.Location)
+	}
+	return filepath.ToSlash(dir)
+}
+
+// suiteKey returns the name of the suite in generated build files: its dir relative to the output dir in lower case with
+// - instead of /. Suites of a single package are named by their types
+func (g *Generator) suiteKey(s *Suite) string {
+	if s.Package != "" {
+		return strings.ToLower(s.entrypointTitle())
+	}
+	return strings.ToLower(strings.ReplaceAll(g.relativeDir(s), "/", "-"))
+}
+
+var suiteIDRegex = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// suiteID returns the suite key usable as an id of a CI job or a service
+func (g *Generator) suiteID(s *Suite) string {
+	return withDigitPrefix("_", strings.Trim(suiteIDRegex.ReplaceAllString(g.suiteKey(s), "-"), "-"))
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import "regexp"
+
+// Match returns the suites matching the regex by name or having tests matching it with all their tests, and the
+// suites they include and require recursively, so the generated suites compile. Returns nil if nothing matches
+func Match(suites []*Suite, matchRegex *regexp.Regexp) []*Suite {
+	return Closure(suites, func(suite *Suite) bool {
+		if matchRegex.MatchString(suite.Name()) {
+			return true
+		}
+		for _, test := range suite.Tests {
+			if matchRegex.MatchString(test.Name) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Closure returns the selected suites and the suites they include and require recursively in the order of the suites
+func Closure(suites []*Suite, selected func(suite *Suite) bool) []*Suite {
+	// Remote suites are generated in their own modules
+	var generated = map[*Suite]bool{}
+	for _, suite := range suites {
+		generated[suite] = true
+	}
+	var matched = map[*Suite]bool{}
+	var add func(suite *Suite)
+	add = func(suite *Suite) {
+		if matched[suite] || !generated[suite] {
+			return
+		}
+		matched[suite] = true
+		for _, child := range suite.Children {
+			add(child)
+		}
+		for _, parent := range suite.Parents {
+			add(parent)
+		}
+	}
+	for _, suite := range suites {
+		if selected(suite) {
+			add(suite)
+		}
+	}
+
+	var result []*Suite
+	for _, suite := range suites {
+		if matched[suite] {
+			result = append(result, suite)
+		}
+	}
+	return result
+}
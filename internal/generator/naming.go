@@ -0,0 +1,113 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+
+	"github.com/networkservicemesh/gotestmd/internal/config"
+)
+
+// words returns the name in the case style of the naming rules without the prefix and suffix
+func words(n config.Naming, name string) string {
+	switch n.Case {
+	case config.NamingCamel:
+		var result strings.Builder
+		for _, piece := range nameRegex.Split(name, -1) {
+			_, _ = result.WriteString(upperFirst(piece))
+		}
+		return result.String()
+	case config.NamingPreserve:
+		return upperFirst(nameRegex.ReplaceAllString(name, n.Separator))
+	default:
+		return cases.Title(language.AmericanEnglish).String(nameRegex.ReplaceAllString(name, n.Separator))
+	}
+}
+
+// identifier returns the name of a test or a suite following the naming rules
+func identifier(n config.Naming, name string) string {
+	return n.Prefix + words(n, name) + n.Suffix
+}
+
+// typeName returns the unique name of the suite type for the example path, e.g. ProducerConsumer2Suite
+func typeName(n config.Naming, name string) string {
+	var result strings.Builder
+	for _, piece := range strings.Split(name, "/") {
+		_, _ = result.WriteString(words(n, piece))
+	}
+	_, _ = result.WriteString("Suite")
+	return withDigitPrefix(n.DigitPrefix, result.String())
+}
+
+// withDigitPrefix adds the prefix to the identifier starting with a digit
+func withDigitPrefix(prefix, s string) string {
+	if s != "" && unicode.IsDigit(rune(s[0])) {
+		return prefix + s
+	}
+	return s
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// CheckNames fails if different examples are mapped to the same generated package, type, file, test or subtest
+func CheckNames(suites []*Suite) error {
+	var packages = map[Dependency]*Suite{}
+	var locations = map[string]*Suite{}
+	var types = map[string]*Suite{}
+	for _, s := range suites {
+		if other, ok := packages[s.importPath]; ok && s.importPath != "" {
+			return errors.Errorf("examples %v and %v are generated into the same package %v", other.Dir, s.Dir, s.importPath)
+		}
+		packages[s.importPath] = s
+		if other, ok := locations[s.Location]; ok {
+			return errors.Errorf("examples %v and %v are generated into the same file %v", other.Dir, s.Dir, s.Location)
+		}
+		locations[s.Location] = s
+		if s.Package != "" {
+			if other, ok := types[s.Type]; ok {
+				return errors.Errorf("examples %v and %v are generated into the same type %v", other.Dir, s.Dir, s.Type)
+			}
+			types[s.Type] = s
+		}
+
+		var tests = map[string]*Test{}
+		for _, t := range s.Tests {
+			if other, ok := tests[t.Name]; ok {
+				return errors.Errorf("examples %v and %v are generated into the same test %v of suite %v", other.Dir, t.Dir, t.Name, s.Dir)
+			}
+			tests[t.Name] = t
+		}
+		var children = map[string]*Suite{}
+		for _, child := range s.Children {
+			if other, ok := children[child.Title()]; ok {
+				return errors.Errorf("examples %v and %v are generated into the same subtest %v of suite %v", other.Dir, child.Dir, child.Title(), s.Dir)
+			}
+			children[child.Title()] = child
+		}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// PowerShellScript is the name of the PowerShell script of a suite
+const PowerShellScript = "suite.ps1"
+
+// psPart is a section of an example run in its dir
+type psPart struct {
+	Message string
+	Dir     string
+	Source  Source
+	Section string
+	Body    Body
+	Lines   []int
+}
+
+// PowerShellString returns suite.ps1 running the setup, the tests and the cleanup of the suite selected by its arguments
+func (s *Suite) PowerShellString() (string, error) {
+	var setup, cleanup []psPart
+	for _, p := range s.Parents {
+		setup = append(setup, p.psDependenciesSetup()...)
+	}
+	var cleanupTests []psPart
+	for i, t := range s.RequiredTests {
+		setup = append(setup, psPart{Message: "setup test " + t.Name, Dir: t.Dir, Source: t.Source, Section: "Run", Body: t.Run, Lines: t.RunLines})
+		last := s.RequiredTests[len(s.RequiredTests)-1-i]
+		cleanupTests = append(cleanupTests, psPart{Message: "cleanup test " + last.Name, Dir: last.Dir, Source: last.Source, Section: "Cleanup", Body: last.Cleanup, Lines: last.CleanupLines})
+	}
+	setup = append(setup, psPart{Message: "setup suite " + s.scriptName(), Dir: s.Dir, Source: s.Source, Section: "Run", Body: s.Run, Lines: s.RunLines})
+	cleanup = append(cleanup, psPart{Message: "cleanup suite " + s.scriptName(), Dir: s.Dir, Source: s.Source, Section: "Cleanup", Body: s.Cleanup, Lines: s.CleanupLines})
+	cleanup = append(cleanup, cleanupTests...)
+	for _, p := range s.Parents {
+		cleanup = append(cleanup, p.psDependenciesCleanup()...)
+	}
+
+	type testData struct {
+		Name    string
+		Run     string
+		Cleanup string
+	}
+	var tests []testData
+	for _, t := range s.Tests {
+		var run, after []psPart
+		if len(s.BeforeEach) > 0 {
+			run = append(run, psPart{Dir: s.Dir, Source: s.Source, Section: "Before each", Body: s.BeforeEach, Lines: s.BeforeEachLines})
+		}
+		run = append(run, psPart{Dir: t.Dir, Source: t.Source, Section: "Run", Body: t.Run, Lines: t.RunLines})
+		after = append(after, psPart{Dir: t.Dir, Source: t.Source, Section: "Cleanup", Body: t.Cleanup, Lines: t.CleanupLines})
+		if len(s.AfterEach) > 0 {
+			after = append(after, psPart{Dir: s.Dir, Source: s.Source, Section: "After each", Body: s.AfterEach, Lines: s.AfterEachLines})
+		}
+		tests = append(tests, testData{Name: psQuote(t.Name), Run: psString(s.BashDir(), run, false, "\t\t\t"), Cleanup: psString(s.BashDir(), after, true, "\t\t\t")})
+	}
+
+	return s.templates.executeString(PowerShellTemplateName, struct {
+		Header  string
+		Name    string
+		Setup   string
+		Cleanup string
+		Tests   []testData
+	}{
+		Header:  s.header("#"),
+		Name:    s.scriptName(),
+		Setup:   psString(s.BashDir(), setup, false, "\t"),
+		Cleanup: psString(s.BashDir(), cleanup, true, "\t"),
+		Tests:   tests,
+	})
+}
+
+func (s *Suite) psDependenciesSetup() []psPart {
+	var result []psPart
+	for _, p := range s.Parents {
+		result = append(result, p.psDependenciesSetup()...)
+	}
+	return append(result, psPart{Message: "setup suite " + s.scriptName(), Dir: s.Dir, Source: s.Source, Section: "Run", Body: s.Run, Lines: s.RunLines})
+}
+
+func (s *Suite) psDependenciesCleanup() []psPart {
+	var result = []psPart{{Message: "cleanup suite " + s.scriptName(), Dir: s.Dir, Source: s.Source, Section: "Cleanup", Body: s.Cleanup, Lines: s.CleanupLines}}
+	for _, p := range s.Parents {
+		result = append(result, p.psDependenciesCleanup()...)
+	}
+	return result
+}
+
+// psString returns the PowerShell statements of the script in the dir running the parts. Steps of best effort parts
+// don't stop on failures
+func psString(scriptDir string, parts []psPart, bestEffort bool, indent string) string {
+	var sb strings.Builder
+	for _, part := range parts {
+		if len(part.Body) == 0 {
+			continue
+		}
+		if part.Message != "" {
+			sb.WriteString(indent + "Write-Host " + psQuote(part.Message) + "\n")
+		}
+		sb.WriteString(indent + psSetLocation(scriptDir, part.Dir) + "\n")
+		for i, block := range part.Body {
+			location := psQuote(part.Body.stepLocation(i, part.Source, part.Section, part.Lines))
+			stepIndent := indent
+			if bestEffort {
+				sb.WriteString(indent + "try {\n")
+				stepIndent += "\t"
+			}
+			sb.WriteString(stepIndent + "Write-Step " + location + " " + psQuote(block) + "\n")
+			for _, line := range strings.Split(block, "\n") {
+				sb.WriteString(stepIndent + line + "\n")
+			}
+			sb.WriteString(stepIndent + "Assert-ExitCode " + location + "\n")
+			if bestEffort {
+				sb.WriteString(indent + "} catch {\n" + indent + "\tWrite-Failure $_\n" + indent + "}\n")
+			}
+		}
+	}
+	return sb.String()
+}
+
+// psQuote returns the string as a single-quoted PowerShell string. PowerShell also treats typographic single quotes
+// psSetLocation returns the command changing the dir. The dir is relative to the dir of the script, so the script can be
+// run in any checkout of the repository
+func psSetLocation(scriptDir, dir string) string {
+	absScriptDir, _ := filepath.Abs(scriptDir)
+	absDir, _ := filepath.Abs(dir)
+	rel, err := filepath.Rel(absScriptDir, absDir)
+	if err != nil {
+		return "Set-Location -LiteralPath " + psQuote(absDir)
+	}
+	return "Set-Location -LiteralPath (Join-Path $PSScriptRoot " + psQuote(rel) + ")"
+}
+
+// as quotes, so they are doubled too
+func psQuote(s string) string {
+	var sb strings.Builder
+	sb.WriteString("'")
+	for _, r := range s {
+		switch r {
+		case '\'', '‘', '’', '‚', '‛':
+			sb.WriteRune(r)
+		}
+		sb.WriteRune(r)
+	}
+	sb.WriteString("'")
+	return sb.String()
+}
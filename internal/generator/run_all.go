@@ -0,0 +1,77 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"path/filepath"
+)
+
+// BashRunAllScript is the name of the bash script running all generated suites
+const BashRunAllScript = "run-all.sh"
+
+// BashRunAllString returns run-all.sh of the output dir running suite.sh of the suites. Required suites are run before
+// the suites requiring them, other suites keep their order
+func (g *Generator) BashRunAllString(suites []*Suite) (string, error) {
+	var dirs []string
+	for _, s := range bashOrder(suites) {
+		dir, err := filepath.Rel(g.conf.OutputDir, s.BashDir())
+		if err != nil {
+			dir = s.BashDir()
+		}
+		dirs = append(dirs, bashQuote(filepath.ToSlash(dir)))
+	}
+
+	return g.templates.executeString(BashRunAllTemplateName, struct {
+		Suites []string
+	}{
+		Suites: dirs,
+	})
+}
+
+// bashOrder returns the suites with the suites they require and the suites including them placed before them
+func bashOrder(suites []*Suite) []*Suite {
+	var selected = map[*Suite]bool{}
+	var includers = map[*Suite][]*Suite{}
+	for _, s := range suites {
+		selected[s] = true
+		for _, c := range s.Children {
+			includers[c] = append(includers[c], s)
+		}
+	}
+	var result []*Suite
+	var visited = map[*Suite]bool{}
+	var visit func(s *Suite)
+	visit = func(s *Suite) {
+		if visited[s] {
+			return
+		}
+		visited[s] = true
+		for _, p := range s.Parents {
+			visit(p)
+		}
+		for _, p := range includers[s] {
+			visit(p)
+		}
+		if selected[s] {
+			result = append(result, s)
+		}
+	}
+	for _, s := range suites {
+		visit(s)
+	}
+	return result
+}
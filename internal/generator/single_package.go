@@ -0,0 +1,112 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"strings"
+)
+
+// singlePackageFile returns the name of the file of the suite for the example name, e.g. producer_consumer2.gen.go
+func singlePackageFile(name string) string {
+	if name = strings.Trim(normalizeName(name), "_"); name == "" {
+		name = "suite"
+	}
+	return name + ".gen.go"
+}
+
+// typeName returns the name of the suite type
+func (s *Suite) typeName() string {
+	if s.Type == "" {
+		return "Suite"
+	}
+	return s.Type
+}
+
+// fieldName returns the name of the field with the suite in the suites including or requiring it
+func (s *Suite) fieldName() string {
+	if s.Package == "" {
+		return s.Name() + "Suite"
+	}
+	return strings.ToLower(s.Type[:1]) + s.Type[1:]
+}
+
+// packageName returns the name of the package of the suite
+func (s *Suite) packageName() string {
+	if s.Package == "" {
+		return s.Name()
+	}
+	return s.Package
+}
+
+// local returns the suite from the same package that the dependency refers to or nil if it should be imported
+func (s *Suite) local(d Dependency) *Suite {
+	if s.Package == "" {
+		return nil
+	}
+	for _, other := range append(append([]*Suite(nil), s.Children...), s.Parents...) {
+		if other.importPath == d && other.Package == s.Package {
+			return other
+		}
+	}
+	return nil
+}
+
+// importsString returns the imports of the suite skipping the suites from the same package
+func (s *Suite) importsString() string {
+	var deps Dependencies
+	for _, d := range s.Deps {
+		if s.local(d) == nil {
+			deps = append(deps, d)
+		}
+	}
+	if s.usesShell() && !s.Deps.contains(shellPkg) {
+		deps = append(deps, shellPkg)
+	}
+	return deps.String()
+}
+
+// fieldsString returns the fields of the suite with the suites it includes or requires
+func (s *Suite) fieldsString() string {
+	if s.Package == "" || len(s.Deps) == 0 {
+		return s.Deps.FieldsString(s.BaseType)
+	}
+	var lines = []string{s.Deps[:1].FieldsString(s.BaseType)}
+	for _, d := range s.Deps[1:] {
+		if other := s.local(d); other != nil {
+			lines = append(lines, other.fieldName()+" "+other.typeName())
+			continue
+		}
+		lines = append(lines, d.Name()+"Suite "+d.Name()+".Suite")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// setupString returns the setup of the base suite and the suites required by the suite
+func (s *Suite) setupString() string {
+	if s.Package == "" || len(s.DepsToSetup) < 2 {
+		return s.DepsToSetup.SetupString(s.BaseType, s.PlainBase)
+	}
+	var fields []string
+	for _, d := range s.DepsToSetup[1:] {
+		if other := s.local(d); other != nil {
+			fields = append(fields, "&s."+other.fieldName())
+			continue
+		}
+		fields = append(fields, "&s."+d.Name()+"Suite")
+	}
+	return parentsSetupString(s.BaseType, fields, s.PlainBase)
+}
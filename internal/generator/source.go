@@ -0,0 +1,101 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+)
+
+// Source is a markdown file the generated code is based on
+type Source struct {
+	// Path is the path of the source relative to the module root. It is written to the headers of the generated files
+	Path string
+	Hash string
+	// File is the path of the source as it was read
+	File string
+}
+
+func (g *Generator) newSource(e *linker.LinkedExample) Source {
+	path := e.Source
+	if !linker.IsURL(path) {
+		path = g.relPath(path)
+	}
+	return Source{
+		Path: path,
+		Hash: e.Hash,
+		File: e.Source,
+	}
+}
+
+// relPath returns the path relative to the module root with forward slashes. Paths outside the module are kept as is
+func (g *Generator) relPath(p string) string {
+	if g.root == "" || p == "" {
+		return filepath.ToSlash(p)
+	}
+	rel, err := filepath.Rel(g.root, p)
+	if err != nil && filepath.IsAbs(g.root) {
+		var absPath string
+		if absPath, err = filepath.Abs(p); err == nil {
+			rel, err = filepath.Rel(g.root, absPath)
+		}
+	}
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(p)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// String returns the source in the PATH HASH format
+func (s Source) String() string {
+	return s.Path + " " + s.Hash
+}
+
+// Sources returns unique sources of the suite, its required tests and tests
+func (s *Suite) Sources() []Source {
+	var result []Source
+	var visited = map[string]bool{}
+	add := func(source Source) {
+		if source.Path == "" || visited[source.Path] {
+			return
+		}
+		visited[source.Path] = true
+		result = append(result, source)
+	}
+	add(s.Source)
+	for _, t := range s.RequiredTests {
+		add(t.Source)
+	}
+	for _, t := range s.Tests {
+		add(t.Source)
+	}
+	return result
+}
+
+// header returns the sources of the suite as comments starting with the prefix
+func (s *Suite) header(prefix string) string {
+	var result strings.Builder
+	for _, source := range s.Sources() {
+		_, _ = result.WriteString(prefix)
+		_, _ = result.WriteString(" Source: ")
+		_, _ = result.WriteString(source.String())
+		_, _ = result.WriteString("\n")
+	}
+	return result.String()
+}
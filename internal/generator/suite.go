@@ -20,100 +20,316 @@ package generator
 
 import (
 	"fmt"
+	"io"
 	"path"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
-	"text/template"
+	"time"
 
+	"github.com/sirupsen/logrus"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
 )
 
-const suiteTemplate = `// Code generated by gotestmd DO NOT EDIT.
-package {{ .Name }}
+// testifyLogf logs the steps of testify suites
+const testifyLogf = "s.T().Logf"
+
+// Body represents a body of the method
+type Body []string
 
-import(
-	{{ .Imports }}
+// String returns the body as part of the method
+func (b Body) String() string {
+	return b.callString(runCall, runOptions{})
+}
+
+// Methods of the runner running the blocks
+const (
+	runCall     = "r.Run"
+	runSoftCall = "r.RunSoft"
 )
 
-type Suite struct {
-	{{ .Fields }}
+// shellPkg is the package of the runner options
+const shellPkg = Dependency("github.com/networkservicemesh/gotestmd/pkg/suites/shell")
+
+// runnerSetupString returns the calls of the runner choosing the image of the container running the commands,
+// setting the env variables and registering the artifacts
+func runnerSetupString(image string, env, artifacts map[string]string) string {
+	var result []string
+	if image != "" {
+		result = append(result, "r.UseImage("+strconv.Quote(image)+")")
+	}
+	for _, key := range parser.SortedKeys(env) {
+		result = append(result, "r.Setenv("+strconv.Quote(key)+", "+strconv.Quote(env[key])+")")
+	}
+	for _, name := range parser.SortedKeys(artifacts) {
+		result = append(result, "r.Artifact("+strconv.Quote(name)+", "+strconv.Quote(artifacts[name])+")")
+	}
+	return strings.Join(result, "\n")
 }
 
-func (s *Suite) SetupSuite() {
-	{{ .Setup }}
-	{{ if or .Run .Cleanup }}
-	r := s.Runner("{{.Dir}}")
-	{{ end }}
-	{{ .Cleanup }}
-	{{ .Run }}
+// snapshotString returns the call taking the state checked after each test or an empty string if nothing is checked
+func snapshotString(isolate *parser.Isolate) string {
+	if isolate == nil {
+		return ""
+	}
+	var options []string
+	if isolate.Env {
+		options = append(options, "shell.SnapshotEnv()")
+	}
+	if len(isolate.Namespaces) > 0 {
+		var namespaces []string
+		for _, ns := range isolate.Namespaces {
+			namespaces = append(namespaces, strconv.Quote(ns))
+		}
+		options = append(options, "shell.SnapshotNamespaces("+strings.Join(namespaces, ", ")+")")
+	}
+	if len(options) == 0 {
+		return ""
+	}
+	return "s.SnapshotState(" + strings.Join(options, ", ") + ")"
+}
 
-{{ if .TestIncludedSuites }}
-	s.RunIncludedSuites()
+// runOptions are the options of the runner calls running the blocks
+type runOptions struct {
+	// timeout limits each attempt of the commands
+	timeout time.Duration
+	// retry sets how the failed commands are retried
+	retry *parser.Retry
+	// exitCode is the exit code the commands are expected to fail with, see parser.FrontMatter.ExitCode
+	exitCode string
 }
 
-func (s *Suite) RunIncludedSuites() {
-	{{ .TestIncludedSuites }}
-{{ end }}
+// call returns the suffix of the runner method accepting the options and the arguments following the block
+func (o runOptions) call() (suffix string, args []string) {
+	if o.retry == nil && o.exitCode == "" {
+		if o.timeout > 0 {
+			return "WithTimeout", []string{durationString(o.timeout)}
+		}
+		return "", nil
+	}
+	args = append(o.retryArgs(), o.exitCodeArgs()...)
+	if o.timeout > 0 {
+		args = append(args, "shell.WithAttemptTimeout("+durationString(o.timeout)+")")
+	}
+	return "Retry", args
 }
-`
 
-const includedSuiteTemplate = `
-	{{ range .Suites }}
-		s.Run("{{ .Title }}", func() {
-			suite.Run(s.T(), &s.{{ .Name }}Suite)
-		})
-	{{ end }}
-`
+// retryArgs returns the arguments setting the retries
+func (o runOptions) retryArgs() (args []string) {
+	if o.retry == nil {
+		return nil
+	}
+	if o.retry.Timeout > 0 {
+		args = append(args, "shell.WithRetryTimeout("+durationString(o.retry.Timeout)+")")
+	}
+	if o.retry.Interval > 0 {
+		args = append(args, "shell.WithInterval("+durationString(o.retry.Interval)+")")
+	}
+	if o.retry.Backoff > 0 {
+		maxInterval := "0"
+		if o.retry.MaxInterval > 0 {
+			maxInterval = durationString(o.retry.MaxInterval)
+		}
+		args = append(args, "shell.WithBackoff("+strconv.FormatFloat(o.retry.Backoff, 'g', -1, 64)+", "+maxInterval+")")
+	}
+	if o.retry.Until != "" {
+		args = append(args, "shell.WithOutputMatching("+strconv.Quote(o.retry.Until)+")")
+	}
+	return args
+}
 
-// Body represents a body of the method
-type Body []string
+// exitCodeArgs returns the arguments setting the expected exit code
+func (o runOptions) exitCodeArgs() []string {
+	switch o.exitCode {
+	case "":
+		return nil
+	case parser.NonZeroExitCode:
+		return []string{"shell.WithExpectedFailure()"}
+	default:
+		return []string{"shell.WithExpectedExitCode(" + o.exitCode + ")"}
+	}
+}
 
-// String returns the body as part of the method
-func (b Body) String() string {
+// usesTime returns true if the arguments of the calls refer to the time package
+func (o runOptions) usesTime() bool {
+	if o.timeout > 0 {
+		return true
+	}
+	return o.retry != nil && o.retry.Timeout+o.retry.Interval+o.retry.MaxInterval > 0
+}
+
+// usesShell returns true if the arguments of the calls refer to the shell package
+func (o runOptions) usesShell() bool {
+	suffix, args := o.call()
+	return suffix == "Retry" && len(args) > 0
+}
+
+// maxConcatLines is the number of lines of the blocks written as concatenations of raw strings. Longer blocks, e.g.
+// embedded manifests, are written as a single quoted string: formatting and compiling a concatenation takes time
+// quadratic in its length, and the concatenation of tens of thousands of lines exceeds the nesting limit of the Go parser
+const maxConcatLines = 100
+
+// callString returns the body as calls of the runner method. If the options are set, the blocks are run by the method
+// accepting them
+func (b Body) callString(call string, o runOptions) string {
 	var sb strings.Builder
+	b.writeCalls(&sb, call, o)
+	return sb.String()
+}
 
+// writeCalls writes the body as calls of the runner method into sb
+func (b Body) writeCalls(sb *strings.Builder, call string, o runOptions) {
 	if len(b) == 0 {
-		return ""
+		return
 	}
 
+	suffix, args := o.call()
 	for _, block := range b {
-		sb.WriteString("r.Run(")
-		var lines = strings.Split(block, "\n")
-		for i, line := range lines {
-			sb.WriteString("`")
-			sb.WriteString(line)
-			sb.WriteString("`")
-			if i+1 < len(lines) {
-				sb.WriteString("+\"\\n\"+")
-			}
+		sb.WriteString(call)
+		sb.WriteString(suffix)
+		sb.WriteString("(")
+		writeBlockString(sb, block)
+		for _, arg := range args {
+			sb.WriteString(", ")
+			sb.WriteString(arg)
 		}
 		sb.WriteString(")\n")
 	}
+}
+
+// writeBlockString writes the block as a Go string expression into sb
+func writeBlockString(sb *strings.Builder, block string) {
+	if strings.Count(block, "\n") >= maxConcatLines {
+		sb.WriteString(strconv.Quote(block))
+		return
+	}
+	for {
+		line, rest, more := strings.Cut(block, "\n")
+		sb.WriteString("`")
+		sb.WriteString(line)
+		sb.WriteString("`")
+		if !more {
+			return
+		}
+		sb.WriteString("+\"\\n\"+")
+		block = rest
+	}
+}
 
+// loggedString returns the body as part of the method. If lines are set, each block is preceded by a call of logf
+// with the location of the block in the source
+func (b Body) loggedString(logf string, source Source, lines []int) string {
+	return b.loggedCallString(logf, runCall, source, lines, runOptions{})
+}
+
+// optionsString returns the body like loggedString with the blocks run with the options
+func (b Body) optionsString(logf string, source Source, lines []int, o runOptions) string {
+	return b.loggedCallString(logf, runCall, source, lines, o)
+}
+
+func (b Body) loggedCallString(logf, call string, source Source, lines []int, o runOptions) string {
+	var sb strings.Builder
+	b.writeLoggedCalls(&sb, logf, call, source, lines, o)
 	return sb.String()
 }
 
-// BashString returns the body as a bash script for the suite
-func (b Body) BashString(withExit bool) string {
+// writeLoggedCalls writes the body like writeCalls with each block preceded by a call of logf if lines are set
+func (b Body) writeLoggedCalls(sb *strings.Builder, logf, call string, source Source, lines []int, o runOptions) {
+	if len(lines) != len(b) {
+		b.writeCalls(sb, call, o)
+		return
+	}
+
+	for i, block := range b {
+		_, _ = fmt.Fprintf(sb, "%v(\"running: %%s\", %q)\n", logf, source.Path+":"+strconv.Itoa(lines[i]))
+		Body{block}.writeCalls(sb, call, o)
+	}
+}
+
+// softString returns the body running the blocks with RunSoft followed by the report of the failed blocks
+func (b Body) softString(logf string, source Source, lines []int, o runOptions) string {
+	if len(b) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	b.writeLoggedCalls(&sb, logf, runSoftCall, source, lines, o)
+	sb.WriteString("r.Report()\n")
+	return sb.String()
+}
+
+// stepsString returns the body as part of a testify test method. If steps is set, each block is run as a subtest.
+// The following blocks are skipped if a block fails unless soft is set. Setup isn't split, as subtests can be filtered out by -run
+func (b Body) stepsString(source Source, lines []int, steps, soft bool, o runOptions) string {
+	if !steps && soft {
+		return b.softString(testifyLogf, source, lines, o)
+	}
+	if !steps {
+		return b.optionsString(testifyLogf, source, lines, o)
+	}
+
+	var sb strings.Builder
+	for i, block := range b {
+		var blockLines []int
+		if len(lines) == len(b) {
+			blockLines = lines[i : i+1]
+		}
+		if soft {
+			_, _ = fmt.Fprintf(&sb, "s.Run(%q, func() {\n", stepName(i, block))
+			Body{block}.writeLoggedCalls(&sb, testifyLogf, runCall, source, blockLines, o)
+			sb.WriteString("})\n")
+			continue
+		}
+		_, _ = fmt.Fprintf(&sb, "if !s.Run(%q, func() {\n", stepName(i, block))
+		Body{block}.writeLoggedCalls(&sb, testifyLogf, runCall, source, blockLines, o)
+		sb.WriteString("}) {\ns.T().FailNow()\n}\n")
+	}
+
+	return sb.String()
+}
+
+// stepName returns the name of the subtest running the block in the step-N-description format. The description is
+// taken from the first line of the block
+func stepName(i int, block string) string {
+	const maxDescriptionLen = 40
+
+	firstLine := strings.SplitN(block, "\n", 2)[0]
+	description := strings.Trim(nameRegex.ReplaceAllString(strings.ToLower(firstLine), "-"), "-")
+	if len(description) > maxDescriptionLen {
+		description = strings.TrimRight(description[:maxDescriptionLen], "-")
+	}
+
+	name := "step-" + strconv.Itoa(i+1)
+	if description != "" {
+		name += "-" + description
+	}
+	return name
+}
+
+// BashString returns the body as a part of a bash function. Scripts run with set -e, so the first failed command stops
+// them. If bestEffort is set, the commands are run with set +eu, so failed commands are reported and the next ones still run
+func (b Body) BashString(bestEffort bool) string {
 	var sb strings.Builder
 
 	if len(b) == 0 {
 		return "\t:\n"
 	}
 
+	if bestEffort {
+		sb.WriteString("\tset +eu\n")
+	}
 	for _, block := range b {
-		var lines = strings.Split(block, "\n")
-		sb.WriteString("\t")
-		sb.WriteString(lines[0])
-		for i := 1; i < len(lines); i++ {
-			sb.WriteString(" &&\n\t")
-			sb.WriteString(lines[i])
-		}
-		if withExit {
-			sb.WriteString(" || exit")
+		for _, line := range strings.Split(block, "\n") {
+			sb.WriteString("\t")
+			sb.WriteString(line)
+			sb.WriteString("\n")
 		}
-		sb.WriteString("\n")
+	}
+	if bestEffort {
+		sb.WriteString("\tset -eu\n")
 	}
 
 	return sb.String()
@@ -121,193 +337,462 @@ func (b Body) BashString(withExit bool) string {
 
 // Suite represents a template for generating a testify suite.Suite
 type Suite struct {
-	Dir      string
-	Location string
+	Dir string
+	// RunnerDir is Dir relative to the module root. The runners of the generated Go code resolve it against the module root
+	RunnerDir string
+	Location  string
+	// Path is the dir of Location relative to the output dir of the suite. Generated scripts and their reports name the
+	// suite by it, so they don't depend on the location of the output dir
+	Path string
 	Dependency
-	Cleanup     Body
-	Run         Body
+	Cleanup Body
+	Run     Body
+	// BeforeEach and AfterEach run before and after each test of the suite
+	BeforeEach  Body
+	AfterEach   Body
 	Tests       []*Test
 	Children    []*Suite
 	Parents     []*Suite
 	Deps        Dependencies
 	DepsToSetup Dependencies
+	// RequiredTests are tests of other suites to run before the suite
+	RequiredTests []*Test
+	// Priority orders the suite among its siblings
+	Priority int
+	// BaseType is the type of the base package embedded by the suite
+	BaseType string
+	// PlainBase is set if the base type is a testify suite without the Cleanup and SetupParents methods of shell.Suite
+	PlainBase bool
+	// Source is the markdown file of the suite
+	Source Source
+	// Parallel is set if the suite can run in parallel with other included suites of its parent
+	Parallel bool
+	// BuildTags are go:build constraints of the suite example
+	BuildTags []string
+	// Entrypoint is set if a test file running the suite should be generated
+	Entrypoint bool
+	// Timeout limits the time of the suite setup, tests and included suites
+	Timeout time.Duration
+	// CommandTimeout limits the time of each command of the suite setup and cleanup
+	CommandTimeout time.Duration
+	// Retry sets how the failed commands of the suite setup are retried
+	Retry *parser.Retry
+	// ExitCode is the exit code the commands of the suite setup are expected to fail with
+	ExitCode string
+	// Env are the env variables set by the runners of the suite
+	Env map[string]string
+	// Artifacts are the commands collected by the runners of the suite when it fails
+	Artifacts map[string]string
+	// Image is the image of the container running the commands of the suite
+	Image string
+	// Isolate sets the state checked and restored after each test of the suite
+	Isolate *parser.Isolate
+	// BashJobs is the default number of tests run in parallel by suite.sh
+	BashJobs int
+	// RunLines and CleanupLines are the lines of the blocks in the source. They are set if the steps are logged
+	RunLines        []int
+	CleanupLines    []int
+	BeforeEachLines []int
+	AfterEachLines  []int
+	// SkipUnlessEnv are env variables that should be set to run the suite
+	SkipUnlessEnv []string
+	// Type is the name of the suite type. It's unique if the suites are generated into a single package
+	Type string
+	// Package is the name of the package shared by all suites. It's empty if each suite has its own package
+	Package string
+	// title is the name of the suite following the naming rules
+	title string
+	// importPath is the dependency other suites refer to the suite by
+	importPath Dependency
+	templates  *templateSet
 }
 
-func (s *Suite) generateChildrenTesting() string {
-	tmpl, err := template.New("test").Parse(includedSuiteTemplate)
-	if err != nil {
-		panic(err.Error())
+// sortedChildren returns included suites in ascending priority order. Suites with the same priority keep the order of
+// their locations
+func (s *Suite) sortedChildren() []*Suite {
+	children := append([]*Suite(nil), s.Children...)
+	sort.SliceStable(children, func(i, j int) bool {
+		return children[i].Priority < children[j].Priority
+	})
+	return children
+}
+
+// Title returns the name of the suite used for subtests
+func (s *Suite) Title() string {
+	if s.title != "" {
+		return s.title
 	}
+	_, title := path.Split(s.Dir)
+	return cases.Title(language.AmericanEnglish).String(nameRegex.ReplaceAllString(title, "_"))
+}
 
+func (s *Suite) generateChildrenTesting() (string, error) {
 	type suiteData struct {
-		Title string
-		Name  string
+		Title    string
+		Name     string
+		Field    string
+		Parallel bool
 	}
 
 	if len(s.Children) == 0 {
-		return ""
+		return "", nil
 	}
 
 	var suites []*suiteData
-	for _, child := range s.Children {
-		_, title := path.Split(child.Dir)
-		title = cases.Title(language.AmericanEnglish).String(nameRegex.ReplaceAllString(title, "_"))
+	for _, child := range s.sortedChildren() {
 		suite := &suiteData{
-			Title: title,
-			Name:  child.Name(),
+			Title:    child.Title(),
+			Name:     child.Name(),
+			Field:    child.fieldName(),
+			Parallel: child.Parallel,
 		}
 
 		suites = append(suites, suite)
 	}
 
-	var result = new(strings.Builder)
-	err = tmpl.Execute(result, struct {
+	return s.templates.executeString(IncludedSuiteTemplateName, struct {
 		Suites []*suiteData
 	}{
 		Suites: suites,
 	})
-	if err != nil {
-		panic(err.Error())
-	}
-	return result.String()
 }
 
-// String returns a string that contains generated testify.Suite
-func (s *Suite) String() string {
-	tmpl, err := template.New("test").Parse(
-		suiteTemplate,
-	)
+// cleanupFunc returns the function registering the cleanups of the suite. Cleanups of shell.Suite are deferred until
+// the last suite requiring it is torn down
+func (s *Suite) cleanupFunc() string {
+	if s.PlainBase {
+		return "s.T().Cleanup"
+	}
+	return "s.Cleanup"
+}
 
+// WriteTo writes generated testify.Suite into w. Blocks of the suite and its tests are written as they are generated,
+// so the source of the whole file isn't copied
+func (s *Suite) WriteTo(w io.Writer) (int64, error) {
+	requiredTests, err := s.requiredTestsString()
 	if err != nil {
-		panic(err.Error())
+		return 0, err
+	}
+	children, err := s.generateChildrenTesting()
+	if err != nil {
+		return 0, err
 	}
 
-	cleanup := s.Cleanup.String()
+	cleanup := s.Cleanup.optionsString(testifyLogf, s.Source, s.CleanupLines, runOptions{timeout: s.CommandTimeout})
 	if len(cleanup) > 0 {
-		cleanup = fmt.Sprintf(`	s.T().Cleanup(func() {
+		cleanup = fmt.Sprintf(`	%v(func() {
 		%v
-	})`, cleanup)
+	})`, s.cleanupFunc(), cleanup)
 	}
 
-	var result = new(strings.Builder)
+	var result = &squashWriter{w: w}
 
-	_ = tmpl.Execute(result, struct {
+	err = s.templates.execute(result, SuiteTemplateName, struct {
+		Header             string
 		Dir                string
 		Name               string
+		Type               string
+		RunnerSetup        string
 		Cleanup            string
 		Run                string
 		Fields             string
 		Imports            string
 		Setup              string
+		RequiredTests      string
 		TestIncludedSuites string
+		Timeout            string
+		CleanupFunc        string
+		UsesTime           bool
+		Guards             []string
+		BeforeEach         string
+		AfterEach          string
+		Snapshot           string
 	}{
-		Dir:                s.Dir,
-		Name:               s.Name(),
+		Header:             s.goHeader(),
+		Dir:                s.RunnerDir,
+		Name:               s.packageName(),
+		Type:               s.typeName(),
+		RunnerSetup:        runnerSetupString(s.Image, s.Env, s.Artifacts),
 		Cleanup:            cleanup,
-		Run:                s.Run.String(),
-		Imports:            s.Deps.String(),
-		Fields:             s.Deps.FieldsString(),
-		Setup:              s.DepsToSetup.SetupString(),
-		TestIncludedSuites: s.generateChildrenTesting(),
+		Run:                s.Run.optionsString(testifyLogf, s.Source, s.RunLines, s.runOptions()),
+		Imports:            s.importsString(),
+		Fields:             s.fieldsString(),
+		Setup:              s.setupString(),
+		RequiredTests:      requiredTests,
+		TestIncludedSuites: children,
+		Timeout:            durationString(s.Timeout),
+		CleanupFunc:        s.cleanupFunc(),
+		UsesTime:           s.usesTime(),
+		Guards:             s.Guards(),
+		BeforeEach:         s.BeforeEach.optionsString(testifyLogf, s.Source, s.BeforeEachLines, runOptions{timeout: s.CommandTimeout}),
+		AfterEach:          s.AfterEach.optionsString(testifyLogf, s.Source, s.AfterEachLines, runOptions{timeout: s.CommandTimeout}),
+		Snapshot:           snapshotString(s.Isolate),
 	})
+	if err != nil {
+		return result.n, err
+	}
 
 	if len(s.Tests) == 0 {
-		s.Tests = append(s.Tests, new(Test))
+		s.Tests = append(s.Tests, &Test{templates: s.templates})
 	}
 
 	for _, test := range s.Tests {
-		_, _ = result.WriteString(test.String())
+		if test.Parallel {
+			logrus.Warnf("test %v of suite %v is marked as parallel, but testify runs suite tests sequentially. Use --format=testing to run it in parallel", test.Name, s.Pkg())
+		}
+		test.suiteType = s.typeName()
+		if s.Isolate != nil {
+			test.suiteEnv, test.suiteArtifacts = s.Env, s.Artifacts
+		}
+		if _, err := test.WriteTo(result); err != nil {
+			return result.n, err
+		}
 	}
 
-	return spaceRegex.ReplaceAllString(strings.TrimSpace(result.String()), "\n")
+	return result.n, nil
 }
 
-const bashSuiteTemplate = `
-#! /bin/bash
-
-setup_dependencies() {
-{{ .SetupDependencies }}}
-
-setup_main() {
-{{ .SetupMain }}}
+// runOptions returns the options of the Run blocks of the suite
+func (s *Suite) runOptions() runOptions {
+	return runOptions{timeout: s.CommandTimeout, retry: s.Retry, exitCode: s.ExitCode}
+}
 
-setup() {
-	setup_dependencies && setup_main
+// blockOptions returns the options of the Run blocks and the other blocks of the suite, its tests and its required tests
+// that have any blocks
+func (s *Suite) blockOptions() []runOptions {
+	var result []runOptions
+	if len(s.Run) > 0 {
+		result = append(result, s.runOptions())
+	}
+	if len(s.Cleanup)+len(s.BeforeEach)+len(s.AfterEach) > 0 {
+		result = append(result, runOptions{timeout: s.CommandTimeout})
+	}
+	for _, test := range append(append([]*Test(nil), s.Tests...), s.RequiredTests...) {
+		if len(test.Run) > 0 {
+			result = append(result, test.runOptions())
+		}
+		if len(test.Cleanup) > 0 {
+			result = append(result, runOptions{timeout: test.CommandTimeout})
+		}
+	}
+	return result
 }
 
-cleanup_dependencies() {
-{{ .CleanupDependencies }}}
+// usesTime returns true if the runner calls of the suite refer to the time package
+func (s *Suite) usesTime() bool {
+	for _, o := range s.blockOptions() {
+		if o.usesTime() {
+			return true
+		}
+	}
+	return false
+}
 
-cleanup_main() {
-{{ .CleanupMain }}}
+// usesShell returns true if the runner calls or the snapshot of the suite refer to the shell package
+func (s *Suite) usesShell() bool {
+	if snapshotString(s.Isolate) != "" {
+		return true
+	}
+	for _, o := range s.blockOptions() {
+		if o.usesShell() {
+			return true
+		}
+	}
+	return false
+}
 
-cleanup() {
-	cleanup_main && cleanup_dependencies
+func (s *Suite) requiredTestsString() (string, error) {
+	var result = new(strings.Builder)
+	for _, test := range s.RequiredTests {
+		cleanup := test.Cleanup.optionsString(testifyLogf, test.Source, test.CleanupLines, runOptions{timeout: test.CommandTimeout})
+		if len(cleanup) > 0 {
+			cleanup = fmt.Sprintf(`%v(func() {
+			%v
+		})`, s.cleanupFunc(), cleanup)
+		}
+		err := s.templates.execute(result, RequiredTestTemplateName, struct {
+			Dir         string
+			RunnerSetup string
+			Cleanup     string
+			Run         string
+		}{
+			Dir:         test.RunnerDir,
+			RunnerSetup: runnerSetupString(test.Image, test.Env, test.Artifacts),
+			Cleanup:     cleanup,
+			Run:         test.Run.optionsString(testifyLogf, test.Source, test.RunLines, test.runOptions()),
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return result.String(), nil
 }
-`
 
-// BashString generates bash script for the suite
-func (s *Suite) BashString() string {
+// BashString generates the bash functions of the suite and its tests. See BashScripts for the scripts calling them
+func (s *Suite) BashString() (string, error) {
+	scriptDir := s.BashDir()
 	var setupDependencies Body
 	for _, p := range s.Parents {
-		setupDependencies = append(setupDependencies, p.getDependenciesSetup()...)
+		setupDependencies = append(setupDependencies, p.getDependenciesSetup(scriptDir)...)
 	}
 	var cleanupDependencies Body
 	for _, p := range s.Parents {
-		cleanupDependencies = append(cleanupDependencies, p.getDependenciesCleanup()...)
+		cleanupDependencies = append(cleanupDependencies, p.getDependenciesCleanup(scriptDir)...)
+	}
+	var cleanupTests Body
+	for i, t := range s.RequiredTests {
+		setupDependencies = append(setupDependencies, bashEcho("setup test "+t.Name), bashCd(scriptDir, t.Dir))
+		setupDependencies = append(setupDependencies, t.Run.bashSteps(t.Source, "Run", t.RunLines)...)
+
+		last := s.RequiredTests[len(s.RequiredTests)-1-i]
+		cleanupTests = append(cleanupTests, bashEcho("cleanup test "+last.Name), bashCd(scriptDir, last.Dir))
+		cleanupTests = append(cleanupTests, last.Cleanup.bashSteps(last.Source, "Cleanup", last.CleanupLines)...)
 	}
+	cleanupDependencies = append(cleanupTests, cleanupDependencies...)
 
 	absDir, _ := filepath.Abs(s.Dir)
-	s.Run = append([]string{"cd " + absDir}, s.Run...)
-	s.Run = append([]string{fmt.Sprintf("echo 'setup suite %s'", filepath.Dir(s.Location))}, s.Run...)
-	s.Cleanup = append([]string{fmt.Sprintf("echo 'cleanup suite %s'", filepath.Dir(s.Location))}, s.Cleanup...)
-
-	tmpl, err := template.New("test").Parse(bashSuiteTemplate)
-	if err != nil {
-		panic(err.Error())
-	}
+	run := append(Body{bashEcho("setup suite " + s.scriptName()), bashCd(scriptDir, s.Dir)}, s.Run.bashSteps(s.Source, "Run", s.RunLines)...)
+	cleanup := append(Body{bashEcho("cleanup suite " + s.scriptName()), bashCd(scriptDir, s.Dir)}, s.Cleanup.bashSteps(s.Source, "Cleanup", s.CleanupLines)...)
 
 	var result = new(strings.Builder)
 
-	_ = tmpl.Execute(result, struct {
+	err := s.templates.execute(result, BashSuiteTemplateName, struct {
+		Header              string
+		Name                string
 		Dir                 string
 		SetupDependencies   string
 		SetupMain           string
 		CleanupDependencies string
 		CleanupMain         string
 	}{
+		Header:              s.header("#"),
+		Name:                bashQuote(s.scriptName()),
 		Dir:                 absDir,
-		SetupDependencies:   setupDependencies.BashString(true),
-		SetupMain:           s.Run.BashString(true),
-		CleanupDependencies: cleanupDependencies.BashString(false),
-		CleanupMain:         s.Cleanup.BashString(false),
+		SetupDependencies:   setupDependencies.BashString(false),
+		SetupMain:           run.BashString(false),
+		CleanupDependencies: cleanupDependencies.BashString(true),
+		CleanupMain:         cleanup.BashString(true),
 	})
+	if err != nil {
+		return "", err
+	}
 	for _, test := range s.Tests {
-		result.WriteString(test.BashString())
+		function, err := s.bashTest(test).BashString()
+		if err != nil {
+			return "", err
+		}
+		result.WriteString(function)
 	}
-	result.WriteString("\n\n")
-	result.WriteString("\"$1\"\n")
+	result.WriteString("\n")
+
+	return result.String(), nil
+}
 
-	return result.String()
+// Names of the bash scripts of a suite
+const (
+	BashSetupScript   = "setup.sh"
+	BashCleanupScript = "cleanup.sh"
+	BashSuiteScript   = "suite.sh"
+)
+
+// BashTestScript returns the name of the bash script running the test
+func BashTestScript(name string) string {
+	return "test_" + normalizeName(name) + ".sh"
+}
+
+// BashDir returns the dir of the bash scripts of the suite
+func (s *Suite) BashDir() string {
+	return filepath.Dir(s.Location)
 }
 
-func (s *Suite) getDependenciesSetup() []string {
+// scriptName returns the name of the suite in generated scripts: Path or the dir of Location if Path isn't set
+func (s *Suite) scriptName() string {
+	if s.Path != "" {
+		return s.Path
+	}
+	return filepath.ToSlash(s.BashDir())
+}
+
+// BashScripts returns the executable scripts of the suite by their names: setup.sh, cleanup.sh, test_<name>.sh for
+// each test and suite.sh selecting what to run by its arguments. Each script contains the functions of the suite
+func (s *Suite) BashScripts() (map[string]string, error) {
+	functions, err := s.BashString()
+	if err != nil {
+		return nil, err
+	}
+	cli, err := s.bashCLIString()
+	if err != nil {
+		return nil, err
+	}
+	call := func(function string) string {
+		return functions + "\n" + function + "\nexit \"$status\"\n"
+	}
+
+	result := map[string]string{
+		BashSetupScript:   call("setup"),
+		BashCleanupScript: call("cleanup"),
+	}
+	for _, test := range s.Tests {
+		result[BashTestScript(test.Name)] = call("test" + test.Name)
+	}
+	result[BashSuiteScript] = functions + cli
+	return result, nil
+}
+
+// bashCLIString returns the part of suite.sh that runs setup, cleanup or tests selected by the arguments
+func (s *Suite) bashCLIString() (string, error) {
+	type testData struct {
+		Name string
+		Key  string
+	}
+	var tests []testData
+	for _, test := range s.Tests {
+		tests = append(tests, testData{Name: test.Name, Key: strings.ToLower(test.Name)})
+	}
+
+	jobs := s.BashJobs
+	if jobs < 1 {
+		jobs = 1
+	}
+	return s.templates.executeString(BashCLITemplateName, struct {
+		Tests []testData
+		Jobs  int
+	}{
+		Tests: tests,
+		Jobs:  jobs,
+	})
+}
+
+// bashTest returns a copy of the test run by the scripts of the suite. It runs BeforeEach and AfterEach of the suite in
+// the dir of the suite
+func (s *Suite) bashTest(t *Test) *Test {
+	result := *t
+	result.bashDir = s.BashDir()
+	if len(s.BeforeEach) > 0 {
+		result.bashBefore = append(Body{bashCd(result.bashDir, s.Dir)}, s.BeforeEach.bashSteps(s.Source, "Before each", s.BeforeEachLines)...)
+	}
+	if len(s.AfterEach) > 0 {
+		result.bashAfter = append(Body{bashCd(result.bashDir, s.Dir)}, s.AfterEach.bashSteps(s.Source, "After each", s.AfterEachLines)...)
+	}
+	return &result
+}
+
+func (s *Suite) getDependenciesSetup(scriptDir string) []string {
 	setup := make([]string, 0)
 	for _, p := range s.Parents {
-		setup = append(setup, p.getDependenciesSetup()...)
+		setup = append(setup, p.getDependenciesSetup(scriptDir)...)
 	}
 
-	absDir, _ := filepath.Abs(s.Dir)
-	setup = append(setup, fmt.Sprintf("echo 'setup suite %s'", filepath.Dir(s.Location)), "cd "+absDir)
-	setup = append(setup, s.Run...)
+	setup = append(setup, bashEcho("setup suite "+s.scriptName()), bashCd(scriptDir, s.Dir))
+	setup = append(setup, s.Run.bashSteps(s.Source, "Run", s.RunLines)...)
 	return setup
 }
 
-func (s *Suite) getDependenciesCleanup() []string {
-	cleanup := []string{fmt.Sprintf("echo 'cleanup suite %s'", filepath.Dir(s.Location))}
-	cleanup = append(cleanup, s.Cleanup...)
+func (s *Suite) getDependenciesCleanup(scriptDir string) []string {
+	cleanup := []string{bashEcho("cleanup suite " + s.scriptName()), bashCd(scriptDir, s.Dir)}
+	cleanup = append(cleanup, s.Cleanup.bashSteps(s.Source, "Cleanup", s.CleanupLines)...)
 	for _, p := range s.Parents {
-		cleanup = append(cleanup, p.getDependenciesSetup()...)
+		cleanup = append(cleanup, p.getDependenciesCleanup(scriptDir)...)
 	}
 
 	return cleanup
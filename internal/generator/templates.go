@@ -0,0 +1,192 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"embed"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// Names of the built-in templates. A template is overridden by the NAME.tmpl file of the templates dir
+const (
+	SuiteTemplateName            = "suite"
+	IncludedSuiteTemplateName    = "included_suite"
+	RequiredTestTemplateName     = "required_test"
+	TestTemplateName             = "test"
+	EmptyTestTemplateName        = "empty_test"
+	TestingSuiteTemplateName     = "testing_suite"
+	GinkgoSuiteTemplateName      = "ginkgo_suite"
+	EntrypointTemplateName       = "entrypoint"
+	BashSuiteTemplateName        = "bash_suite"
+	BashTestTemplateName         = "bash_test"
+	BashCLITemplateName          = "bash_cli"
+	BashRunAllTemplateName       = "bash_run_all"
+	MakefileTemplateName         = "makefile"
+	GitHubWorkflowTemplateName   = "github_workflow"
+	PowerShellTemplateName       = "powershell"
+	DockerComposeTemplateName    = "docker_compose"
+	DockerEntrypointTemplateName = "docker_entrypoint"
+	templateExt                  = ".tmpl"
+)
+
+// builtinFS contains the built-in templates, templates/NAME.tmpl
+//
+//go:embed templates/*.tmpl
+var builtinFS embed.FS
+
+// builtinTemplates contains the sources of the built-in templates and builtinParsed contains them parsed once with the
+// default functions
+var builtinTemplates, builtinParsed = loadBuiltins()
+
+// loadBuiltins reads and parses the built-in templates. The files are embedded into the binary and parsed by the tests,
+// so they can't fail here
+func loadBuiltins() (map[string]string, map[string]*template.Template) {
+	var sources = map[string]string{}
+	var parsed = map[string]*template.Template{}
+	entries, _ := builtinFS.ReadDir("templates")
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), templateExt)
+		source, _ := builtinFS.ReadFile(path.Join("templates", entry.Name()))
+		sources[name] = string(source)
+		parsed[name] = template.Must(template.New(name).Funcs(defaultFuncs(nil)).Parse(string(source)))
+	}
+	return sources, parsed
+}
+
+// Templates contains sources of the templates overriding the built-in ones by name
+type Templates map[string]string
+
+// TemplateNames returns the names of the built-in templates
+func TemplateNames() []string {
+	var result []string
+	for name := range builtinTemplates {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// BuiltinTemplate returns the source of the built-in template
+func BuiltinTemplate(name string) (string, bool) {
+	source, ok := builtinTemplates[name]
+	return source, ok
+}
+
+// LoadTemplates reads NAME.tmpl files of the dir overriding the built-in templates. Fails on files with unknown names
+// and on templates that can't be parsed with the default functions and the passed ones
+func LoadTemplates(dir string, funcs ...template.FuncMap) (Templates, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+templateExt))
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read templates dir %v", dir)
+	}
+	var result = Templates{}
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), templateExt)
+		if _, ok := builtinTemplates[name]; !ok {
+			return nil, errors.Errorf("unknown template %v, expected one of: %v", file, strings.Join(TemplateNames(), ", "))
+		}
+		source, err := os.ReadFile(filepath.Clean(file))
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot read template %v", file)
+		}
+		tmpl := template.New(name).Funcs(defaultFuncs(nil))
+		for _, f := range funcs {
+			tmpl = tmpl.Funcs(f)
+		}
+		if _, err := tmpl.Parse(string(source)); err != nil {
+			return nil, errors.Wrapf(err, "cannot parse template %v", file)
+		}
+		result[name] = string(source)
+	}
+	return result, nil
+}
+
+// templateSet contains the overridden templates and the functions available in all templates. The templates are
+// compiled once, on the first use
+type templateSet struct {
+	overrides Templates
+	funcs     template.FuncMap
+
+	once     sync.Once
+	compiled map[string]*template.Template
+	err      error
+}
+
+// compile parses the overridden templates and binds the functions to the copies of the built-in ones
+func (t *templateSet) compile() error {
+	t.once.Do(func() {
+		var compiled = map[string]*template.Template{}
+		for _, name := range TemplateNames() {
+			if source, ok := t.overrides[name]; ok {
+				tmpl, err := template.New(name).Funcs(t.funcs).Parse(source)
+				if err != nil {
+					t.err = errors.Wrapf(err, "cannot parse template %v", name)
+					return
+				}
+				compiled[name] = tmpl
+				continue
+			}
+			// Parse trees of the built-in templates are shared by the copies
+			tmpl, err := builtinParsed[name].Clone()
+			if err != nil {
+				t.err = errors.Wrapf(err, "cannot copy template %v", name)
+				return
+			}
+			compiled[name] = tmpl.Funcs(t.funcs)
+		}
+		t.compiled = compiled
+	})
+	return t.err
+}
+
+// lookup returns the overridden template or the built-in one
+func (t *templateSet) lookup(name string) (*template.Template, error) {
+	if t == nil {
+		return builtinParsed[name], nil
+	}
+	if err := t.compile(); err != nil {
+		return nil, err
+	}
+	return t.compiled[name], nil
+}
+
+// execute writes the template applied to the data into w
+func (t *templateSet) execute(w io.Writer, name string, data interface{}) error {
+	tmpl, err := t.lookup(name)
+	if err != nil {
+		return err
+	}
+	return errors.Wrapf(tmpl.Execute(w, data), "cannot execute template %v", name)
+}
+
+// executeString returns the template applied to the data
+func (t *templateSet) executeString(name string, data interface{}) (string, error) {
+	var result strings.Builder
+	if err := t.execute(&result, name, data); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
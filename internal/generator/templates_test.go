@@ -0,0 +1,138 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/gotestmd/internal/config"
+	"github.com/networkservicemesh/gotestmd/internal/generator"
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
+)
+
+func TestLoadTemplates(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "test.tmpl"), []byte(`
+func (s *Suite) Test{{ .Name }}() {
+	s.T().Log("custom")
+	r := s.Runner("{{ .Dir }}")
+	{{ .Run }}
+}
+`), 0o600))
+
+	templates, err := generator.LoadTemplates(dir)
+	require.NoError(t, err)
+
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/tree", Includes: []string{"leaf"}, Run: []string{"echo tree"}},
+		&parser.Example{Dir: "examples/tree/leaf", Run: []string{"echo leaf"}},
+	)
+	require.NoError(t, err)
+
+	source := goSource(t, generate(t, generator.New(config.Config{
+		OutputDir:    "suites",
+		BasePkg:      "example.com/base",
+		ImportPrefix: "example.com/suites",
+	}, generator.WithTemplates(templates)), examples...)[0])
+	require.Contains(t, source, "func (s *Suite) TestLeaf() {\ns.T().Log(\"custom\")")
+}
+
+func TestLoadTemplatesErrors(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "suit.tmpl"), nil, 0o600))
+	_, err := generator.LoadTemplates(dir)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown template")
+
+	dir = t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "suite.tmpl"), []byte("{{ .Name "), 0o600))
+	_, err = generator.LoadTemplates(dir)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cannot parse template")
+}
+
+func TestGenerateTemplatesErrors(t *testing.T) {
+	_, err := generator.New(config.Config{
+		OutputDir:    "suites",
+		BasePkg:      "example.com/base",
+		ImportPrefix: "example.com/suites",
+	}, generator.WithTemplates(generator.Templates{generator.TestTemplateName: "{{ header .Name }}"})).Generate(link(t)...)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cannot parse template test")
+}
+
+func TestExecuteTemplatesErrors(t *testing.T) {
+	suites := generate(t, generator.New(config.Config{
+		OutputDir:    "suites",
+		BasePkg:      "example.com/base",
+		ImportPrefix: "example.com/suites",
+	}, generator.WithTemplates(generator.Templates{generator.SuiteTemplateName: "{{ .NoSuchField }}"})), link(t)...)
+	_, err := suites[0].WriteTo(io.Discard)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cannot execute template suite")
+}
+
+func TestBuiltinTemplates(t *testing.T) {
+	names := []string{
+		generator.SuiteTemplateName, generator.IncludedSuiteTemplateName, generator.RequiredTestTemplateName,
+		generator.TestTemplateName, generator.EmptyTestTemplateName, generator.TestingSuiteTemplateName,
+		generator.GinkgoSuiteTemplateName, generator.EntrypointTemplateName, generator.BashSuiteTemplateName,
+		generator.BashTestTemplateName, generator.BashCLITemplateName, generator.BashRunAllTemplateName,
+		generator.MakefileTemplateName, generator.GitHubWorkflowTemplateName, generator.PowerShellTemplateName,
+		generator.DockerComposeTemplateName, generator.DockerEntrypointTemplateName,
+	}
+	require.ElementsMatch(t, names, generator.TemplateNames())
+	for _, name := range names {
+		source, ok := generator.BuiltinTemplate(name)
+		require.True(t, ok)
+		require.NotEmpty(t, source, name)
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	t.Setenv("GOTESTMD_TEAM", "networking")
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "empty_test.tmpl"), []byte(
+		`// Owner: {{ data "owner" }}, team: {{ env "GOTESTMD_TEAM" }}, {{ header "generated" }}`,
+	), 0o600))
+
+	_, err := generator.LoadTemplates(dir)
+	require.Error(t, err)
+
+	funcs := template.FuncMap{
+		"header": func(s string) string { return strings.ToUpper(s) },
+	}
+	templates, err := generator.LoadTemplates(dir, funcs)
+	require.NoError(t, err)
+
+	source := goSource(t, generate(t, generator.New(config.Config{
+		OutputDir:    "suites",
+		BasePkg:      "example.com/base",
+		ImportPrefix: "example.com/suites",
+		TemplateData: map[string]string{"owner": "nsm"},
+	}, generator.WithTemplates(templates), generator.WithFuncs(funcs)), link(t)...)[0])
+	require.Contains(t, source, "// Owner: nsm, team: networking, GENERATED")
+}
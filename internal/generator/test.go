@@ -20,85 +20,118 @@ package generator
 
 import (
 	"fmt"
+	"io"
 	"path/filepath"
-	"strings"
-	"text/template"
-)
-
-const emptyTest = `func (s *Suite) Test() {}`
+	"time"
 
-const testTemplate = `
-func (s *Suite) Test{{ .Name }}() {
-	r := s.Runner("{{ .Dir }}")
-	{{ .Cleanup }}
-	{{ .Run }}
-}
-`
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
+)
 
 // Test is a template for a test for a suite
 type Test struct {
-	Dir     string
-	Name    string
-	Cleanup Body
-	Run     Body
+	Dir string
+	// RunnerDir is Dir relative to the module root. The runners of the generated Go code resolve it against the module root
+	RunnerDir string
+	Name      string
+	Cleanup   Body
+	Run       Body
+	// Source is the markdown file of the test
+	Source Source
+	// Parallel is set if the test can run in parallel with other tests of the suite
+	Parallel bool
+	// BuildTags are go:build constraints of the test example. They apply to the whole suite
+	BuildTags []string
+	// RunLines and CleanupLines are the lines of the blocks in the source. They are set if the steps are logged
+	RunLines     []int
+	CleanupLines []int
+	// Steps is set if each Run block is run as a subtest
+	Steps bool
+	// SoftFail is set if the test continues after a failed step and reports all failed steps at the end
+	SoftFail bool
+	// CommandTimeout limits the time of each command of the test
+	CommandTimeout time.Duration
+	// Retry sets how the failed commands of Run blocks of the test are retried
+	Retry *parser.Retry
+	// ExitCode is the exit code the commands of Run blocks of the test are expected to fail with
+	ExitCode string
+	// Env are the env variables set by the runner of the test
+	Env map[string]string
+	// Artifacts are the commands collected by the runner of the test when it fails
+	Artifacts map[string]string
+	// Image is the image of the container running the commands of the test
+	Image     string
+	suiteType string
+	// suiteEnv and suiteArtifacts are the env variables and the artifacts of the isolated suite of the test. The runner
+	// of the test sets them before its own ones
+	suiteEnv       map[string]string
+	suiteArtifacts map[string]string
+	// bashBefore and bashAfter are the steps of the suite run before and after the test in bash scripts
+	bashBefore Body
+	bashAfter  Body
+	// bashDir is the dir of the bash scripts running the test
+	bashDir   string
+	templates *templateSet
 }
 
-// String returns string as a test for the suite
-func (t *Test) String() string {
-	source := testTemplate
-	if len(t.Cleanup)+len(t.Run) == 0 {
-		source = emptyTest
-	}
-
-	tmpl, err := template.New("test").Parse(
-		source,
-	)
+// runOptions returns the options of the Run blocks of the test
+func (t *Test) runOptions() runOptions {
+	return runOptions{timeout: t.CommandTimeout, retry: t.Retry, exitCode: t.ExitCode}
+}
 
-	if err != nil {
-		panic(err.Error())
+// WriteTo writes the test for the suite into w
+func (t *Test) WriteTo(w io.Writer) (int64, error) {
+	name := TestTemplateName
+	if len(t.Cleanup)+len(t.Run) == 0 {
+		name = EmptyTestTemplateName
 	}
 
-	cleanup := t.Cleanup.String()
+	cleanup := t.Cleanup.optionsString(testifyLogf, t.Source, t.CleanupLines, runOptions{timeout: t.CommandTimeout})
 	if len(cleanup) > 0 {
 		cleanup = fmt.Sprintf(`	s.T().Cleanup(func() {
 		%v
 	})`, cleanup)
 	}
 
-	var result = new(strings.Builder)
+	var result = &countWriter{w: w}
 
-	_ = tmpl.Execute(result, struct {
-		Dir     string
-		Name    string
-		Cleanup string
-		Run     string
+	suiteType := t.suiteType
+	if suiteType == "" {
+		suiteType = "Suite"
+	}
+
+	err := t.templates.execute(result, name, struct {
+		Dir         string
+		Name        string
+		Type        string
+		RunnerSetup string
+		Cleanup     string
+		Run         string
 	}{
-		Name:    t.Name,
-		Dir:     t.Dir,
-		Cleanup: cleanup,
-		Run:     t.Run.String(),
+		Name:        t.Name,
+		Type:        suiteType,
+		Dir:         t.RunnerDir,
+		RunnerSetup: runnerSetupString(t.Image, mergeMaps(t.suiteEnv, t.Env), mergeMaps(t.suiteArtifacts, t.Artifacts)),
+		Cleanup:     cleanup,
+		Run:         t.Run.stepsString(t.Source, t.RunLines, t.Steps, t.SoftFail, t.runOptions()),
 	})
-
-	return result.String()
+	return result.n, err
 }
 
-const bashTestTemplate = `
-test{{ .Name }}() {
-{{ .Run }}
-{{ .Cleanup }}}`
-
-// BashString generates a bash script for the test
-func (t *Test) BashString() string {
-	tmpl, err := template.New("bashtest").Parse(bashTestTemplate)
-	if err != nil {
-		panic(err.Error())
-	}
+// BashString generates a bash function running the test in its dir
+func (t *Test) BashString() (string, error) {
 	absDir, _ := filepath.Abs(t.Dir)
 
-	t.Run = append(t.Run, "cd "+absDir)
-	result := new(strings.Builder)
-
-	_ = tmpl.Execute(result, struct {
+	var run = Body{bashCd(t.bashDir, t.Dir)}
+	if len(t.bashBefore) > 0 {
+		run = append(append(run, t.bashBefore...), bashCd(t.bashDir, t.Dir))
+	}
+	run = append(run, t.Run.bashSteps(t.Source, "Run", t.RunLines)...)
+	var cleanup Body
+	if len(t.Cleanup) > 0 {
+		cleanup = append(Body{bashCd(t.bashDir, t.Dir)}, t.Cleanup.bashSteps(t.Source, "Cleanup", t.CleanupLines)...)
+	}
+	cleanup = append(cleanup, t.bashAfter...)
+	return t.templates.executeString(BashTestTemplateName, struct {
 		Dir     string
 		Name    string
 		Run     string
@@ -106,9 +139,7 @@ func (t *Test) BashString() string {
 	}{
 		Name:    t.Name,
 		Dir:     absDir,
-		Run:     t.Run.BashString(true),
-		Cleanup: t.Cleanup.BashString(false),
+		Run:     run.BashString(false),
+		Cleanup: cleanup.BashString(true),
 	})
-
-	return result.String()
 }
@@ -0,0 +1,202 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/gotestmd/internal/config"
+)
+
+// plainSuite contains data for templates of the formats that don't use testify suites
+type plainSuite struct {
+	Header     string
+	Name       string
+	Pkg        string
+	Title      string
+	Parallel   bool
+	Base       string
+	BasePkg    string
+	UsesRunner bool
+	Dir        string
+	// RunnerSetup sets the env variables, the image and the artifacts of the runners of the suite
+	RunnerSetup   string
+	Imports       string
+	Setup         string
+	RequiredTests []*plainTest
+	Cleanup       string
+	Run           string
+	Tests         []*plainTest
+	Children      []*plainSuite
+	// Guards are env variables that should be set to run the suite
+	Guards []string
+	// BeforeEach and AfterEach run before and after each test in the dir of the suite. AfterEach is registered as a cleanup
+	BeforeEach string
+	AfterEach  string
+}
+
+type plainTest struct {
+	Name        string
+	Dir         string
+	RunnerSetup string
+	Cleanup     string
+	Run         string
+	Parallel    bool
+}
+
+// plainSuite converts the suite to the template data. The suites it requires are set up by calling setup of their packages,
+// cleanup bodies are registered by the cleanup function, steps are logged by logf
+func (s *Suite) plainSuite(setup, cleanup, logf string) *plainSuite {
+	wrapCleanup := func(b Body, source Source, lines []int) string {
+		if len(b) == 0 {
+			return ""
+		}
+		return fmt.Sprintf(`%v(func() {
+		%v
+	})`, cleanup, b.loggedString(logf, source, lines))
+	}
+	newTest := func(t *Test) *plainTest {
+		result := &plainTest{
+			Name:        t.Name,
+			Dir:         t.RunnerDir,
+			RunnerSetup: runnerSetupString(t.Image, t.Env, t.Artifacts),
+			Cleanup:     wrapCleanup(t.Cleanup, t.Source, t.CleanupLines),
+			Run:         t.Run.loggedString(logf, t.Source, t.RunLines),
+			Parallel:    t.Parallel,
+		}
+		if t.SoftFail {
+			result.Run = t.Run.softString(logf, t.Source, t.RunLines, runOptions{})
+		}
+		return result
+	}
+
+	result := &plainSuite{
+		Header:      s.goHeader(),
+		Name:        s.Name(),
+		Pkg:         s.Pkg(),
+		Title:       s.Title(),
+		Base:        s.Deps[0].Name(),
+		BasePkg:     s.Deps[0].Pkg(),
+		Dir:         s.RunnerDir,
+		RunnerSetup: runnerSetupString(s.Image, s.Env, s.Artifacts),
+		Cleanup:     wrapCleanup(s.Cleanup, s.Source, s.CleanupLines),
+		Run:         s.Run.loggedString(logf, s.Source, s.RunLines),
+		Guards:      s.Guards(),
+	}
+	if len(s.Tests) > 0 {
+		result.BeforeEach = s.BeforeEach.loggedString(logf, s.Source, s.BeforeEachLines)
+		result.AfterEach = wrapCleanup(s.AfterEach, s.Source, s.AfterEachLines)
+	}
+
+	result.UsesRunner = len(s.Run)+len(s.Cleanup)+len(s.RequiredTests) > 0 || result.BeforeEach+result.AfterEach != ""
+	for _, test := range s.Tests {
+		result.UsesRunner = result.UsesRunner || len(test.Run)+len(test.Cleanup) > 0
+	}
+	var imports []string
+	for _, d := range s.Deps[1:] {
+		imports = append(imports, fmt.Sprintf("%q", d.Pkg()))
+	}
+	result.Imports = strings.Join(imports, "\n")
+
+	var setupCalls strings.Builder
+	for _, d := range s.DepsToSetup[1:] {
+		_, _ = setupCalls.WriteString(d.Name())
+		_, _ = setupCalls.WriteString(".")
+		_, _ = setupCalls.WriteString(setup)
+		_, _ = setupCalls.WriteString("\n")
+	}
+	result.Setup = setupCalls.String()
+
+	for _, test := range s.RequiredTests {
+		result.RequiredTests = append(result.RequiredTests, newTest(test))
+	}
+	for _, test := range s.Tests {
+		result.Tests = append(result.Tests, newTest(test))
+	}
+	for _, child := range s.sortedChildren() {
+		result.Children = append(result.Children, &plainSuite{
+			Name:     child.Name(),
+			Title:    child.Title(),
+			Parallel: child.Parallel,
+		})
+	}
+
+	return result
+}
+
+func (s *Suite) writePlain(w io.Writer, name string, data *plainSuite) error {
+	if err := s.checkPlain(); err != nil {
+		return err
+	}
+	return s.templates.execute(&squashWriter{w: w}, name, data)
+}
+
+// checkPlain returns an error if the suite or its tests use directives the formats without testify suites don't support
+func (s *Suite) checkPlain() error {
+	if s.ExitCode != "" {
+		return unsupportedError("exit-code", s.Dir)
+	}
+	if s.Retry != nil {
+		return unsupportedError("retry", s.Dir)
+	}
+	if s.CommandTimeout != 0 {
+		return unsupportedError("command-timeout", s.Dir)
+	}
+	if s.Isolate != nil {
+		return unsupportedError("isolate", s.Dir)
+	}
+	for _, t := range append(append([]*Test(nil), s.RequiredTests...), s.Tests...) {
+		if t.ExitCode != "" {
+			return unsupportedError("exit-code", t.Dir)
+		}
+		if t.Retry != nil {
+			return unsupportedError("retry", t.Dir)
+		}
+		if t.CommandTimeout != 0 {
+			return unsupportedError("command-timeout", t.Dir)
+		}
+	}
+	return nil
+}
+
+// unsupportedError returns the error of the front matter directive of the example the formats without testify suites
+// don't support
+func unsupportedError(directive, dir string) error {
+	return errors.Errorf("%v of %v is supported only by the %v format", directive, dir, config.FormatTestify)
+}
+
+// writeString returns the output of write
+func writeString(write func(w io.Writer) error) (string, error) {
+	var result strings.Builder
+	if err := write(&result); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// TestingString returns a string that contains the suite as standard library tests without testify
+func (s *Suite) TestingString() (string, error) {
+	return writeString(s.writeTesting)
+}
+
+func (s *Suite) writeTesting(w io.Writer) error {
+	return s.writePlain(w, TestingSuiteTemplateName, s.plainSuite("Setup(t)", "t.Cleanup", "t.Logf"))
+}
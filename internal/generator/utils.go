@@ -19,16 +19,87 @@
 package generator
 
 import (
+	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
+	"golang.org/x/mod/modfile"
+
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
 )
 
 var nameRegex = regexp.MustCompile("[^a-zA-Z0-9]+")
-var spaceRegex = regexp.MustCompile(`[\t\r\n]+`)
+
+// squashWriter writes the generated code into w with the runs of tabs and line breaks replaced by a single line break
+// and the leading and trailing whitespace dropped. The code is formatted afterwards, so it's squashed on the fly instead
+// of keeping one more copy of the whole file
+type squashWriter struct {
+	w       io.Writer
+	started bool
+	// pending is the whitespace written only if anything but whitespace follows it
+	pending []byte
+	n       int64
+}
+
+func (s *squashWriter) Write(p []byte) (int, error) {
+	for i := 0; i < len(p); {
+		switch c := p[i]; {
+		case c == '\t' || c == '\r' || c == '\n':
+			if s.started && (len(s.pending) == 0 || s.pending[len(s.pending)-1] != '\n') {
+				s.pending = append(s.pending, '\n')
+			}
+			i++
+		case isSpace(c):
+			if s.started {
+				s.pending = append(s.pending, c)
+			}
+			i++
+		default:
+			j := i + 1
+			for j < len(p) && !isSpace(p[j]) {
+				j++
+			}
+			if err := s.write(s.pending); err != nil {
+				return i, err
+			}
+			s.pending = s.pending[:0]
+			if err := s.write(p[i:j]); err != nil {
+				return i, err
+			}
+			s.started = true
+			i = j
+		}
+	}
+	return len(p), nil
+}
+
+func (s *squashWriter) write(p []byte) error {
+	n, err := s.w.Write(p)
+	s.n += int64(n)
+	return err
+}
+
+// countWriter counts the bytes written into w
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\v' || c == '\f'
+}
 
 func normalizeName(s string) string {
 	return strings.ToLower(nameRegex.ReplaceAllString(s, "_"))
@@ -47,23 +118,74 @@ func normalizeDeps(module string, deps []string) Dependencies {
 	return d
 }
 
-func moduleName(start string) string {
-	const gomod = "go.mod"
-	currDir, err := filepath.Abs(start)
+func remoteDependency(remote *linker.Remote, remotePrefixes map[string]string) Dependency {
+	prefix, ok := remotePrefixes[remote.Module]
+	if !ok {
+		prefix = remote.Module
+	}
+	pieces := strings.Split(remote.Path, "/")
+	for i := 0; i < len(pieces); i++ {
+		pieces[i] = normalizeName(pieces[i])
+	}
+	return Dependency(path.Join(append([]string{prefix}, pieces...)...))
+}
+
+// moduleImportPath returns the import path of the dir based on the nearest go.mod. If module is set, it's used
+// instead of the module path from go.mod. Returns an empty string if the import path can't be detected
+func moduleImportPath(dir, module string) string {
+	absDir, err := filepath.Abs(dir)
 	if err != nil {
 		logrus.Fatal(err.Error())
 	}
-	for len(currDir) > 0 {
-		p := filepath.Clean(filepath.Join(currDir, "go.mod"))
-		if _, err = os.Open(p); err == nil {
-			source, err := os.ReadFile(p)
-			if err != nil {
-				logrus.Fatal(err.Error())
-			}
-			moduleName := strings.TrimPrefix(strings.Split(string(source), "\n")[0], "module ")
-			return filepath.Clean(filepath.Join(moduleName, start))
+	if root := ModuleRoot(absDir); root != "" {
+		if module == "" {
+			// #nosec
+			source, _ := os.ReadFile(filepath.Join(root, "go.mod"))
+			module = modfile.ModulePath(source)
+		}
+		rel, _ := filepath.Rel(root, absDir)
+		return path.Join(module, filepath.ToSlash(rel))
+	}
+	if module == "" {
+		logrus.Warnf("cannot find go.mod for %v, use --module or --import-prefix to set the import path of generated suites", dir)
+		return ""
+	}
+	return path.Join(module, filepath.ToSlash(filepath.Clean(dir)))
+}
+
+// ModuleRoot returns the absolute dir with the go.mod the dir belongs to or an empty string if there is none
+func ModuleRoot(dir string) string {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for currDir := absDir; ; currDir = filepath.Dir(currDir) {
+		if _, err := os.Stat(filepath.Join(currDir, "go.mod")); err == nil {
+			return currDir
 		}
-		currDir = filepath.Dir(currDir)
+		// The root of the file system is its own parent
+		if filepath.Dir(currDir) == currDir {
+			return ""
+		}
+	}
+}
+
+// durationString returns the duration as a Go expression, e.g. 10 * time.Minute, or an empty string for zero duration
+func durationString(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return ""
+	case d%time.Hour == 0:
+		return strconv.FormatInt(int64(d/time.Hour), 10) + " * time.Hour"
+	case d%time.Minute == 0:
+		return strconv.FormatInt(int64(d/time.Minute), 10) + " * time.Minute"
+	case d%time.Second == 0:
+		return strconv.FormatInt(int64(d/time.Second), 10) + " * time.Second"
+	case d%time.Millisecond == 0:
+		return strconv.FormatInt(int64(d/time.Millisecond), 10) + " * time.Millisecond"
+	case d%time.Microsecond == 0:
+		return strconv.FormatInt(int64(d/time.Microsecond), 10) + " * time.Microsecond"
+	default:
+		return strconv.FormatInt(int64(d), 10) + " * time.Nanosecond"
 	}
-	return ""
 }
@@ -0,0 +1,101 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package glob provides path patterns with ** support
+package glob
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Pattern is a compiled path glob.
+//
+// `*` matches any sequence of characters except `/`, `?` matches any single character except `/`,
+// `**` matches any sequence of characters including `/`. A trailing `/**` also matches the directory itself.
+type Pattern struct {
+	source string
+	regex  *regexp.Regexp
+}
+
+// Compile compiles the glob pattern
+func Compile(pattern string) (*Pattern, error) {
+	var sb strings.Builder
+	sb.WriteString("^")
+	p := filepath.ToSlash(filepath.Clean(pattern))
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "/**") && i+len("/**") == len(p):
+			sb.WriteString("(/.*)?")
+			i += len("/**") - 1
+		case strings.HasPrefix(p[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case p[i] == '*':
+			sb.WriteString("[^/]*")
+		case p[i] == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		}
+	}
+	sb.WriteString("$")
+
+	regex, err := regexp.Compile(sb.String())
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid pattern %v", pattern)
+	}
+	return &Pattern{source: pattern, regex: regex}, nil
+}
+
+// Match returns true if the path matches the pattern
+func (p *Pattern) Match(path string) bool {
+	return p.regex.MatchString(filepath.ToSlash(filepath.Clean(path)))
+}
+
+// String returns the source of the pattern
+func (p *Pattern) String() string {
+	return p.source
+}
+
+// Patterns is a list of patterns
+type Patterns []*Pattern
+
+// CompileAll compiles all passed patterns
+func CompileAll(patterns ...string) (Patterns, error) {
+	var result Patterns
+	for _, pattern := range patterns {
+		p, err := Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+// Match returns the first pattern matching the path or nil
+func (p Patterns) Match(path string) *Pattern {
+	for _, pattern := range p {
+		if pattern.Match(path) {
+			return pattern
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package glob_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/gotestmd/internal/glob"
+)
+
+func TestMatch(t *testing.T) {
+	for pattern, paths := range map[string]map[string]bool{
+		"examples/experimental/**": {
+			"examples/experimental":         true,
+			"examples/experimental/a":       true,
+			"examples/experimental/a/b":     true,
+			"examples/experimental-feature": false,
+			"examples/basic":                false,
+		},
+		"examples/*/heal": {
+			"examples/basic/heal":   true,
+			"examples/basic/a/heal": false,
+		},
+		"**/Leaf?": {
+			"examples/Tree/LeafA":    true,
+			"examples/Tree/LeafAB":   false,
+			"examples/Tree/SubTree/": false,
+		},
+	} {
+		p, err := glob.Compile(pattern)
+		require.NoError(t, err)
+		for path, expected := range paths {
+			require.Equal(t, expected, p.Match(path), "%v %v", pattern, path)
+		}
+	}
+}
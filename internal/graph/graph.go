@@ -0,0 +1,124 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package graph renders linked examples as Graphviz DOT or Mermaid graphs
+package graph
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+)
+
+const (
+	// DOT is the Graphviz format
+	DOT = "dot"
+	// Mermaid is the Mermaid flowchart format
+	Mermaid = "mermaid"
+)
+
+type edge struct {
+	from, to int
+	requires bool
+}
+
+type graph struct {
+	names []string
+	edges []edge
+}
+
+func newGraph(examples []*linker.LinkedExample) *graph {
+	var sorted = append([]*linker.LinkedExample{}, examples...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	var result = new(graph)
+	var ids = map[string]int{}
+	for i, e := range sorted {
+		name := e.Name
+		if name == "" {
+			name = "."
+		}
+		ids[e.Name] = i
+		result.names = append(result.names, name)
+	}
+	for i, e := range sorted {
+		for _, child := range e.Children {
+			result.edges = append(result.edges, edge{from: i, to: ids[child.Name]})
+		}
+		for _, require := range e.Requires {
+			if to, ok := ids[require]; ok {
+				result.edges = append(result.edges, edge{from: i, to: to, requires: true})
+			}
+		}
+	}
+	return result
+}
+
+// Write renders examples in the passed format. Includes are rendered as solid edges, requires as dashed edges
+func Write(w io.Writer, format string, examples []*linker.LinkedExample) error {
+	g := newGraph(examples)
+	switch format {
+	case DOT:
+		_, err := io.WriteString(w, g.dot())
+		return err
+	case Mermaid:
+		_, err := io.WriteString(w, g.mermaid())
+		return err
+	}
+	return errors.Errorf("unknown graph format %q, expected %v or %v", format, DOT, Mermaid)
+}
+
+func (g *graph) dot() string {
+	var sb strings.Builder
+	sb.WriteString("digraph gotestmd {\n")
+	sb.WriteString("\trankdir=LR;\n")
+	sb.WriteString("\tnode [shape=box];\n")
+	for _, name := range g.names {
+		_, _ = fmt.Fprintf(&sb, "\t%q;\n", name)
+	}
+	for _, e := range g.edges {
+		if e.requires {
+			_, _ = fmt.Fprintf(&sb, "\t%q -> %q [style=dashed, label=\"requires\"];\n", g.names[e.from], g.names[e.to])
+			continue
+		}
+		_, _ = fmt.Fprintf(&sb, "\t%q -> %q;\n", g.names[e.from], g.names[e.to])
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func (g *graph) mermaid() string {
+	var sb strings.Builder
+	sb.WriteString("graph LR\n")
+	for i, name := range g.names {
+		_, _ = fmt.Fprintf(&sb, "\tn%d[\"%v\"]\n", i, strings.ReplaceAll(name, `"`, "#quot;"))
+	}
+	for _, e := range g.edges {
+		if e.requires {
+			_, _ = fmt.Fprintf(&sb, "\tn%d -. requires .-> n%d\n", e.from, e.to)
+			continue
+		}
+		_, _ = fmt.Fprintf(&sb, "\tn%d --> n%d\n", e.from, e.to)
+	}
+	return sb.String()
+}
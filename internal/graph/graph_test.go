@@ -0,0 +1,74 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/gotestmd/internal/graph"
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
+)
+
+func linkTree(t *testing.T) []*linker.LinkedExample {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/spire", Includes: []string{"single-cluster"}, Run: []string{"echo spire"}},
+		&parser.Example{Dir: "examples/spire/single-cluster", Run: []string{"echo single-cluster"}},
+		&parser.Example{Dir: "examples/use-cases", Includes: []string{"kernel2ip"}, Requires: []string{"../spire"}},
+		&parser.Example{Dir: "examples/use-cases/kernel2ip", Run: []string{"echo kernel2ip"}},
+	)
+	require.NoError(t, err)
+	return examples
+}
+
+func TestWriteDOT(t *testing.T) {
+	var sb strings.Builder
+	require.NoError(t, graph.Write(&sb, graph.DOT, linkTree(t)))
+	require.Equal(t, `digraph gotestmd {
+	rankdir=LR;
+	node [shape=box];
+	"spire";
+	"spire/single-cluster";
+	"use-cases";
+	"use-cases/kernel2ip";
+	"spire" -> "spire/single-cluster";
+	"use-cases" -> "use-cases/kernel2ip";
+	"use-cases" -> "spire" [style=dashed, label="requires"];
+}
+`, sb.String())
+}
+
+func TestWriteMermaid(t *testing.T) {
+	var sb strings.Builder
+	require.NoError(t, graph.Write(&sb, graph.Mermaid, linkTree(t)))
+	require.Equal(t, `graph LR
+	n0["spire"]
+	n1["spire/single-cluster"]
+	n2["use-cases"]
+	n3["use-cases/kernel2ip"]
+	n0 --> n1
+	n2 --> n3
+	n2 -. requires .-> n0
+`, sb.String())
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	require.EqualError(t, graph.Write(new(strings.Builder), "svg", linkTree(t)), `unknown graph format "svg", expected dot or mermaid`)
+}
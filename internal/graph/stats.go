@@ -0,0 +1,160 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+)
+
+// Stats are the metrics of the linked examples
+type Stats struct {
+	Examples int `json:"examples"`
+	Suites   int `json:"suites"`
+	Tests    int `json:"tests"`
+	Blocks   int `json:"blocks"`
+	// Commands are the lines of the blocks without empty lines, comments and continuations of multiline commands
+	Commands int `json:"commands"`
+	// MaxDepth is the length of the longest chain of includes and requires
+	MaxDepth int `json:"maxDepth"`
+	// LargestSetups are the examples with the most examples set up before them
+	LargestSetups []*Setup `json:"largestSetups"`
+}
+
+// Setup is an example with the examples set up before it
+type Setup struct {
+	Name  string   `json:"name"`
+	Setup []string `json:"setup"`
+}
+
+// NewStats returns the metrics of the examples with the top examples by the number of examples set up before them
+func NewStats(examples []*linker.LinkedExample, top int) *Stats {
+	var index = map[string]*linker.LinkedExample{}
+	for _, e := range examples {
+		index[e.Name] = e
+	}
+	// dependencies are set up before the example: the examples including it and the required ones
+	dependencies := func(e *linker.LinkedExample) []*linker.LinkedExample {
+		var result = append([]*linker.LinkedExample{}, e.Parents...)
+		for _, require := range e.Requires {
+			if dep, ok := index[require]; ok {
+				result = append(result, dep)
+			}
+		}
+		return result
+	}
+
+	var depths = map[string]int{}
+	var depth func(e *linker.LinkedExample) int
+	depth = func(e *linker.LinkedExample) int {
+		if result, ok := depths[e.Name]; ok {
+			return result
+		}
+		var result int
+		for _, dep := range dependencies(e) {
+			if d := depth(dep) + 1; d > result {
+				result = d
+			}
+		}
+		depths[e.Name] = result
+		return result
+	}
+
+	var result = &Stats{Examples: len(examples), LargestSetups: []*Setup{}}
+	var setups []*Setup
+	for _, e := range examples {
+		if e.IsLeaf() {
+			result.Tests++
+		} else {
+			result.Suites++
+		}
+		for _, blocks := range [][]string{e.Run, e.Cleanup, e.BeforeEach, e.AfterEach} {
+			result.Blocks += len(blocks)
+			for _, block := range blocks {
+				result.Commands += countCommands(block)
+			}
+		}
+		if d := depth(e); d > result.MaxDepth {
+			result.MaxDepth = d
+		}
+
+		var visited = map[string]bool{}
+		var visit func(e *linker.LinkedExample)
+		visit = func(e *linker.LinkedExample) {
+			for _, dep := range dependencies(e) {
+				if !visited[dep.Name] {
+					visited[dep.Name] = true
+					visit(dep)
+				}
+			}
+		}
+		visit(e)
+		if len(visited) == 0 {
+			continue
+		}
+		var setup = &Setup{Name: displayName(e.Name)}
+		for name := range visited {
+			setup.Setup = append(setup.Setup, displayName(name))
+		}
+		sort.Strings(setup.Setup)
+		setups = append(setups, setup)
+	}
+	sort.SliceStable(setups, func(i, j int) bool {
+		if len(setups[i].Setup) != len(setups[j].Setup) {
+			return len(setups[i].Setup) > len(setups[j].Setup)
+		}
+		return setups[i].Name < setups[j].Name
+	})
+	if len(setups) > top {
+		setups = setups[:top]
+	}
+	result.LargestSetups = append(result.LargestSetups, setups...)
+	return result
+}
+
+// countCommands returns the number of commands in the bash block
+func countCommands(block string) int {
+	var result int
+	var continued bool
+	for _, line := range strings.Split(block, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") && !continued {
+			result++
+		}
+		continued = strings.HasSuffix(line, "\\")
+	}
+	return result
+}
+
+// WriteStats prints the metrics as NAME: VALUE lines
+func WriteStats(w io.Writer, stats *Stats) error {
+	var result strings.Builder
+	_, _ = fmt.Fprintf(&result, "examples: %v\nsuites: %v\ntests: %v\nblocks: %v\ncommands: %v\nmax depth: %v\n",
+		stats.Examples, stats.Suites, stats.Tests, stats.Blocks, stats.Commands, stats.MaxDepth)
+	if len(stats.LargestSetups) > 0 {
+		_, _ = result.WriteString("largest setups:\n")
+	}
+	for _, setup := range stats.LargestSetups {
+		_, _ = fmt.Fprintf(&result, "  %v: %v (%v)\n", setup.Name, len(setup.Setup), strings.Join(setup.Setup, ", "))
+	}
+	_, err := io.WriteString(w, result.String())
+	return err
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+)
+
+// WriteTree renders examples as an ASCII tree of includes starting from the examples not included by others.
+// Required examples and tests are listed after the names, marks are appended to the examples by their names
+func WriteTree(w io.Writer, examples []*linker.LinkedExample, marks map[string]string) error {
+	var roots []*linker.LinkedExample
+	for _, e := range examples {
+		if len(e.Parents) == 0 {
+			roots = append(roots, e)
+		}
+	}
+	var sb strings.Builder
+	writeTreeLevel(&sb, "", roots, marks)
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeTreeLevel(sb *strings.Builder, prefix string, examples []*linker.LinkedExample, marks map[string]string) {
+	var sorted = append([]*linker.LinkedExample{}, examples...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	for i, e := range sorted {
+		branch, indent := "|-- ", "|   "
+		if i == len(sorted)-1 {
+			branch, indent = "`-- ", "    "
+		}
+		sb.WriteString(prefix)
+		sb.WriteString(branch)
+		sb.WriteString(treeLine(e, marks[e.Name]))
+		sb.WriteString("\n")
+		writeTreeLevel(sb, prefix+indent, e.Children, marks)
+	}
+}
+
+// treeLine returns the name of the example with its requires and the mark
+func treeLine(e *linker.LinkedExample, mark string) string {
+	var sb strings.Builder
+	sb.WriteString(displayName(e.Name))
+	if e.IsLeaf() {
+		sb.WriteString(" (test)")
+	}
+	var requires []string
+	for _, require := range e.Requires {
+		requires = append(requires, displayName(require))
+	}
+	for _, test := range e.RequiredTests {
+		requires = append(requires, displayName(test.Name))
+	}
+	if len(requires) > 0 {
+		sb.WriteString(" -> requires ")
+		sb.WriteString(strings.Join(requires, ", "))
+	}
+	if mark != "" {
+		sb.WriteString(" [")
+		sb.WriteString(mark)
+		sb.WriteString("]")
+	}
+	return sb.String()
+}
+
+// displayName returns . for the example of the input dir itself
+func displayName(name string) string {
+	if name == "" {
+		return "."
+	}
+	return name
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
+)
+
+// bashism is a bash feature missing in POSIX sh
+type bashism struct {
+	regex *regexp.Regexp
+	name  string
+}
+
+var bashisms = []bashism{
+	{regexp.MustCompile(`\[\[`), "[[ ]] test"},
+	{regexp.MustCompile(`(^|[;&|]\s*)function\s`), "function keyword"},
+	{regexp.MustCompile(`(^|[;&|]\s*)source\s`), "source, use . instead"},
+	{regexp.MustCompile(`\[\s[^]]*\s==\s`), "== in [ ] test, use = instead"},
+	{regexp.MustCompile(`\w=\(`), "array"},
+	{regexp.MustCompile(`\$\{\w+\[`), "array"},
+	{regexp.MustCompile(`<<<`), "here-string"},
+	{regexp.MustCompile(`&>`), "&> redirection"},
+	{regexp.MustCompile(`[<>]\(`), "process substitution"},
+	{regexp.MustCompile(`\{\w+\.\.\w+\}`), "brace expansion"},
+	{regexp.MustCompile(`(^|[;&|]\s*)(pushd|popd|declare|shopt)\s`), "bash builtin"},
+	{regexp.MustCompile(`(^|[;&|]\s*)echo\s+-e\s`), "echo -e, use printf instead"},
+}
+
+// isPOSIX returns true if the shell is POSIX sh without bash extensions, e.g. sh, dash or "busybox sh"
+func isPOSIX(shell string) bool {
+	fields := strings.Fields(shell)
+	if len(fields) == 0 {
+		return false
+	}
+	name := path.Base(fields[0])
+	if name == "busybox" && len(fields) > 1 {
+		name = fields[1]
+	}
+	switch name {
+	case "sh", "dash", "ash", "posh":
+		return true
+	}
+	return false
+}
+
+// lintBashisms returns the bashisms of the blocks run by POSIX sh: the ones with the shell=NAME attribute and the
+// others if the default shell is sh
+func lintBashisms(e *parser.Example, shell string) []*Finding {
+	var result []*Finding
+	for _, block := range e.Blocks() {
+		blockShell := block.Shell
+		if blockShell == "" {
+			blockShell = shell
+		}
+		if !isPOSIX(blockShell) {
+			continue
+		}
+		for i, line := range strings.Split(block.Script, "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), "#") {
+				continue
+			}
+			for _, b := range bashisms {
+				if b.regex.MatchString(line) {
+					pos := parser.Position{File: e.Source, Line: block.Pos.Line + i}
+					result = append(result, &Finding{Pos: pos, Severity: SeverityWarning, Rule: RuleBashism, Msg: b.name + " isn't supported by " + blockShell})
+					break
+				}
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,269 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lint checks the structure of example documents
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
+)
+
+// Severity is the severity of a finding
+type Severity string
+
+// Severities of findings
+const (
+	// SeverityError marks documents that can't be generated or run correctly
+	SeverityError Severity = "error"
+	// SeverityWarning marks likely mistakes
+	SeverityWarning Severity = "warning"
+)
+
+// Rules of the checks
+const (
+	RuleParse           = "parse"
+	RuleBrokenLink      = "broken-link"
+	RuleLink            = "link"
+	RuleMissingCleanup  = "missing-cleanup"
+	RuleEmptySection    = "empty-section"
+	RuleDuplicateTest   = "duplicate-test"
+	RuleUnusedDirective = "unused-directive"
+	RuleBashism         = "bashism"
+)
+
+var nameRegex = regexp.MustCompile("[^a-zA-Z0-9]+")
+
+// Finding is a problem found in a document
+type Finding struct {
+	Pos      parser.Position
+	Severity Severity
+	Rule     string
+	Msg      string
+}
+
+// String returns the finding in the FILE:LINE: SEVERITY: MESSAGE [RULE] format
+func (f *Finding) String() string {
+	return fmt.Sprintf("%v: %v: %v [%v]", f.Pos, f.Severity, f.Msg, f.Rule)
+}
+
+// Option is an option of Lint
+type Option func(o *options)
+
+type options struct {
+	shell string
+}
+
+// WithShell sets the shell running the blocks without the shell=NAME attribute, so their bashisms are found if it's sh
+func WithShell(shell string) Option {
+	return func(o *options) {
+		o.shell = shell
+	}
+}
+
+// Lint checks README.md files of the dirs. Root is the main input dir the names of examples are relative to.
+// Examples are linked only if there are no parse errors and broken links
+func Lint(root string, dirs []string, opts ...Option) []*Finding {
+	return LintOverlay(root, dirs, nil, opts...)
+}
+
+// LintOverlay checks the dirs like Lint taking the contents of the files found in the overlay by their absolute paths
+// instead of reading them from the disk, e.g. of the documents edited in an editor
+func LintOverlay(root string, dirs []string, overlay map[string]string, opts ...Option) []*Finding {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	var result []*Finding
+	add := func(pos parser.Position, severity Severity, rule, msg string) {
+		result = append(result, &Finding{Pos: pos, Severity: severity, Rule: rule, Msg: msg})
+	}
+
+	var examples []*parser.Example
+	var p = parser.New()
+	for _, dir := range dirs {
+		file := path.Join(dir, "README.md")
+		e, err := parseFile(p, file, overlay)
+		switch errs := err.(type) {
+		case nil:
+			examples = append(examples, e)
+		case parser.ErrorList:
+			for _, e := range errs {
+				add(e.Pos, SeverityError, RuleParse, e.Msg)
+			}
+		default:
+			if !os.IsNotExist(err) {
+				add(parser.Position{File: file}, SeverityError, RuleParse, err.Error())
+			}
+		}
+	}
+
+	var dirIndex = map[string]bool{}
+	var ids = map[string]bool{}
+	for _, e := range examples {
+		dirIndex[filepath.Clean(e.Dir)] = true
+		if e.ID != "" {
+			ids[e.ID] = true
+		}
+	}
+	for _, e := range examples {
+		section := func(name string) parser.Position {
+			return parser.Position{File: e.Source, Line: e.Sections[name]}
+		}
+		for _, link := range e.Includes {
+			if !linkExists(e, link, dirIndex, ids) {
+				add(section(parser.SectionIncludes), SeverityError, RuleBrokenLink, "included example "+link+" doesn't exist")
+			}
+		}
+		for _, link := range e.Requires {
+			if !linker.IsRemote(link) && !linker.IsURL(link) && !linkExists(e, link, dirIndex, ids) {
+				add(section(parser.SectionRequires), SeverityError, RuleBrokenLink, "required example "+link+" doesn't exist")
+			}
+		}
+		for name, blocks := range map[string][]string{parser.SectionRun: e.Run, parser.SectionCleanup: e.Cleanup, parser.SectionBeforeEach: e.BeforeEach, parser.SectionAfterEach: e.AfterEach} {
+			if _, ok := e.Sections[name]; ok && len(blocks) == 0 {
+				add(section(name), SeverityWarning, RuleEmptySection, name+" section has no bash blocks")
+			}
+		}
+		for name, links := range map[string]int{parser.SectionIncludes: len(e.Includes), parser.SectionRequires: len(e.Requires) + len(e.OptionalRequires)} {
+			if _, ok := e.Sections[name]; ok && links == 0 {
+				add(section(name), SeverityWarning, RuleEmptySection, name+" section has no markdown links")
+			}
+		}
+		for _, d := range e.Directives() {
+			if !d.Known {
+				add(d.Pos, SeverityWarning, RuleUnusedDirective, "unknown front matter key "+d.Key)
+			}
+		}
+		result = append(result, lintBashisms(e, o.shell)...)
+	}
+
+	if !hasErrors(result) {
+		result = append(result, lintLinked(root, examples)...)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Pos.File != result[j].Pos.File {
+			return result[i].Pos.File < result[j].Pos.File
+		}
+		if result[i].Pos.Line != result[j].Pos.Line {
+			return result[i].Pos.Line < result[j].Pos.Line
+		}
+		return result[i].Msg < result[j].Msg
+	})
+	return result
+}
+
+func parseFile(p *parser.Parser, file string, overlay map[string]string) (*parser.Example, error) {
+	if abs, err := filepath.Abs(file); err == nil {
+		if content, ok := overlay[abs]; ok {
+			return p.ParseNamed(strings.NewReader(content), file)
+		}
+	}
+	return p.ParseFile(file)
+}
+
+// Count returns the number of the findings with the severity
+func Count(findings []*Finding, severity Severity) int {
+	var result int
+	for _, f := range findings {
+		if f.Severity == severity {
+			result++
+		}
+	}
+	return result
+}
+
+func hasErrors(findings []*Finding) bool {
+	return Count(findings, SeverityError) > 0
+}
+
+// linkExists returns true if the link points to a parsed example. Tests of the examples are checked by the linker
+func linkExists(e *parser.Example, link string, dirs, ids map[string]bool) bool {
+	if i := strings.LastIndex(link, "#"); i >= 0 {
+		link = link[:i]
+	}
+	if linker.IsAlias(link) {
+		return ids[strings.TrimPrefix(link, "@")]
+	}
+	return dirs[filepath.Join(e.Dir, link)]
+}
+
+// lintLinked links the examples without remote dependencies and checks the links between them
+func lintLinked(root string, examples []*parser.Example) []*Finding {
+	var result []*Finding
+	var local []*parser.Example
+	for _, e := range examples {
+		// The linker updates the links in place
+		c := *e
+		c.Includes = append([]string(nil), e.Includes...)
+		c.Requires, c.OptionalRequires = localLinks(e.Requires), localLinks(e.OptionalRequires)
+		local = append(local, &c)
+	}
+	linked, err := linker.New(root).Link(local...)
+	if err != nil {
+		return append(result, &Finding{Pos: parser.Position{File: root}, Severity: SeverityError, Rule: RuleLink, Msg: err.Error()})
+	}
+
+	for _, e := range linked {
+		pos := parser.Position{File: e.Source, Line: 1}
+		if e.IsLeaf() {
+			for key, set := range map[string]bool{"priority": e.Priority != 0, "timeout": e.Timeout != 0, "skip-unless-env": e.SkipUnlessEnv != ""} {
+				if set {
+					result = append(result, &Finding{Pos: pos, Severity: SeverityWarning, Rule: RuleUnusedDirective, Msg: key + " has no effect on tests"})
+				}
+			}
+			continue
+		}
+		if e.SoftFail {
+			result = append(result, &Finding{Pos: pos, Severity: SeverityWarning, Rule: RuleUnusedDirective, Msg: "soft-fail has no effect on suites"})
+		}
+		if len(e.Run) > 0 && len(e.Cleanup) == 0 {
+			result = append(result, &Finding{Pos: parser.Position{File: e.Source, Line: e.Sections[parser.SectionRun]}, Severity: SeverityWarning, Rule: RuleMissingCleanup, Msg: "suite has Run without Cleanup"})
+		}
+		var tests = map[string]*linker.LinkedExample{}
+		for _, child := range e.Children {
+			if !child.IsLeaf() {
+				continue
+			}
+			key := strings.ToLower(nameRegex.ReplaceAllString(filepath.Base(child.Name), ""))
+			if other, ok := tests[key]; ok {
+				result = append(result, &Finding{Pos: parser.Position{File: child.Source, Line: 1}, Severity: SeverityError, Rule: RuleDuplicateTest, Msg: "test has the same name as " + other.Source})
+				continue
+			}
+			tests[key] = child
+		}
+	}
+	return result
+}
+
+// localLinks returns the links without remote examples and URLs
+func localLinks(links []string) []string {
+	var result []string
+	for _, link := range links {
+		if !linker.IsRemote(link) && !linker.IsURL(link) {
+			result = append(result, link)
+		}
+	}
+	return result
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/gotestmd/internal/lint"
+)
+
+func writeExamples(t *testing.T, files map[string]string) (root string, dirs []string) {
+	root = t.TempDir()
+	dirs = []string{root}
+	for dir, content := range files {
+		location := filepath.Join(root, dir)
+		require.NoError(t, os.MkdirAll(location, 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(location, "README.md"), []byte(content), 0o600))
+		if dir != "." {
+			dirs = append(dirs, location)
+		}
+	}
+	return root, dirs
+}
+
+func messages(root string, findings []*lint.Finding) []string {
+	var result []string
+	for _, f := range findings {
+		rel, _ := filepath.Rel(root, f.Pos.File)
+		f.Pos.File = rel
+		result = append(result, f.String())
+	}
+	return result
+}
+
+func TestLint(t *testing.T) {
+	root, dirs := writeExamples(t, map[string]string{
+		".":   "# Suite\n\n## Includes\n\n- [A B](./a-b)\n- [AB](./ab)\n\n## Run\n\n```bash\necho run\n```\n",
+		"a-b": "---\ntimeout: 1m\nowner: team\n---\n# A B\n\n## Run\n\n```bash\necho a\n```\n",
+		"ab":  "# AB\n\n## Run\n\nNothing to run\n\n## Cleanup\n\n```bash\necho cleanup\n```\n\n## Includes\n\nNothing is included.\n",
+	})
+
+	findings := lint.Lint(root, dirs)
+	require.Equal(t, []string{
+		"README.md:8: warning: suite has Run without Cleanup [missing-cleanup]",
+		"a-b/README.md:1: warning: timeout has no effect on tests [unused-directive]",
+		"a-b/README.md:3:1: warning: unknown front matter key owner [unused-directive]",
+		"ab/README.md:1: error: test has the same name as " + filepath.Join(root, "a-b", "README.md") + " [duplicate-test]",
+		"ab/README.md:3: warning: Run section has no bash blocks [empty-section]",
+		"ab/README.md:13: warning: Includes section has no markdown links [empty-section]",
+	}, messages(root, findings))
+	require.Equal(t, 1, lint.Count(findings, lint.SeverityError))
+	require.Equal(t, 5, lint.Count(findings, lint.SeverityWarning))
+}
+
+func TestLintBrokenLinks(t *testing.T) {
+	root, dirs := writeExamples(t, map[string]string{
+		".": "# Suite\n\n## Includes\n\n- [Missing](./missing)\n\n## Run\n\n```bash\necho run\n```\n\n## Cleanup\n\n```bash\necho cleanup\n```\n",
+		"a": "# A\n\n## Requires\n\n- [Alias](@missing)\n- [Remote](github.com/org/repo//examples/a@v1.0.0)\n\n## Run\n\n```bash\necho a\n```\n",
+	})
+
+	require.Equal(t, []string{
+		"README.md:3: error: included example ./missing doesn't exist [broken-link]",
+		"a/README.md:3: error: required example @missing doesn't exist [broken-link]",
+	}, messages(root, lint.Lint(root, dirs)))
+}
+
+func TestLintOverlay(t *testing.T) {
+	root, dirs := writeExamples(t, map[string]string{
+		".": "# Suite\n\n## Run\n\n```bash\necho run\n```\n\n## Cleanup\n\n```bash\necho cleanup\n```\n",
+	})
+
+	overlay := map[string]string{filepath.Join(root, "README.md"): "# Suite\n\n## Includes\n\n- [Missing](./missing)\n"}
+	require.Equal(t, []string{
+		"README.md:3: error: included example ./missing doesn't exist [broken-link]",
+	}, messages(root, lint.LintOverlay(root, dirs, overlay)))
+	require.Empty(t, lint.Lint(root, dirs))
+}
+
+func TestLintBashisms(t *testing.T) {
+	root, dirs := writeExamples(t, map[string]string{
+		".": "# Suite\n\n## Run\n\n```bash shell=sh\n# [[ is fine in comments\nif [[ -f go.mod ]]; then echo found; fi\nsource .env\ncat <<< text\n```\n\n```bash\n[[ -f go.mod ]]\n```\n\n## Cleanup\n\n```bash\necho -e 'done\\n'\n```\n",
+	})
+
+	require.Equal(t, []string{
+		"README.md:7: warning: [[ ]] test isn't supported by sh [bashism]",
+		"README.md:8: warning: source, use . instead isn't supported by sh [bashism]",
+		"README.md:9: warning: here-string isn't supported by sh [bashism]",
+	}, messages(root, lint.Lint(root, dirs)))
+	require.Equal(t, []string{
+		"README.md:7: warning: [[ ]] test isn't supported by sh [bashism]",
+		"README.md:8: warning: source, use . instead isn't supported by sh [bashism]",
+		"README.md:9: warning: here-string isn't supported by sh [bashism]",
+		"README.md:13: warning: [[ ]] test isn't supported by busybox sh [bashism]",
+		"README.md:19: warning: echo -e, use printf instead isn't supported by busybox sh [bashism]",
+	}, messages(root, lint.Lint(root, dirs, lint.WithShell("busybox sh"))))
+}
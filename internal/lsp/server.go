@@ -0,0 +1,304 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lsp provides a language server reporting the findings of lint in example documents as diagnostics
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/textproto"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/gotestmd/internal/lint"
+)
+
+// Severities of diagnostics
+const (
+	severityError   = 1
+	severityWarning = 2
+)
+
+// methodNotFound is the JSON-RPC error code of unsupported requests
+const methodNotFound = -32601
+
+// Server checks the examples of the input dir when the documents are opened, saved or closed. Changes only update the
+// contents of the documents, so the tree isn't walked and linted on every keystroke
+type Server struct {
+	root string
+	// dirs returns the dirs of the examples to check
+	dirs func() []string
+	// walked are the dirs found when a document was opened or saved last time
+	walked []string
+	// docs are the contents of open documents by their absolute paths
+	docs map[string]string
+	// published are the URIs of the documents with published diagnostics
+	published map[string]bool
+	out       io.Writer
+}
+
+// New creates a Server checking the dirs of the root input dir
+func New(root string, dirs func() []string) *Server {
+	return &Server{
+		root:      root,
+		dirs:      dirs,
+		docs:      map[string]string{},
+		published: map[string]bool{},
+	}
+}
+
+type message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  interface{}      `json:"result,omitempty"`
+	Error   *responseError   `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type textDocument struct {
+	URI  string `json:"uri"`
+	Text string `json:"text"`
+}
+
+type documentParams struct {
+	TextDocument   textDocument `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+}
+
+type position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type diagnostic struct {
+	Range struct {
+		Start position `json:"start"`
+		End   position `json:"end"`
+	} `json:"range"`
+	Severity int    `json:"severity"`
+	Code     string `json:"code"`
+	Source   string `json:"source"`
+	Message  string `json:"message"`
+}
+
+// Serve handles the messages of r and writes the responses and the diagnostics to w until the exit notification or
+// the end of r
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	s.out = w
+	reader := textproto.NewReader(bufio.NewReader(r))
+	for {
+		msg, err := readMessage(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if msg.Method == "exit" {
+			return nil
+		}
+		if err = s.handle(msg); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(msg *message) error {
+	switch msg.Method {
+	case "initialize":
+		return s.reply(msg.ID, map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				// Full documents are sent on changes
+				"textDocumentSync": map[string]interface{}{"openClose": true, "change": 1, "save": true},
+			},
+			"serverInfo": map[string]string{"name": "gotestmd"},
+		})
+	case "shutdown":
+		return s.reply(msg.ID, nil)
+	case "textDocument/didOpen", "textDocument/didChange", "textDocument/didSave", "textDocument/didClose":
+		return s.handleDocument(msg)
+	}
+	if msg.ID != nil {
+		return s.write(&message{JSONRPC: "2.0", ID: msg.ID, Error: &responseError{Code: methodNotFound, Message: "method not found: " + msg.Method}})
+	}
+	// Other notifications are ignored
+	return nil
+}
+
+// handleDocument updates the open documents and publishes the diagnostics. Invalid notifications are logged and skipped,
+// so a document of another scheme doesn't stop the server
+func (s *Server) handleDocument(msg *message) error {
+	var params documentParams
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		logrus.Warnf("invalid params of %v: %v", msg.Method, err)
+		return nil
+	}
+	location, err := uriPath(params.TextDocument.URI)
+	if err != nil {
+		logrus.Warnf("skipping %v: %v", msg.Method, err)
+		return nil
+	}
+	switch msg.Method {
+	case "textDocument/didOpen":
+		s.docs[location] = params.TextDocument.Text
+		s.walked = nil
+	case "textDocument/didChange":
+		if len(params.ContentChanges) > 0 {
+			s.docs[location] = params.ContentChanges[len(params.ContentChanges)-1].Text
+		}
+		return nil
+	case "textDocument/didSave":
+		s.walked = nil
+	case "textDocument/didClose":
+		delete(s.docs, location)
+	}
+	return s.publish()
+}
+
+// publish sends the diagnostics of the documents with findings and clears the diagnostics of the fixed documents
+func (s *Server) publish() error {
+	var diagnostics = map[string][]*diagnostic{}
+	if s.walked == nil {
+		s.walked = s.dirs()
+	}
+	for _, f := range lint.LintOverlay(s.root, s.walked, s.docs) {
+		location, err := filepath.Abs(f.Pos.File)
+		if err != nil {
+			return err
+		}
+		uri := pathURI(location)
+		diagnostics[uri] = append(diagnostics[uri], newDiagnostic(f))
+	}
+	for uri := range s.published {
+		if _, ok := diagnostics[uri]; !ok {
+			diagnostics[uri] = []*diagnostic{}
+		}
+	}
+	var uris []string
+	for uri := range diagnostics {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+	s.published = map[string]bool{}
+	for _, uri := range uris {
+		list := diagnostics[uri]
+		if len(list) > 0 {
+			s.published[uri] = true
+		}
+		params, err := json.Marshal(map[string]interface{}{"uri": uri, "diagnostics": list})
+		if err != nil {
+			return err
+		}
+		if err = s.write(&message{JSONRPC: "2.0", Method: "textDocument/publishDiagnostics", Params: params}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newDiagnostic returns the diagnostic of the finding covering the rest of its line
+func newDiagnostic(f *lint.Finding) *diagnostic {
+	var result = &diagnostic{Severity: severityWarning, Code: f.Rule, Source: "gotestmd", Message: f.Msg}
+	if f.Severity == lint.SeverityError {
+		result.Severity = severityError
+	}
+	if f.Pos.Line > 0 {
+		result.Range.Start.Line = f.Pos.Line - 1
+	}
+	if f.Pos.Column > 0 {
+		result.Range.Start.Character = f.Pos.Column - 1
+	}
+	result.Range.End = position{Line: result.Range.Start.Line + 1}
+	return result
+}
+
+func (s *Server) reply(id *json.RawMessage, result interface{}) error {
+	if result == nil {
+		// The result of shutdown is null, so it's set explicitly
+		result = json.RawMessage("null")
+	}
+	return s.write(&message{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+func (s *Server) write(msg *message) error {
+	content, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(s.out, "Content-Length: "+strconv.Itoa(len(content))+"\r\n\r\n"+string(content))
+	return err
+}
+
+// readMessage reads a message with its Content-Length header
+func readMessage(reader *textproto.Reader) (*message, error) {
+	header, err := reader.ReadMIMEHeader()
+	if err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, io.EOF
+		}
+		return nil, errors.Wrap(err, "cannot read header")
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return nil, errors.Errorf("invalid Content-Length %q", header.Get("Content-Length"))
+	}
+	content := make([]byte, length)
+	if _, err = io.ReadFull(reader.R, content); err != nil {
+		return nil, errors.Wrap(err, "cannot read message")
+	}
+	var msg message
+	if err = json.Unmarshal(content, &msg); err != nil {
+		return nil, errors.Wrap(err, "invalid message")
+	}
+	return &msg, nil
+}
+
+func uriPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return "", errors.Errorf("unsupported document %v, expected a file URI", uri)
+	}
+	location := u.Path
+	// Windows paths are /C:/dir/file
+	if filepath.VolumeName(strings.TrimPrefix(location, "/")) != "" {
+		location = strings.TrimPrefix(location, "/")
+	}
+	return filepath.Clean(filepath.FromSlash(location)), nil
+}
+
+func pathURI(location string) string {
+	location = filepath.ToSlash(location)
+	if !strings.HasPrefix(location, "/") {
+		location = "/" + location
+	}
+	return (&url.URL{Scheme: "file", Path: location}).String()
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsp_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/gotestmd/internal/lsp"
+)
+
+func request(t *testing.T, buf *bytes.Buffer, id int, method string, params interface{}) {
+	msg := map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params}
+	if id > 0 {
+		msg["id"] = id
+	}
+	content, err := json.Marshal(msg)
+	require.NoError(t, err)
+	_, _ = fmt.Fprintf(buf, "Content-Length: %d\r\n\r\n%s", len(content), content)
+}
+
+func responses(t *testing.T, out *bytes.Buffer) []map[string]interface{} {
+	var result []map[string]interface{}
+	reader := textproto.NewReader(bufio.NewReader(out))
+	for {
+		header, err := reader.ReadMIMEHeader()
+		if err != nil {
+			return result
+		}
+		length, err := strconv.Atoi(header.Get("Content-Length"))
+		require.NoError(t, err)
+		content := make([]byte, length)
+		_, err = io.ReadFull(reader.R, content)
+		require.NoError(t, err)
+		var msg map[string]interface{}
+		require.NoError(t, json.Unmarshal(content, &msg))
+		result = append(result, msg)
+	}
+}
+
+func TestServer(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, "README.md")
+	require.NoError(t, os.WriteFile(file, []byte("# Suite\n\n## Run\n\n```bash\necho run\n```\n\n## Cleanup\n\n```bash\necho cleanup\n```\n"), 0o600))
+	uri := "file://" + filepath.ToSlash(file)
+
+	var in, out bytes.Buffer
+	request(t, &in, 1, "initialize", map[string]interface{}{})
+	request(t, &in, 0, "initialized", map[string]interface{}{})
+	request(t, &in, 0, "textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "text": "# Suite\n\n## Requires\n\n- [Missing](../missing)\n"},
+	})
+	request(t, &in, 0, "textDocument/didClose", map[string]interface{}{"textDocument": map[string]interface{}{"uri": uri}})
+	request(t, &in, 2, "textDocument/hover", map[string]interface{}{})
+	request(t, &in, 3, "shutdown", nil)
+	request(t, &in, 0, "exit", nil)
+	request(t, &in, 4, "shutdown", nil)
+	require.NoError(t, lsp.New(root, func() []string { return []string{root} }).Serve(&in, &out))
+
+	msgs := responses(t, &out)
+	require.Len(t, msgs, 5)
+	require.Equal(t, "gotestmd", msgs[0]["result"].(map[string]interface{})["serverInfo"].(map[string]interface{})["name"])
+
+	require.Equal(t, "textDocument/publishDiagnostics", msgs[1]["method"])
+	params := msgs[1]["params"].(map[string]interface{})
+	require.Equal(t, uri, params["uri"])
+	diagnostics := params["diagnostics"].([]interface{})
+	require.Len(t, diagnostics, 1)
+	diagnostic := diagnostics[0].(map[string]interface{})
+	require.Equal(t, "required example ../missing doesn't exist", diagnostic["message"])
+	require.Equal(t, "broken-link", diagnostic["code"])
+	require.Equal(t, float64(1), diagnostic["severity"])
+	require.Equal(t, float64(2), diagnostic["range"].(map[string]interface{})["start"].(map[string]interface{})["line"])
+
+	// The document is fixed on the disk, so the diagnostics are cleared
+	require.Empty(t, msgs[2]["params"].(map[string]interface{})["diagnostics"])
+	require.Equal(t, float64(-32601), msgs[3]["error"].(map[string]interface{})["code"])
+	require.Contains(t, msgs[4], "result")
+	require.Nil(t, msgs[4]["result"])
+}
+
+func TestServerDocuments(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, "README.md")
+	require.NoError(t, os.WriteFile(file, []byte("# Suite\n\n## Run\n\n```bash\necho run\n```\n"), 0o600))
+	uri := "file://" + filepath.ToSlash(file)
+	broken := "# Suite\n\n## Requires\n\n- [Missing](../missing)\n"
+
+	var in, out bytes.Buffer
+	request(t, &in, 0, "textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": "untitled:Untitled-1", "text": broken},
+	})
+	request(t, &in, 0, "textDocument/didOpen", "invalid")
+	request(t, &in, 0, "textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "text": "# Suite\n"},
+	})
+	for i := 0; i < 3; i++ {
+		request(t, &in, 0, "textDocument/didChange", map[string]interface{}{
+			"textDocument":   map[string]interface{}{"uri": uri},
+			"contentChanges": []map[string]interface{}{{"text": broken}},
+		})
+	}
+	request(t, &in, 0, "textDocument/didSave", map[string]interface{}{"textDocument": map[string]interface{}{"uri": uri}})
+	request(t, &in, 1, "shutdown", nil)
+
+	var walks int
+	require.NoError(t, lsp.New(root, func() []string {
+		walks++
+		return []string{root}
+	}).Serve(&in, &out))
+	require.Equal(t, 2, walks)
+
+	// Invalid documents are skipped and changes are checked when the document is saved
+	msgs := responses(t, &out)
+	require.Len(t, msgs, 2)
+	require.Equal(t, "textDocument/publishDiagnostics", msgs[0]["method"])
+	require.Len(t, msgs[0]["params"].(map[string]interface{})["diagnostics"], 1)
+	require.Contains(t, msgs[1], "result")
+}
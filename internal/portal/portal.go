@@ -0,0 +1,348 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package portal serves the linked examples as web pages showing their documents and setup chains
+package portal
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"html/template"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+)
+
+// RunFunc runs the suite of the example writing its output to w
+type RunFunc func(ctx context.Context, e *linker.LinkedExample, w io.Writer) error
+
+// Portal is an http.Handler serving the pages of the examples
+type Portal struct {
+	examples []*linker.LinkedExample
+	index    map[string]*linker.LinkedExample
+	run      RunFunc
+	mux      *http.ServeMux
+	// addr is the listen address the Host and Origin headers of the requests are checked against
+	addr string
+	// token is embedded in the run forms, so other sites can't run the suites
+	token string
+}
+
+// Option is an option for the Portal
+type Option func(p *Portal)
+
+// WithRun allows running the suites of the examples from their pages
+func WithRun(run RunFunc) Option {
+	return func(p *Portal) {
+		p.run = run
+	}
+}
+
+// WithAddr rejects the requests with Host and Origin headers not matching the listen address, so pages of other sites
+// can't reach the portal by DNS rebinding
+func WithAddr(addr string) Option {
+	return func(p *Portal) {
+		p.addr = addr
+	}
+}
+
+// New creates a Portal serving the examples
+func New(examples []*linker.LinkedExample, options ...Option) *Portal {
+	p := &Portal{
+		examples: examples,
+		index:    map[string]*linker.LinkedExample{},
+		mux:      http.NewServeMux(),
+		token:    newToken(),
+	}
+	for _, e := range examples {
+		p.index[e.Name] = e
+	}
+	for _, o := range options {
+		o(p)
+	}
+	p.mux.HandleFunc("/", p.serveIndex)
+	p.mux.HandleFunc("/example", p.serveExample)
+	p.mux.HandleFunc("/run", p.serveRun)
+	return p
+}
+
+// ServeHTTP serves the index page with the tree of the examples, the pages of the examples and the output of the runs
+func (p *Portal) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !p.sameHost(r.Host) {
+		http.Error(w, "unexpected host "+r.Host, http.StatusForbidden)
+		return
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if u, err := url.Parse(origin); err != nil || !p.sameHost(u.Host) {
+			http.Error(w, "unexpected origin "+origin, http.StatusForbidden)
+			return
+		}
+	}
+	p.mux.ServeHTTP(w, r)
+}
+
+// sameHost returns true if the host matches the listen address. Any host matches the unspecified address and loopback
+// hosts match each other if the ports are the same
+func (p *Portal) sameHost(host string) bool {
+	if p.addr == "" || host == p.addr {
+		return true
+	}
+	addrHost, addrPort, err := net.SplitHostPort(p.addr)
+	if err != nil {
+		return false
+	}
+	reqHost, reqPort, err := net.SplitHostPort(host)
+	if err != nil || reqPort != addrPort {
+		return false
+	}
+	if ip := net.ParseIP(addrHost); addrHost == "" || ip != nil && ip.IsUnspecified() {
+		return true
+	}
+	return isLoopback(addrHost) && isLoopback(reqHost)
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// newToken returns a random token for the run forms
+func newToken() string {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		logrus.Fatalf("cannot generate a token: %v", err)
+	}
+	return hex.EncodeToString(b[:])
+}
+
+type node struct {
+	Example  *linker.LinkedExample
+	Children []*node
+}
+
+func newNode(e *linker.LinkedExample) *node {
+	var result = &node{Example: e}
+	for _, child := range e.Children {
+		result.Children = append(result.Children, newNode(child))
+	}
+	return result
+}
+
+func (p *Portal) serveIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	var roots []*node
+	for _, e := range linker.Roots(p.examples) {
+		roots = append(roots, newNode(e))
+	}
+	p.render(w, "index", roots)
+}
+
+type examplePage struct {
+	Example    *linker.LinkedExample
+	Setup      []*linker.LinkedExample
+	Dependents []*linker.LinkedExample
+	Document   string
+	CanRun     bool
+	Token      string
+}
+
+func (p *Portal) serveExample(w http.ResponseWriter, r *http.Request) {
+	e, ok := p.index[r.URL.Query().Get("name")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	page := &examplePage{
+		Example:    e,
+		Setup:      linker.Dependencies(p.examples, e),
+		Dependents: linker.Dependents(p.examples, e),
+		CanRun:     p.run != nil && e.Remote == nil && !linker.IsURL(e.Source),
+		Token:      p.token,
+	}
+	if linker.IsURL(e.Source) {
+		page.Document = "The document is downloaded from " + e.Source
+	} else if content, err := os.ReadFile(filepath.Clean(e.Source)); err != nil {
+		page.Document = "Cannot read the document: " + err.Error()
+	} else {
+		page.Document = string(content)
+	}
+	p.render(w, "example", page)
+}
+
+func (p *Portal) serveRun(w http.ResponseWriter, r *http.Request) {
+	if p.run == nil {
+		http.Error(w, "running suites is disabled", http.StatusForbidden)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "use POST to run suites", http.StatusMethodNotAllowed)
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(r.PostFormValue("token")), []byte(p.token)) != 1 {
+		http.Error(w, "invalid token, reload the page", http.StatusForbidden)
+		return
+	}
+	e, ok := p.index[r.URL.Query().Get("name")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	out := &flushWriter{w: w}
+	if flusher, ok := w.(http.Flusher); ok {
+		out.flusher = flusher
+	}
+	logrus.Infof("running %v", displayName(e))
+	if err := p.run(r.Context(), e, out); err != nil {
+		_, _ = io.WriteString(out, "\nFAILED: "+err.Error()+"\n")
+		return
+	}
+	_, _ = io.WriteString(out, "\nPASSED\n")
+}
+
+func (p *Portal) render(w http.ResponseWriter, name string, data interface{}) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := pages.ExecuteTemplate(w, name, data); err != nil {
+		logrus.Errorf("cannot render %v page: %v", name, err)
+	}
+}
+
+// flushWriter sends the written output to the client right away
+type flushWriter struct {
+	w       io.Writer
+	flusher http.Flusher
+}
+
+func (f *flushWriter) Write(b []byte) (int, error) {
+	n, err := f.w.Write(b)
+	if f.flusher != nil {
+		f.flusher.Flush()
+	}
+	return n, err
+}
+
+// displayName returns the name of the example or . for the root one
+func displayName(e *linker.LinkedExample) string {
+	if e.Name == "" {
+		return "."
+	}
+	return e.Name
+}
+
+var pages = template.Must(template.New("pages").Funcs(template.FuncMap{"name": displayName}).Parse(`
+{{- define "header" -}}
+<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ . }} - gotestmd</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+pre { background: #f6f8fa; padding: 1em; overflow: auto; }
+li { margin: 0.2em 0; }
+</style>
+</head>
+<body>
+<p><a href="/">Examples</a></p>
+{{- end -}}
+
+{{- define "footer" -}}
+</body>
+</html>
+{{- end -}}
+
+{{- define "node" -}}
+<li><a href="/example?name={{ .Example.Name }}">{{ name .Example }}</a>
+{{- with .Example.Requires }} <small>requires {{ range $i, $r := . }}{{ if $i }}, {{ end }}{{ $r }}{{ end }}</small>{{ end }}
+{{- if .Children }}
+<ul>
+{{- range .Children }}
+{{ template "node" . }}
+{{- end }}
+</ul>
+{{- end -}}
+</li>
+{{- end -}}
+
+{{- define "link" -}}
+<a href="/example?name={{ .Name }}">{{ name . }}</a>
+{{- end -}}
+
+{{- define "index" -}}
+{{ template "header" "Examples" }}
+<h1>Examples</h1>
+<ul>
+{{- range . }}
+{{ template "node" . }}
+{{- end }}
+</ul>
+{{ template "footer" }}
+{{- end -}}
+
+{{- define "example" -}}
+{{ template "header" (name .Example) }}
+<h1>{{ name .Example }}</h1>
+<p>{{ .Example.Source }}</p>
+{{- if .CanRun }}
+<form method="post" action="/run?name={{ .Example.Name }}"><input type="hidden" name="token" value="{{ .Token }}"><button type="submit">Run</button></form>
+{{- end }}
+<h2>Setup chain</h2>
+{{- if .Setup }}
+<ol>
+{{- range .Setup }}
+<li>{{ template "link" . }}</li>
+{{- end }}
+</ol>
+{{- else }}
+<p>Nothing is set up before the example.</p>
+{{- end }}
+{{- with .Example.Children }}
+<h2>Includes</h2>
+<ul>
+{{- range . }}
+<li>{{ template "link" . }}</li>
+{{- end }}
+</ul>
+{{- end }}
+{{- with .Dependents }}
+<h2>Dependents</h2>
+<ul>
+{{- range . }}
+<li>{{ template "link" . }}</li>
+{{- end }}
+</ul>
+{{- end }}
+<h2>Document</h2>
+<pre>{{ .Document }}</pre>
+{{ template "footer" }}
+{{- end -}}
+`))
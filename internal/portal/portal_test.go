@@ -0,0 +1,139 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portal_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/gotestmd/internal/portal"
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
+)
+
+func get(t *testing.T, handler http.Handler, method, target string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+	return recorder
+}
+
+// run posts the run form of the example with the token
+func run(t *testing.T, handler http.Handler, name, token string, header http.Header) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/run?name="+name, strings.NewReader(url.Values{"token": {token}}.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	for key, values := range header {
+		request.Header[key] = values
+	}
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+// token returns the token of the run form of the example page
+func token(t *testing.T, handler http.Handler, name string) string {
+	match := regexp.MustCompile(`name="token" value="([0-9a-f]+)"`).FindStringSubmatch(get(t, handler, http.MethodGet, "/example?name="+name).Body.String())
+	require.NotNil(t, match)
+	return match[1]
+}
+
+func TestPortal(t *testing.T) {
+	root := t.TempDir()
+	readme := filepath.Join(root, "consumer", "README.md")
+	require.NoError(t, os.MkdirAll(filepath.Dir(readme), 0o700))
+	require.NoError(t, os.WriteFile(readme, []byte("# Consumer <b>\n"), 0o600))
+	examples, err := linker.New(root).Link(
+		&parser.Example{Dir: filepath.Join(root, "consumer"), Source: readme, Requires: []string{"../spire"}, Run: []string{"echo consumer"}},
+		&parser.Example{Dir: filepath.Join(root, "spire"), Includes: []string{"check"}, Run: []string{"echo spire"}},
+		&parser.Example{Dir: filepath.Join(root, "spire", "check"), Run: []string{"echo check"}},
+	)
+	require.NoError(t, err)
+
+	handler := portal.New(examples)
+	index := get(t, handler, http.MethodGet, "/")
+	require.Equal(t, http.StatusOK, index.Code)
+	require.Contains(t, index.Body.String(), `<li><a href="/example?name=consumer">consumer</a> <small>requires spire</small></li>`)
+	require.Contains(t, index.Body.String(), `<li><a href="/example?name=spire%2fcheck">spire/check</a></li>`)
+
+	page := get(t, handler, http.MethodGet, "/example?name=consumer")
+	require.Equal(t, http.StatusOK, page.Code)
+	require.Contains(t, page.Body.String(), "<h2>Setup chain</h2>\n<ol>\n<li><a href=\"/example?name=spire\">spire</a></li>\n</ol>")
+	require.Contains(t, page.Body.String(), "<pre># Consumer &lt;b&gt;\n</pre>")
+	require.NotContains(t, page.Body.String(), "Run</button>")
+
+	require.Equal(t, http.StatusNotFound, get(t, handler, http.MethodGet, "/example?name=missing").Code)
+	require.Equal(t, http.StatusForbidden, get(t, handler, http.MethodPost, "/run?name=consumer").Code)
+}
+
+func TestPortalRun(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/ok", Run: []string{"echo ok"}},
+		&parser.Example{Dir: "examples/failed", Run: []string{"exit 1"}},
+	)
+	require.NoError(t, err)
+	handler := portal.New(examples, portal.WithRun(func(_ context.Context, e *linker.LinkedExample, w io.Writer) error {
+		_, _ = io.WriteString(w, "running "+e.Name)
+		if e.Name == "failed" {
+			return errors.New("exit status 1")
+		}
+		return nil
+	}))
+
+	require.Contains(t, get(t, handler, http.MethodGet, "/example?name=ok").Body.String(), `<form method="post" action="/run?name=ok"><input type="hidden" name="token" value="`)
+	require.Equal(t, http.StatusMethodNotAllowed, get(t, handler, http.MethodGet, "/run?name=ok").Code)
+
+	secret := token(t, handler, "ok")
+	ok := run(t, handler, "ok", secret, nil)
+	require.Equal(t, http.StatusOK, ok.Code)
+	require.Equal(t, "running ok\nPASSED\n", ok.Body.String())
+	require.Equal(t, "running failed\nFAILED: exit status 1\n", run(t, handler, "failed", secret, nil).Body.String())
+
+	require.Equal(t, http.StatusForbidden, get(t, handler, http.MethodPost, "/run?name=ok").Code)
+	require.Equal(t, http.StatusForbidden, run(t, handler, "ok", "wrong", nil).Code)
+}
+
+func TestPortalAddr(t *testing.T) {
+	examples, err := linker.New("examples/").Link(&parser.Example{Dir: "examples/ok", Run: []string{"echo ok"}})
+	require.NoError(t, err)
+	handler := portal.New(examples, portal.WithAddr("localhost:8080"))
+
+	request := func(host, origin string) int {
+		recorder := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Host = host
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		handler.ServeHTTP(recorder, r)
+		return recorder.Code
+	}
+	require.Equal(t, http.StatusOK, request("localhost:8080", ""))
+	require.Equal(t, http.StatusOK, request("127.0.0.1:8080", "http://localhost:8080"))
+	require.Equal(t, http.StatusForbidden, request("evil.example.com:8080", ""))
+	require.Equal(t, http.StatusForbidden, request("localhost:8081", ""))
+	require.Equal(t, http.StatusForbidden, request("localhost:8080", "http://evil.example.com"))
+}
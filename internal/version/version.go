@@ -0,0 +1,96 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package version provides the version of gotestmd set with -ldflags or taken from the Go build info
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// The values are set with -ldflags, e.g.
+// -X github.com/networkservicemesh/gotestmd/internal/version.Version=v1.2.3
+// -X github.com/networkservicemesh/gotestmd/internal/version.Commit=abc1234
+// -X github.com/networkservicemesh/gotestmd/internal/version.Date=2024-01-02T15:04:05Z
+// Empty values are taken from the build info
+var (
+	Version string
+	Commit  string
+	Date    string
+)
+
+// develVersion is the version of binaries built from a checkout without -ldflags
+const develVersion = "(devel)"
+
+// Info is the version of gotestmd
+type Info struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit,omitempty"`
+	// Date is the build date set with -ldflags or the time of the commit
+	Date string `json:"date,omitempty"`
+	// Modified is set if the binary is built from a checkout with uncommitted changes
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Get returns the version of the binary. Module versions are set by go install MODULE@VERSION, VCS info is set by
+// go build in a checkout
+func Get() *Info {
+	var result = &Info{Version: develVersion, GoVersion: runtime.Version()}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if info.Main.Version != "" {
+			result.Version = info.Main.Version
+		}
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				result.Commit = setting.Value
+			case "vcs.time":
+				result.Date = setting.Value
+			case "vcs.modified":
+				result.Modified = setting.Value == "true"
+			}
+		}
+	}
+	for _, value := range []struct{ from, to *string }{{&Version, &result.Version}, {&Commit, &result.Commit}, {&Date, &result.Date}} {
+		if *value.from != "" {
+			*value.to = *value.from
+		}
+	}
+	return result
+}
+
+// String returns the version with the commit and the date, e.g. v1.2.3 (commit abc1234, 2024-01-02T15:04:05Z)
+func (i *Info) String() string {
+	var details []string
+	if i.Commit != "" {
+		commit := "commit " + i.Commit
+		if i.Modified {
+			commit += " with changes"
+		}
+		details = append(details, commit)
+	}
+	if i.Date != "" {
+		details = append(details, i.Date)
+	}
+	if len(details) == 0 {
+		return i.Version
+	}
+	return fmt.Sprintf("%v (%v)", i.Version, strings.Join(details, ", "))
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package version_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/gotestmd/internal/version"
+)
+
+func TestGet(t *testing.T) {
+	version.Version, version.Commit, version.Date = "v1.2.3", "abc1234", "2024-01-02T15:04:05Z"
+	t.Cleanup(func() {
+		version.Version, version.Commit, version.Date = "", "", ""
+	})
+
+	info := version.Get()
+	require.Equal(t, "v1.2.3", info.Version)
+	require.NotEmpty(t, info.GoVersion)
+
+	info.Modified = false
+	require.Equal(t, "v1.2.3 (commit abc1234, 2024-01-02T15:04:05Z)", info.String())
+	info.Modified = true
+	require.Equal(t, "v1.2.3 (commit abc1234 with changes, 2024-01-02T15:04:05Z)", info.String())
+	require.Equal(t, "v1.2.3", (&version.Info{Version: "v1.2.3"}).String())
+}
@@ -26,6 +26,6 @@ import (
 
 func main() {
 	if err := gotestmd.New().Execute(); err != nil {
-		os.Exit(-1)
+		os.Exit(gotestmd.ExitCode(err))
 	}
 }
@@ -19,11 +19,16 @@
 package main_test
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/networkservicemesh/gotestmd/cmd/gotestmd"
 	"github.com/networkservicemesh/gotestmd/pkg/bash"
 )
 
@@ -71,6 +76,47 @@ EOF
 	require.Zero(t, exitCode)
 }
 
+func TestExamplesTestingFormat(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.RemoveAll("test-testing-examples")
+	})
+	runner, err := bash.New()
+	require.NoError(t, err)
+	defer runner.Close()
+	_, _, exitCode, err := runner.Run("go install ./...")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	_, _, exitCode, err = runner.Run("gotestmd examples/ test-testing-examples/ --format=testing")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	_, _, exitCode, err = runner.Run(`cat > test-testing-examples/entry_point_test.go <<EOF
+package suites
+
+import (
+	"testing"
+
+	"github.com/networkservicemesh/gotestmd/test-testing-examples/helloworld"
+	"github.com/networkservicemesh/gotestmd/test-testing-examples/producer/consumer2"
+	"github.com/networkservicemesh/gotestmd/test-testing-examples/tree"
+)
+
+func TestEntryPoint(t *testing.T) {
+	t.Run("Helloworld", helloworld.Run)
+	t.Run("Tree", tree.Run)
+	t.Run("Consumer2", consumer2.Run)
+}
+EOF
+`)
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	_, _, exitCode, err = runner.Run("go test ./test-testing-examples/... ")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+}
+
 func TestBashSuite(t *testing.T) {
 	t.Cleanup(func() {
 		_ = os.RemoveAll("test-bash-examples")
@@ -86,11 +132,16 @@ func TestBashSuite(t *testing.T) {
 	require.NoError(t, err)
 	require.Zero(t, exitCode)
 
-	_, _, exitCode, err = runner.Run("./test-bash-examples/tree/suite.gen.sh setup")
+	_, _, exitCode, err = runner.Run("GOTESTMD_JUNIT=test-bash-examples/junit.xml ./test-bash-examples/tree/setup.sh")
 	require.NoError(t, err)
 	require.Zero(t, exitCode)
 
-	_, _, exitCode, err = runner.Run("./test-bash-examples/tree/suite.gen.sh cleanup")
+	junit, err := os.ReadFile("test-bash-examples/junit.xml")
+	require.NoError(t, err)
+	require.Contains(t, string(junit), `<testsuite name="tree" tests="1" failures="0">`)
+	require.Contains(t, string(junit), `<testcase classname="tree" name="setup"`)
+
+	_, _, exitCode, err = runner.Run("GOTESTMD_JUNIT=test-bash-examples/junit.xml ./test-bash-examples/tree/cleanup.sh")
 	require.NoError(t, err)
 	require.Zero(t, exitCode)
 }
@@ -106,19 +157,19 @@ func TestBashTest(t *testing.T) {
 	require.NoError(t, err)
 	require.Zero(t, exitCode)
 
-	_, _, exitCode, err = runner.Run("gotestmd examples/ test-bash-examples/ --bash --match=LeafA")
+	_, _, exitCode, err = runner.Run("gotestmd examples/ test-bash-examples/ --bash --match=Leafa")
 	require.NoError(t, err)
 	require.Zero(t, exitCode)
 
-	_, _, exitCode, err = runner.Run("./test-bash-examples/tree/suite.gen.sh setup")
+	_, _, exitCode, err = runner.Run("GOTESTMD_JUNIT=test-bash-examples/junit.xml ./test-bash-examples/tree/setup.sh")
 	require.NoError(t, err)
 	require.Zero(t, exitCode)
 
-	_, _, exitCode, err = runner.Run("./test-bash-examples/tree/suite.gen.sh testLeafA")
+	_, _, exitCode, err = runner.Run("GOTESTMD_JUNIT=test-bash-examples/junit.xml ./test-bash-examples/tree/test_leafa.sh")
 	require.NoError(t, err)
 	require.Zero(t, exitCode)
 
-	_, _, exitCode, err = runner.Run("./test-bash-examples/tree/suite.gen.sh cleanup")
+	_, _, exitCode, err = runner.Run("GOTESTMD_JUNIT=test-bash-examples/junit.xml ./test-bash-examples/tree/cleanup.sh")
 	require.NoError(t, err)
 	require.Zero(t, exitCode)
 }
@@ -139,3 +190,689 @@ func TestBashNoMatchesFound(t *testing.T) {
 	require.NoError(t, err)
 	require.NotZero(t, exitCode)
 }
+
+func TestIncludeExclude(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.RemoveAll("test-selected-examples")
+	})
+	runner, err := bash.New()
+	require.NoError(t, err)
+	defer runner.Close()
+	_, _, exitCode, err := runner.Run("go install ./...")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	_, stderr, exitCode, err := runner.Run("gotestmd examples/ test-selected-examples/ --format=json --exclude 'examples/Tree/SubTree'")
+	require.NoError(t, err)
+	require.NotZero(t, exitCode)
+	require.Contains(t, stderr, "examples/Tree/README.md depends on examples/Tree/SubTree excluded by --include and --exclude")
+
+	_, _, exitCode, err = runner.Run("gotestmd examples/ test-selected-examples/ --format=json --exclude 'examples/Tree/SubTree' --excluded-deps include")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	content, err := os.ReadFile("test-selected-examples/examples.json")
+	require.NoError(t, err)
+	require.Contains(t, string(content), `"name": "Tree/SubTree"`)
+	require.Contains(t, string(content), `"name": "Tree/SubTree/LeafB"`)
+
+	_, _, exitCode, err = runner.Run("gotestmd examples/ test-selected-examples/ --format=json --include 'examples/Producer/**'")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	content, err = os.ReadFile("test-selected-examples/examples.json")
+	require.NoError(t, err)
+	require.Contains(t, string(content), `"name": "Producer/Consumer1"`)
+	require.NotContains(t, string(content), `"name": "HelloWorld"`)
+}
+
+func TestVerify(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.RemoveAll("test-verify-examples")
+	})
+	runner, err := bash.New()
+	require.NoError(t, err)
+	defer runner.Close()
+	_, _, exitCode, err := runner.Run("go install ./...")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	_, _, exitCode, err = runner.Run("gotestmd examples/ test-verify-examples/")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	_, _, exitCode, err = runner.Run("gotestmd verify examples/ test-verify-examples/")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	require.NoError(t, os.Remove("test-verify-examples/helloworld/suite.gen.go"))
+	require.NoError(t, os.WriteFile("test-verify-examples/tree/suite.gen.go", []byte("// Source: examples/Tree/README.md sha256:0\n"), os.ModePerm))
+
+	stdout, stderr, exitCode, err := runner.Run("gotestmd verify examples/ test-verify-examples/")
+	require.NoError(t, err)
+	require.NotZero(t, exitCode)
+	require.Contains(t, stdout, "missing test-verify-examples/helloworld/suite.gen.go")
+	require.Contains(t, stdout, "outdated test-verify-examples/tree/suite.gen.go: examples/Tree/LeafA/README.md added, examples/Tree/LeafC/README.md added, examples/Tree/README.md changed")
+	require.Contains(t, stderr, "2 of 8 generated files are out of date")
+}
+
+func TestList(t *testing.T) {
+	runner, err := bash.New()
+	require.NoError(t, err)
+	defer runner.Close()
+	_, _, exitCode, err := runner.Run("go install ./...")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	stdout, _, exitCode, err := runner.Run("gotestmd list examples/ test-list-examples/")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.Contains(t, stdout, "tree examples/Tree/README.md\n  tests: Leafa Leafc\n  includes: tree-subtree")
+	require.Contains(t, stdout, "producer-consumer2 examples/Producer/Consumer2/README.md\n  requires: producer\n")
+	require.NoDirExists(t, "test-list-examples")
+
+	stdout, _, exitCode, err = runner.Run("gotestmd list examples/ test-list-examples/ --json")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.Contains(t, stdout, `"name": "tree-subtree"`)
+
+	// OUTPUT_DIR is optional, since nothing is written
+	stdout, _, exitCode, err = runner.Run("gotestmd list examples/")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.Contains(t, stdout, "tree examples/Tree/README.md\n  tests: Leafa Leafc\n  includes: tree-subtree")
+
+	_, stderr, exitCode, err := runner.Run("gotestmd list")
+	require.NoError(t, err)
+	require.Equal(t, gotestmd.ExitError, exitCode)
+	require.Contains(t, stderr, "expected INPUT_DIR argument")
+}
+
+func TestTree(t *testing.T) {
+	runner, err := bash.New()
+	require.NoError(t, err)
+	defer runner.Close()
+	_, _, exitCode, err := runner.Run("go install ./...")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	stdout, _, exitCode, err := runner.Run("gotestmd tree examples/ test-tree-examples/ --prune 'examples/Tree/SubTree/**' --exclude examples/HelloWorld")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.Contains(t, stdout, "|-- HelloWorld [excluded]\n")
+	require.Contains(t, stdout, "|-- Producer/Consumer2 -> requires Producer\n")
+	require.Contains(t, stdout, "`-- Tree\n    |-- Tree/LeafA (test)\n    |-- Tree/LeafC (test)\n    `-- Tree/SubTree [pruned, matches examples/Tree/SubTree/**]\n")
+	require.NoDirExists(t, "test-tree-examples")
+
+	stdout, _, exitCode, err = runner.Run("gotestmd tree examples/")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.Contains(t, stdout, "|-- Producer/Consumer2 -> requires Producer\n")
+}
+
+func TestRun(t *testing.T) {
+	runner, err := bash.New()
+	require.NoError(t, err)
+	defer runner.Close()
+	_, _, exitCode, err := runner.Run("go install ./...")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	stdout, _, exitCode, err := runner.Run("gotestmd run examples/ examples/Tree/SubTree")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.Contains(t, stdout, "I'm sub tree\n")
+	require.Contains(t, stdout, "I'm leaf B\n")
+	require.Contains(t, stdout, "Sub tree is done\n")
+	require.NotContains(t, stdout, "I'm leaf A")
+
+	_, stderr, exitCode, err := runner.Run("gotestmd run examples/ UnmatchablePattern")
+	require.NoError(t, err)
+	require.NotZero(t, exitCode)
+	require.Contains(t, stderr, "No matches found for pattern: UnmatchablePattern")
+}
+
+func TestClean(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.RemoveAll("test-clean-examples")
+	})
+	runner, err := bash.New()
+	require.NoError(t, err)
+	defer runner.Close()
+	_, _, exitCode, err := runner.Run("go install ./...")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	_, _, exitCode, err = runner.Run("gotestmd examples/ test-clean-examples/")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.NoError(t, os.MkdirAll("test-clean-examples/removed/nested", 0o700))
+	require.NoError(t, os.WriteFile("test-clean-examples/removed/nested/suite.gen.go", []byte("// Code generated by gotestmd DO NOT EDIT.\n// Source: examples/Removed/README.md sha256:00\npackage nested\n"), 0o600))
+
+	stdout, _, exitCode, err := runner.Run("gotestmd clean test-clean-examples/ --dry-run")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.Equal(t, "would remove test-clean-examples/removed/nested/suite.gen.go: source examples/Removed/README.md doesn't exist", stdout)
+	require.FileExists(t, "test-clean-examples/removed/nested/suite.gen.go")
+
+	require.NoError(t, os.WriteFile("test-clean-examples/removed/custom.go", []byte("// Source: examples/Removed/README.md sha256:00\npackage removed\n"), 0o600))
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	stdout, _, exitCode, err = runner.Run("(cd " + t.TempDir() + " && gotestmd clean " + filepath.Join(wd, "test-clean-examples") + " --dry-run)")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.Equal(t, "would remove "+filepath.Join(wd, "test-clean-examples/removed/nested/suite.gen.go")+": source examples/Removed/README.md doesn't exist", stdout)
+
+	_, stderr, exitCode, err := runner.Run("gotestmd clean " + t.TempDir())
+	require.NoError(t, err)
+	require.Equal(t, gotestmd.ExitError, exitCode)
+	require.Contains(t, stderr, "cannot find go.mod")
+
+	_, _, exitCode, err = runner.Run("gotestmd examples/ test-clean-examples/ --clean")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.NoFileExists(t, "test-clean-examples/removed/nested/suite.gen.go")
+	require.FileExists(t, "test-clean-examples/removed/custom.go")
+	require.FileExists(t, "test-clean-examples/tree/subtree/suite.gen.go")
+}
+
+func TestDryRun(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.RemoveAll("test-dry-run-examples")
+	})
+	runner, err := bash.New()
+	require.NoError(t, err)
+	defer runner.Close()
+	_, _, exitCode, err := runner.Run("go install ./...")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	stdout, _, exitCode, err := runner.Run("gotestmd examples/ test-dry-run-examples/ --dry-run")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.Contains(t, stdout, "\ncreate test-dry-run-examples/tree/suite.gen.go")
+	require.NoDirExists(t, "test-dry-run-examples")
+
+	stdout, _, exitCode, err = runner.Run("gotestmd examples/ test-dry-run-examples/ --diff")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.Contains(t, stdout, "--- /dev/null\n+++ b/test-dry-run-examples/tree/suite.gen.go\n")
+	require.NoDirExists(t, "test-dry-run-examples")
+
+	_, _, exitCode, err = runner.Run("gotestmd examples/ test-dry-run-examples/")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	stdout, _, exitCode, err = runner.Run("gotestmd examples/ test-dry-run-examples/ --dry-run")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.Contains(t, stdout, "\nunchanged test-dry-run-examples/tree/suite.gen.go")
+	stdout, _, exitCode, err = runner.Run("gotestmd examples/ test-dry-run-examples/ --diff")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.Empty(t, stdout)
+}
+
+func TestGoMatch(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.RemoveAll("test-match-examples")
+	})
+	runner, err := bash.New()
+	require.NoError(t, err)
+	defer runner.Close()
+	_, _, exitCode, err := runner.Run("go install ./...")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	_, _, exitCode, err = runner.Run("gotestmd examples/ test-match-examples/ --match=^consumer2$")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.FileExists(t, "test-match-examples/producer/consumer2/suite.gen.go")
+	require.FileExists(t, "test-match-examples/producer/suite.gen.go")
+	require.NoDirExists(t, "test-match-examples/producer/consumer3")
+	require.NoDirExists(t, "test-match-examples/tree")
+
+	_, stderr, exitCode, err := runner.Run("gotestmd examples/ test-match-examples/ --match=UnmatchablePattern")
+	require.NoError(t, err)
+	require.NotZero(t, exitCode)
+	require.Contains(t, stderr, "No matches found for pattern: UnmatchablePattern")
+}
+
+func TestTags(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.RemoveAll("test-tags-examples")
+	})
+	runner, err := bash.New()
+	require.NoError(t, err)
+	defer runner.Close()
+	_, _, exitCode, err := runner.Run("go install ./...")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	_, _, exitCode, err = runner.Run("mkdir test-tags-examples && cp -r examples test-tags-examples/input && " +
+		"sed -i '1i ---\\ntags: [slow]\\n---' test-tags-examples/input/Producer/README.md")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	_, _, exitCode, err = runner.Run("gotestmd test-tags-examples/input test-tags-examples/skip --skip-tags slow")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.FileExists(t, "test-tags-examples/skip/tree/suite.gen.go")
+	require.NoDirExists(t, "test-tags-examples/skip/producer")
+
+	_, _, exitCode, err = runner.Run("gotestmd test-tags-examples/input test-tags-examples/run --run-tags slow")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.FileExists(t, "test-tags-examples/run/producer/suite.gen.go")
+	require.NoDirExists(t, "test-tags-examples/run/producer/consumer2")
+	require.NoDirExists(t, "test-tags-examples/run/tree")
+}
+
+func TestRewrite(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.RemoveAll("test-rewrite-examples")
+	})
+	runner, err := bash.New()
+	require.NoError(t, err)
+	defer runner.Close()
+	_, _, exitCode, err := runner.Run("go install ./...")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	_, _, exitCode, err = runner.Run("gotestmd examples test-rewrite-examples --rewrite 's|^echo \"Hello|echo \"Hi|' --rewrite 's/^echo /sudo echo /'")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	content, err := os.ReadFile("test-rewrite-examples/helloworld/suite.gen.go")
+	require.NoError(t, err)
+	require.Contains(t, string(content), "sudo echo \"Hi")
+
+	_, stderr, exitCode, err := runner.Run("gotestmd examples test-rewrite-examples --rewrite 's/echo/'")
+	require.NoError(t, err)
+	require.Equal(t, 1, exitCode)
+	require.Contains(t, stderr, `invalid rewrite rule "s/echo/", expected s/REGEX/REPLACEMENT/`)
+}
+
+func TestGoGenerate(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.RemoveAll("test-gogenerate-examples")
+	})
+	runner, err := bash.New()
+	require.NoError(t, err)
+	defer runner.Close()
+	_, _, exitCode, err := runner.Run("go install ./...")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	_, _, exitCode, err = runner.Run("mkdir -p test-gogenerate-examples/producer/consumer2 test-gogenerate-examples/unknown")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	_, _, exitCode, err = runner.Run("(cd test-gogenerate-examples/producer/consumer2 && gotestmd go-generate ../../../examples ../..)")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.FileExists(t, "test-gogenerate-examples/producer/consumer2/suite.gen.go")
+	require.FileExists(t, "test-gogenerate-examples/producer/suite.gen.go")
+	require.NoDirExists(t, "test-gogenerate-examples/producer/consumer3")
+	require.NoDirExists(t, "test-gogenerate-examples/tree")
+
+	_, stderr, exitCode, err := runner.Run("(cd test-gogenerate-examples/unknown && gotestmd go-generate ../../examples ..)")
+	require.NoError(t, err)
+	require.Equal(t, gotestmd.ExitNoMatch, exitCode)
+	require.Contains(t, stderr, "no suite is generated into or from")
+}
+
+func TestHook(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.RemoveAll("test-hook-examples")
+	})
+	runner, err := bash.New()
+	require.NoError(t, err)
+	defer runner.Close()
+	_, _, exitCode, err := runner.Run("go install ./...")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	_, stderr, exitCode, err := runner.Run("mkdir test-hook-examples && cp -r examples test-hook-examples/ && (cd test-hook-examples && " +
+		"git init -q && gotestmd examples suites && git add -A && git -c user.name=test -c user.email=test@example.com commit -qm init && " +
+		"sed -i 's/echo/echo changed/' examples/Producer/Consumer2/README.md && git add examples)")
+	require.NoError(t, err)
+	require.Zero(t, exitCode, stderr)
+
+	stdout, _, exitCode, err := runner.Run("(cd test-hook-examples && gotestmd hook examples suites)")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.Equal(t, "staged suites/producer/consumer2/suite.gen.go", stdout)
+	stdout, _, exitCode, err = runner.Run("(cd test-hook-examples && git diff --cached --name-only)")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.Equal(t, "examples/Producer/Consumer2/README.md\nsuites/producer/consumer2/suite.gen.go", stdout)
+
+	_, stderr, exitCode, err = runner.Run("(cd test-hook-examples && gotestmd hook examples suites --no-stage --files examples/Tree/LeafA/README.md)")
+	require.NoError(t, err)
+	require.Zero(t, exitCode, stderr)
+
+	_, _, exitCode, err = runner.Run("(cd test-hook-examples && sed -i 's/changed/again/' examples/Producer/Consumer2/README.md && git add examples)")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	_, stderr, exitCode, err = runner.Run("(cd test-hook-examples && gotestmd hook examples suites --no-stage)")
+	require.NoError(t, err)
+	require.Equal(t, gotestmd.ExitDrift, exitCode)
+	require.Contains(t, stderr, "git add suites/producer/consumer2/suite.gen.go")
+
+	_, stderr, exitCode, err = runner.Run("(cd test-hook-examples && git add suites && git -c user.name=test -c user.email=test@example.com commit -qm consumer2 && " +
+		"git rm -rq examples/Tree/SubTree && sed -i '/Sub Tree/d' examples/Tree/README.md && git add examples)")
+	require.NoError(t, err)
+	require.Zero(t, exitCode, stderr)
+	stdout, _, exitCode, err = runner.Run("(cd test-hook-examples && gotestmd hook examples suites)")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.Equal(t, "staged suites/tree/suite.gen.go\nstaged removal of suites/tree/subtree/suite.gen.go", stdout)
+	require.NoFileExists(t, "test-hook-examples/suites/tree/subtree/suite.gen.go")
+	stdout, _, exitCode, err = runner.Run("(cd test-hook-examples && git status --short suites)")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.Equal(t, "D  suites/tree/subtree/suite.gen.go\nM  suites/tree/suite.gen.go", stdout)
+}
+
+func TestInit(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.RemoveAll("test-init-examples")
+	})
+	runner, err := bash.New()
+	require.NoError(t, err)
+	defer runner.Close()
+	_, _, exitCode, err := runner.Run("go install ./...")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	_, _, exitCode, err = runner.Run("gotestmd init test-init-examples/base")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	_, _, exitCode, err = runner.Run("gotestmd init test-init-examples/base/new-feature --requires ../")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	content, err := os.ReadFile("test-init-examples/base/new-feature/README.md")
+	require.NoError(t, err)
+	require.Contains(t, string(content), "# New feature\n")
+	require.Contains(t, string(content), "## Requires\n\n- [Base](../)\n")
+	require.Contains(t, string(content), "## Cleanup\n")
+
+	stdout, _, exitCode, err := runner.Run("gotestmd lint test-init-examples")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.Empty(t, stdout)
+
+	_, stderr, exitCode, err := runner.Run("gotestmd init test-init-examples/base")
+	require.NoError(t, err)
+	require.NotZero(t, exitCode)
+	require.Contains(t, stderr, "test-init-examples/base/README.md already exists")
+}
+
+func TestStats(t *testing.T) {
+	runner, err := bash.New()
+	require.NoError(t, err)
+	defer runner.Close()
+	_, _, exitCode, err := runner.Run("go install ./...")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	stdout, _, exitCode, err := runner.Run("gotestmd stats examples/ --top 1")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.Contains(t, stdout, "examples: 13\nsuites: 8\ntests: 5\n")
+	require.Contains(t, stdout, "max depth: 2\nlargest setups:\n  Producer/Consumer1: 2 (Producer, Producer/Consumer3)")
+}
+
+func TestExitCodes(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.RemoveAll("test-exit-examples")
+	})
+	runner, err := bash.New()
+	require.NoError(t, err)
+	defer runner.Close()
+	_, _, exitCode, err := runner.Run("go install ./...")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	for command, expected := range map[string]int{
+		"gotestmd examples/ test-exit-examples/ --bash":                          gotestmd.ExitError,
+		"gotestmd examples/ test-exit-examples/ --match=UnmatchablePattern":      gotestmd.ExitNoMatch,
+		"gotestmd examples/ test-exit-examples/ --exclude examples/Tree/SubTree": gotestmd.ExitLink,
+		"gotestmd verify examples/ test-exit-examples/":                          gotestmd.ExitDrift,
+		"gotestmd examples/ main.go":                                             gotestmd.ExitWrite,
+	} {
+		_, _, exitCode, err = runner.Run(command)
+		require.NoError(t, err)
+		require.Equal(t, expected, exitCode, command)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	runner, err := bash.New()
+	require.NoError(t, err)
+	defer runner.Close()
+	_, _, exitCode, err := runner.Run("go install ./...")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	// The usage is printed only for invalid arguments and flags
+	for command, usage := range map[string]bool{
+		"gotestmd examples/ test-usage-examples/ --unknown":  true,
+		"gotestmd examples/ test-usage-examples/ base extra": true,
+		"gotestmd lint": true,
+		"gotestmd examples/ test-usage-examples/ --match=Unmatchable":   false,
+		"gotestmd verify examples/ test-usage-examples/ --exclude=x/*[": false,
+	} {
+		_, stderr, exitCode, err := runner.Run(command)
+		require.NoError(t, err)
+		require.NotZero(t, exitCode, command)
+		require.Equal(t, usage, strings.Contains(stderr, "Usage:"), command)
+		require.Contains(t, stderr, "Error: ", command)
+	}
+	require.NoDirExists(t, "test-usage-examples")
+}
+
+func TestReport(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.RemoveAll("test-report-examples")
+	})
+	runner, err := bash.New()
+	require.NoError(t, err)
+	defer runner.Close()
+	_, _, exitCode, err := runner.Run("go install ./...")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	_, _, exitCode, err = runner.Run("gotestmd examples/ test-report-examples/ --prune 'examples/Tree/**' --report test-report-examples/summary.json")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	_, _, exitCode, err = runner.Run("gotestmd examples/ test-report-examples/ --match=helloworld --report test-report-examples/summary.json")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	var report struct {
+		Error string
+		Files []struct {
+			Path   string
+			Status string
+		}
+		Skipped []struct {
+			Name   string
+			Reason string
+		}
+	}
+	content, err := os.ReadFile("test-report-examples/summary.json")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(content, &report))
+	require.Empty(t, report.Error)
+	require.Len(t, report.Files, 1)
+	require.Equal(t, "test-report-examples/helloworld/suite.gen.go", report.Files[0].Path)
+	require.Equal(t, "unchanged", report.Files[0].Status)
+	require.Contains(t, report.Skipped, struct {
+		Name   string
+		Reason string
+	}{Name: "Tree", Reason: "not matched by --match"})
+}
+
+func TestRender(t *testing.T) {
+	runner, err := bash.New()
+	require.NoError(t, err)
+	defer runner.Close()
+	_, _, exitCode, err := runner.Run("go install ./...")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	stdout, _, exitCode, err := runner.Run("gotestmd render examples/HelloWorld/README.md")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.Contains(t, stdout, "package helloworld\n")
+	require.Contains(t, stdout, "r := s.Runner(\"examples/HelloWorld\")\n")
+
+	stdout, _, exitCode, err = runner.Run("gotestmd render --bash < examples/HelloWorld/README.md")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.Contains(t, stdout, "echo \"Hello world!\"\n")
+	require.NotContains(t, stdout, "Source:")
+
+	_, stderr, exitCode, err := runner.Run("printf -- '---\\nid: [\\n---\\n' | gotestmd render")
+	require.NoError(t, err)
+	require.Equal(t, gotestmd.ExitParse, exitCode)
+	require.Contains(t, stderr, "front matter")
+}
+
+func TestParseErrorsOrder(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.RemoveAll("test-parse-examples")
+	})
+	var expected []string
+	for i := 0; i < 50; i++ {
+		dir := fmt.Sprintf("test-parse-examples/Example%02d", i)
+		require.NoError(t, os.MkdirAll(dir, 0o750))
+		content := "# Run\n\n```bash\necho ok\n```\n"
+		if i%5 == 0 {
+			content = "---\nid: [broken\n---\n"
+			expected = append(expected, dir+"/README.md:1:1: invalid front matter")
+		}
+		require.NoError(t, os.WriteFile(dir+"/README.md", []byte(content), 0o600))
+	}
+	runner, err := bash.New()
+	require.NoError(t, err)
+	defer runner.Close()
+	_, _, exitCode, err := runner.Run("go install ./...")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	for i := 0; i < 3; i++ {
+		_, stderr, exitCode, err := runner.Run("gotestmd test-parse-examples/ test-parse-examples/out/")
+		require.NoError(t, err)
+		require.Equal(t, gotestmd.ExitParse, exitCode)
+		var found []string
+		for _, line := range strings.Split(stderr, "\n") {
+			if i := strings.Index(line, "test-parse-examples/"); i >= 0 && strings.Contains(line, "invalid front matter") {
+				found = append(found, line[i:i+len(expected[0])])
+			}
+		}
+		require.Equal(t, expected, found)
+	}
+}
+
+func TestCache(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.RemoveAll("test-cache-examples")
+	})
+	runner, err := bash.New()
+	require.NoError(t, err)
+	defer runner.Close()
+	_, _, exitCode, err := runner.Run("go install ./... && mkdir -p test-cache-examples && cp -r examples test-cache-examples/in")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	statuses := func() map[string]string {
+		_, _, exitCode, err := runner.Run("gotestmd test-cache-examples/in/ test-cache-examples/out/ --cache test-cache-examples/cache.json --report test-cache-examples/summary.json")
+		require.NoError(t, err)
+		require.Zero(t, exitCode)
+		var report struct {
+			Files []struct {
+				Path   string
+				Status string
+			}
+		}
+		content, err := os.ReadFile("test-cache-examples/summary.json")
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(content, &report))
+		var result = map[string]string{}
+		for _, file := range report.Files {
+			result[strings.TrimPrefix(file.Path, "test-cache-examples/out/")] = file.Status
+		}
+		return result
+	}
+
+	require.Equal(t, "created", statuses()["tree/suite.gen.go"])
+	require.FileExists(t, "test-cache-examples/cache.json")
+	for _, status := range statuses() {
+		require.Equal(t, "unchanged", status)
+	}
+
+	// The leaf is a test of the tree suite, the other suites don't depend on it
+	_, _, exitCode, err = runner.Run("echo >> test-cache-examples/in/Tree/LeafA/README.md && rm test-cache-examples/out/helloworld/suite.gen.go")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	result := statuses()
+	require.Equal(t, "updated", result["tree/suite.gen.go"])
+	require.Equal(t, "created", result["helloworld/suite.gen.go"])
+	require.Equal(t, "unchanged", result["tree/subtree/suite.gen.go"])
+}
+
+func TestChangedFiles(t *testing.T) {
+	runner, err := bash.New()
+	require.NoError(t, err)
+	defer runner.Close()
+	_, _, exitCode, err := runner.Run("go install ./...")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	suites := func(flags string) []string {
+		stdout, _, exitCode, err := runner.Run("gotestmd list examples/ test-changed-examples/ --json " + flags)
+		require.NoError(t, err)
+		require.Zero(t, exitCode)
+		var infos []struct {
+			Name string
+		}
+		require.NoError(t, json.Unmarshal([]byte(stdout), &infos))
+		var result []string
+		for _, info := range infos {
+			result = append(result, info.Name)
+		}
+		return result
+	}
+
+	// The consumers require the producer, a file of the dir of the example belongs to it
+	require.ElementsMatch(t, []string{"producer", "producer-consumer2", "producer-consumer3", "producer-consumer4"}, suites("--changed-files examples/Producer/kustomization.yaml"))
+	// A test belongs to the suite including it
+	require.ElementsMatch(t, []string{"tree-subtree", "helloworld"}, suites("--changed-files examples/Tree/SubTree/LeafB/README.md --changed-files examples/HelloWorld/README.md"))
+
+	_, _, exitCode, err = runner.Run("gotestmd list examples/ test-changed-examples/ --changed-files README.md")
+	require.NoError(t, err)
+	require.Equal(t, gotestmd.ExitNoMatch, exitCode)
+}
+
+func TestTemplateErrors(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.RemoveAll("test-templates-examples")
+		_ = os.RemoveAll("test-templates")
+	})
+	require.NoError(t, os.MkdirAll("test-templates", 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join("test-templates", "suite.tmpl"), []byte("{{ .NoSuchField }}"), 0o600))
+
+	runner, err := bash.New()
+	require.NoError(t, err)
+	defer runner.Close()
+	_, _, exitCode, err := runner.Run("go install ./...")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+
+	_, stderr, exitCode, err := runner.Run("gotestmd examples/ test-templates-examples/ --templates test-templates/")
+	require.NoError(t, err)
+	require.Equal(t, gotestmd.ExitError, exitCode)
+	require.Contains(t, stderr, "cannot execute template suite")
+	require.Contains(t, stderr, "NoSuchField")
+	_, err = os.Stat(filepath.Join("test-templates-examples", "helloworld", "suite.gen.go"))
+	require.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package backend provides the registry of output formats added to the built-in ones. A module embedding gotestmd
+// registers its backends in init functions, and they are selected with --format like the built-in formats
+package backend
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/networkservicemesh/gotestmd/internal/config"
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+)
+
+// File is a generated file
+type File struct {
+	// Path is the location of the file in the output dir
+	Path    string
+	Content []byte
+	// Executable is set for scripts
+	Executable bool
+}
+
+// Backend generates the files of an output format
+type Backend interface {
+	// Name is the name of the format, e.g. bats
+	Name() string
+	// Generate returns the files generated for the linked examples into the output dir
+	Generate(outputDir string, examples []*linker.LinkedExample) ([]*File, error)
+}
+
+var (
+	mu       sync.RWMutex
+	backends = map[string]Backend{}
+)
+
+// Register makes the backend available by its name. Panics if the name is empty, taken by a built-in format or
+// registered twice
+func Register(b Backend) {
+	mu.Lock()
+	defer mu.Unlock()
+	name := b.Name()
+	switch name {
+	case "", config.FormatTestify, config.FormatTesting, config.FormatGinkgo, config.FormatJSON:
+		panic("backend: invalid name " + name)
+	}
+	if _, ok := backends[name]; ok {
+		panic("backend: Register called twice for " + name)
+	}
+	backends[name] = b
+}
+
+// Get returns the backend registered with the name or nil
+func Get(name string) Backend {
+	mu.RLock()
+	defer mu.RUnlock()
+	return backends[name]
+}
+
+// Names returns the sorted names of the registered backends
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	var result = make([]string, 0, len(backends))
+	for name := range backends {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/gotestmd/pkg/backend"
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+)
+
+type namesBackend string
+
+func (b namesBackend) Name() string {
+	return string(b)
+}
+
+func (b namesBackend) Generate(outputDir string, examples []*linker.LinkedExample) ([]*backend.File, error) {
+	var content []byte
+	for _, e := range examples {
+		content = append(content, e.Name+"\n"...)
+	}
+	return []*backend.File{{Path: outputDir + "/names.txt", Content: content}}, nil
+}
+
+func TestRegister(t *testing.T) {
+	backend.Register(namesBackend("names"))
+	require.Equal(t, namesBackend("names"), backend.Get("names"))
+	require.Contains(t, backend.Names(), "names")
+	require.Nil(t, backend.Get("unknown"))
+
+	require.PanicsWithValue(t, "backend: Register called twice for names", func() {
+		backend.Register(namesBackend("names"))
+	})
+	require.PanicsWithValue(t, "backend: invalid name testify", func() {
+		backend.Register(namesBackend("testify"))
+	})
+}
@@ -19,35 +19,62 @@
 package bash
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"os"
 	"os/exec"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
 )
 
 const (
 	initialBufferSize    = 1 << 10
+	minOutputLimit       = 1 << 10
 	finishMessage        = "gotestmd/pkg/suites/shell/Bash.const.finish"
-	cmdPrintStatusCode   = `echo -e \\n$?`
+	statusMessage        = "gotestmd/pkg/suites/shell/Bash.const.status"
+	cmdPrintStatusCode   = `echo ` + statusMessage + `$?`
 	cmdPrintStdoutFinish = `echo ` + finishMessage
 	cmdPrintStderrFinish = cmdPrintStdoutFinish + ` >&2`
+	// exitTimeout is the time stderr of the exited process is waited for to report why it exited
+	exitTimeout = time.Second
 )
 
 // Bash is api for bash process
 type Bash struct {
+	// mu serializes the commands run by several goroutines
+	mu        sync.Mutex
 	dir       string
 	env       []string
 	resources []io.Closer
 	ctx       context.Context
 	cancel    context.CancelFunc
+	// killed is set if the process was killed because a command didn't finish in time
+	killed bool
 
 	cmd *exec.Cmd
 
 	stdin    io.Writer
 	stdoutCh chan string
 	stderrCh chan string
+	// stdoutClosed and stderrClosed are closed when the pipes reach EOF, e.g. if ssh can't connect to the host
+	stdoutClosed chan struct{}
+	stderrClosed chan struct{}
+	// exitMessage is the unfinished stderr left when the pipe is closed. It's set before stderrClosed is closed
+	exitMessage string
+	// stdoutLines and stderrLines receive the lines of the output while the commands run
+	stdoutLines func(line string)
+	stderrLines func(line string)
+	// remote is the host or the container running the commands. The commands run locally if it's nil
+	remote remote
+	// shell is the program with the arguments reading the commands, bash by default
+	shell string
+	// outputLimit is the max size of stdout and stderr of a command kept in memory. Zero doesn't limit the output
+	outputLimit int
 }
 
 // New creates a new bash runner and initializes it
@@ -61,6 +88,16 @@ func New(options ...Option) (*Bash, error) {
 	if err != nil {
 		return nil, err
 	}
+	if b.remote != nil {
+		_, stderr, exitCode, err := b.Run(setup(b.remote.dir(), b.env))
+		if err == nil && exitCode != 0 {
+			err = errors.Errorf("can't set up the shell on %v: %v", b.remote, stderr)
+		}
+		if err != nil {
+			b.Close()
+			return nil, err
+		}
+	}
 
 	return b, nil
 }
@@ -68,9 +105,9 @@ func New(options ...Option) (*Bash, error) {
 // Close closes current bash process and all the resources used by it
 func (b *Bash) Close() {
 	b.cancel()
-	_, err := b.stdin.Write([]byte("exit 0\n"))
-	if err != nil {
-		panic(err)
+	if !b.killed {
+		// the write fails if the process has already exited
+		_, _ = b.stdin.Write([]byte("exit 0\n"))
 	}
 	_ = b.cmd.Wait()
 	for _, r := range b.resources {
@@ -92,18 +129,35 @@ func (b *Bash) Init() error {
 	b.ctx, b.cancel = context.WithCancel(context.Background())
 	b.stdoutCh = make(chan string)
 	b.stderrCh = make(chan string)
-	p, err := exec.LookPath("bash")
+	b.stdoutClosed = make(chan struct{})
+	b.stderrClosed = make(chan struct{})
+	shell := strings.Fields(b.shell)
+	if len(shell) == 0 {
+		shell = []string{"bash"}
+	}
+	name, args, dir := shell[0], shell[1:], b.dir
+	if b.remote != nil {
+		name, args = b.remote.command(shell)
+		dir = ""
+	}
+	p, err := exec.LookPath(name)
 	if err != nil {
 		return err
 	}
-	if len(b.env) == 0 {
+	if len(b.env) == 0 && b.remote == nil {
 		b.env = os.Environ()
 	}
+	env := b.env
+	if b.remote != nil {
+		env = os.Environ()
+	}
 	b.cmd = &exec.Cmd{
-		Dir:  b.dir,
-		Env:  b.env,
+		Dir:  dir,
+		Env:  env,
 		Path: p,
+		Args: append([]string{p}, args...),
 	}
+	setProcessGroup(b.cmd)
 
 	stderr, err := b.cmd.StderrPipe()
 	if err != nil {
@@ -129,32 +183,75 @@ func (b *Bash) Init() error {
 		return err
 	}
 
-	go b.extractMessagesFromPipe(stdout, b.stdoutCh)
-	go b.extractMessagesFromPipe(stderr, b.stderrCh)
+	go func() {
+		b.extractMessagesFromPipe(stdout, b.stdoutCh, b.stdoutLines)
+		close(b.stdoutClosed)
+	}()
+	go func() {
+		b.exitMessage = b.extractMessagesFromPipe(stderr, b.stderrCh, b.stderrLines)
+		close(b.stderrClosed)
+	}()
 
 	return nil
 }
 
-func (b *Bash) extractMessagesFromPipe(pipe io.Reader, ch chan string) {
+// extractMessagesFromPipe sends the outputs of the commands to ch until the pipe is closed and returns the output left
+// after the last command, e.g. the error of ssh failed to connect
+func (b *Bash) extractMessagesFromPipe(pipe io.Reader, ch chan string, lines func(line string)) string {
 	var buffer = make([]byte, initialBufferSize)
 	cur := 0
+	// streamed is the part of the buffer already passed to lines
+	streamed := 0
+	// truncated is the number of the bytes dropped after the head of the output exceeding the output limit
+	truncated := 0
+	head := b.outputLimit / 2
 	for b.ctx.Err() == nil {
 		n, err := pipe.Read(buffer[cur:])
+		cur += n
 		if err != nil {
-			return
+			return strings.TrimSpace(string(buffer[:cur]))
 		}
-		cur += n
-		r := strings.TrimSpace(string(buffer[:cur]))
-		if strings.HasSuffix(r, finishMessage) {
+		finished := bytes.HasSuffix(bytes.TrimSpace(buffer[:cur]), []byte(finishMessage))
+		if lines != nil && (truncated == 0 || finished) {
+			start, end := streamed, cur
+			if truncated > 0 && start == head {
+				// the first line of the tail is cut
+				start += bytes.IndexByte(buffer[start:cur], '\n') + 1
+			}
+			if !finished {
+				end = start + bytes.LastIndexByte(buffer[start:cur], '\n') + 1
+			}
+			streamLines(string(buffer[start:end]), lines)
+			streamed = end
+		}
+		// the output is truncated when the buffer is full instead of every read, so the copying is amortized
+		if b.outputLimit > 0 && cur > b.outputLimit && (finished || cur == len(buffer)) {
+			if truncated == 0 && lines != nil && !finished {
+				lines("... the output exceeds " + strconv.Itoa(b.outputLimit) + " bytes, its tail is logged when the command finishes")
+			}
+			drop := cur - b.outputLimit
+			copy(buffer[head:], buffer[head+drop:cur])
+			cur -= drop
+			truncated += drop
+			if streamed -= drop; streamed < head {
+				streamed = head
+			}
+		}
+		if finished {
+			r := string(buffer[:cur])
+			if truncated > 0 {
+				r = string(buffer[:head]) + "\n... " + strconv.Itoa(truncated) + " bytes truncated ...\n" + string(buffer[head:cur])
+			}
+			r = strings.TrimSpace(r)
 			if len(r) >= len(finishMessage) {
 				r = strings.TrimSpace(r[:len(r)-len(finishMessage)])
 			}
 			select {
 			case ch <- r:
 			case <-b.ctx.Done():
-				return
+				return ""
 			}
-			cur = 0
+			cur, streamed, truncated = 0, 0, 0
 			continue
 		}
 		if cur == len(buffer) {
@@ -163,40 +260,60 @@ func (b *Bash) extractMessagesFromPipe(pipe io.Reader, ch chan string) {
 			copy(buffer, oldBuffer)
 		}
 	}
+	return ""
 }
 
 // Run runs the command
 func (b *Bash) Run(cmd string) (stdout, stderr string, exitCode int, err error) {
+	return b.RunContext(context.Background(), cmd)
+}
+
+// RunContext runs the command. If the context is done before the command finishes, the bash process is killed
+// together with the commands it started and the context error is returned. The runner can't be used after that
+func (b *Bash) RunContext(ctx context.Context, cmd string) (stdout, stderr string, exitCode int, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	if b.ctx.Err() != nil {
 		return "", "", 0, b.ctx.Err()
 	}
+	if ctx.Err() != nil {
+		return "", "", 0, ctx.Err()
+	}
 
 	_, err = b.stdin.Write([]byte(cmd + "\n" + cmdPrintStatusCode + "\n" + cmdPrintStdoutFinish + "\n" + cmdPrintStderrFinish + "\n"))
 	if err != nil {
-		return "", "", 0, err
+		// stdin is closed if the process has exited
+		return "", "", 0, b.exitError()
 	}
 
 	select {
 	case stdout = <-b.stdoutCh:
+	case <-b.stdoutClosed:
+		return "", "", 0, b.exitError()
 	case <-b.ctx.Done():
 		return "", "", 0, nil
+	case <-ctx.Done():
+		b.kill()
+		return "", "", 0, ctx.Err()
 	}
 
 	select {
 	case stderr = <-b.stderrCh:
+	case <-b.stderrClosed:
+		return "", "", 0, b.exitError()
 	case <-b.ctx.Done():
 		return "", "", 0, nil
+	case <-ctx.Done():
+		b.kill()
+		return "", "", 0, ctx.Err()
 	}
 
-	lastLineBreak := strings.LastIndex(stdout, "\n")
-	var exitCodeString string
-	if lastLineBreak == -1 {
-		exitCodeString = stdout
-		stdout = ""
-	} else {
-		exitCodeString = stdout[(lastLineBreak + 1):]
-		stdout = strings.TrimSpace(stdout[:lastLineBreak])
+	status := strings.LastIndex(stdout, statusMessage)
+	if status == -1 {
+		return "", "", 0, errors.Errorf("no exit code in the output: %v", stdout)
 	}
+	exitCodeString := stdout[status+len(statusMessage):]
+	stdout = strings.TrimSpace(stdout[:status])
 	var exitCode64 int64
 	exitCode64, err = strconv.ParseInt(exitCodeString, 0, 9)
 	if err != nil {
@@ -206,3 +323,38 @@ func (b *Bash) Run(cmd string) (stdout, stderr string, exitCode int, err error)
 
 	return stdout, stderr, exitCode, nil
 }
+
+// exitError returns the error of the process exited before the command finished with the stderr it left
+func (b *Bash) exitError() error {
+	select {
+	case <-b.stderrClosed:
+		if b.exitMessage != "" {
+			return errors.Errorf("shell exited: %v", b.exitMessage)
+		}
+	case <-time.After(exitTimeout):
+	}
+	return errors.New("shell exited")
+}
+
+// streamLines passes the non-empty lines of the output to the handler without the messages of the runner
+func streamLines(output string, handler func(line string)) {
+	for _, line := range strings.Split(output, "\n") {
+		for _, message := range []string{statusMessage, finishMessage} {
+			if i := strings.Index(line, message); i >= 0 {
+				line = line[:i]
+			}
+		}
+		if line = strings.TrimRight(line, "\r"); line != "" {
+			handler(line)
+		}
+	}
+}
+
+func (b *Bash) kill() {
+	b.killed = true
+	b.cancel()
+	killProcessGroup(b.cmd)
+	if b.remote != nil {
+		b.remote.kill()
+	}
+}
@@ -19,10 +19,17 @@
 package bash_test
 
 import (
+	"context"
 	"math/rand"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 
@@ -155,6 +162,169 @@ func TestBashExitCode(t *testing.T) {
 	require.Empty(t, stderr)
 }
 
+func TestBashRunContextTimeout(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	runner, err := bash.New()
+	require.NoError(t, err)
+	defer runner.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, _, err = runner.RunContext(ctx, "sleep 60")
+	require.Equal(t, context.DeadlineExceeded, err)
+	require.True(t, time.Since(start) < 10*time.Second)
+
+	_, _, _, err = runner.Run("echo hi")
+	require.Error(t, err)
+}
+
+func TestBashOutputLines(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	dir := t.TempDir()
+	stdoutLines, stderrLines := make(chan string, 10), make(chan string, 10)
+	runner, err := bash.New(bash.WithDir(dir),
+		bash.WithStdoutLines(func(line string) { stdoutLines <- line }),
+		bash.WithStderrLines(func(line string) { stderrLines <- line }),
+	)
+	require.NoError(t, err)
+	defer runner.Close()
+
+	type result struct {
+		stdout, stderr string
+		err            error
+	}
+	done := make(chan result, 1)
+	go func() {
+		stdout, stderr, _, err := runner.Run(`echo pulling; while [ ! -f pulled ]; do sleep 0.05; done; echo -n pulled; echo failed >&2`)
+		done <- result{stdout: stdout, stderr: stderr, err: err}
+	}()
+
+	require.Equal(t, "pulling", <-stdoutLines)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pulled"), nil, 0o600))
+	r := <-done
+	require.NoError(t, r.err)
+	require.Equal(t, "pulling\npulled", r.stdout)
+	require.Equal(t, "failed", r.stderr)
+	require.Equal(t, "pulled", <-stdoutLines)
+	require.Equal(t, "failed", <-stderrLines)
+	require.Empty(t, stdoutLines)
+	require.Empty(t, stderrLines)
+}
+
+func TestBashSSH(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	// ssh is replaced by a script running bash locally and recording its arguments
+	bin, remoteDir := t.TempDir(), t.TempDir()
+	argsFile := filepath.Join(bin, "args")
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "ssh"), []byte("#!/bin/bash\necho \"$@\" >> "+argsFile+"\nexec bash -c \"${@: -1}\"\n"), 0o700)) // #nosec
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	runner, err := bash.New(bash.WithDir("examples/basic"), bash.WithEnv([]string{"GREETING=it's remote"}), bash.WithSSH(&bash.SSH{
+		Host:    "tester@node-1",
+		Port:    2222,
+		KeyFile: "id_ed25519",
+		Dir:     remoteDir,
+	}))
+	require.NoError(t, err)
+	defer runner.Close()
+
+	stdout, _, exitCode, err := runner.Run("pwd; echo $GREETING")
+	require.NoError(t, err)
+	require.Zero(t, exitCode)
+	require.Equal(t, remoteDir+"\nit's remote", stdout)
+	require.Equal(t, "examples/basic", runner.Dir())
+
+	pidFile := filepath.Join(remoteDir, "pid")
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, _, _, err = runner.RunContext(ctx, "sleep 60 & echo $! > "+pidFile+"; wait")
+	require.Equal(t, context.DeadlineExceeded, err)
+
+	pid, err := os.ReadFile(filepath.Clean(pidFile))
+	require.NoError(t, err)
+	stat, err := os.ReadFile(filepath.Join("/proc", strings.TrimSpace(string(pid)), "stat"))
+	require.True(t, err != nil || strings.Contains(string(stat), ") Z "), "the remote command is still running")
+
+	args, err := os.ReadFile(filepath.Clean(argsFile))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(args)), "\n")
+	require.Len(t, lines, 2)
+	const prefix = "-T -o BatchMode=yes -o ConnectTimeout=10 -p 2222 -i id_ed25519 tester@node-1 "
+	pgidFile := regexp.MustCompile(`/tmp/gotestmd-[0-9a-f]{16}\.pgid`).FindString(lines[0])
+	require.NotEmpty(t, pgidFile)
+	require.Equal(t, prefix+"setsid -w sh -c 'echo $$ > "+pgidFile+"; \"$@\"; rm -f "+pgidFile+"' sh 'bash'", lines[0])
+	require.Equal(t, prefix+"kill -KILL -- -$(cat "+pgidFile+"); rm -f "+pgidFile, lines[1])
+	require.NoFileExists(t, pgidFile)
+
+	_, err = bash.New(bash.WithSSH(&bash.SSH{Host: "node-1", Dir: filepath.Join(remoteDir, "missing")}))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't set up the shell on node-1")
+}
+
+func TestBashUnreachableHost(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+	if _, err := exec.LookPath("ssh"); err != nil {
+		t.Skip("ssh is not installed")
+	}
+
+	start := time.Now()
+	_, err := bash.New(bash.WithSSH(&bash.SSH{Host: "nobody@127.0.0.1", Port: 1}))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "shell exited: ")
+	require.Less(t, int64(time.Since(start)), int64(30*time.Second))
+}
+
+func TestBashContainerExited(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	// docker is replaced by a script failing to start the container
+	bin := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "docker"), []byte("#!/bin/bash\necho \"Unable to find image 'missing'\" >&2\nexit 125\n"), 0o700)) // #nosec
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	_, err := bash.New(bash.WithContainer(&bash.Container{Image: "missing"}))
+	require.EqualError(t, err, "shell exited: Unable to find image 'missing'")
+}
+
+func TestBashContainer(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	// docker is replaced by a script running bash locally and recording its arguments
+	bin, dir := t.TempDir(), t.TempDir()
+	argsFile := filepath.Join(bin, "args")
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "docker"), []byte("#!/bin/bash\necho \"$@\" >> "+argsFile+"\n[ $1 = run ] && exec bash\n"), 0o700)) // #nosec
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	runner, err := bash.New(bash.WithEnv([]string{"GREETING=hello"}), bash.WithContainer(&bash.Container{
+		Image:  "golang:1.20",
+		Mounts: []string{dir},
+		Dir:    dir,
+	}))
+	require.NoError(t, err)
+	defer runner.Close()
+
+	stdout, _, _, err := runner.Run("pwd; echo $GREETING")
+	require.NoError(t, err)
+	require.Equal(t, dir+"\nhello", stdout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, _, _, err = runner.RunContext(ctx, "sleep 60")
+	require.Equal(t, context.DeadlineExceeded, err)
+
+	args, err := os.ReadFile(filepath.Clean(argsFile))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(args)), "\n")
+	require.Len(t, lines, 2)
+	require.Regexp(t, "^run -i --rm --name (gotestmd-[0-9a-f]{16}) --network host -v "+dir+":"+dir+" --entrypoint bash golang:1.20$", lines[0])
+	require.Equal(t, "rm -f "+strings.Fields(lines[0])[4], lines[1])
+}
+
 func randomString(n int) string {
 	var letter = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
 
@@ -165,3 +335,67 @@ func randomString(n int) string {
 	}
 	return string(b)
 }
+
+func TestBashConcurrentRun(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	runner, err := bash.New(bash.WithDir(t.TempDir()))
+	require.NoError(t, err)
+	defer runner.Close()
+
+	errs := make(chan error, 10)
+	for i := 0; i < cap(errs); i++ {
+		cmd := "echo " + strings.Repeat("x", i+1)
+		go func() {
+			stdout, _, _, err := runner.Run(cmd)
+			if err == nil && cmd != "echo "+stdout {
+				err = errors.Errorf("unexpected output of %q: %q", cmd, stdout)
+			}
+			errs <- err
+		}()
+	}
+	for i := 0; i < cap(errs); i++ {
+		require.NoError(t, <-errs)
+	}
+}
+
+func TestBashShell(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	runner, err := bash.New(bash.WithDir(t.TempDir()), bash.WithShell("sh"), bash.WithEnv([]string{"GREETING=hello"}))
+	require.NoError(t, err)
+	defer runner.Close()
+
+	stdout, _, exitCode, err := runner.Run("echo $(basename $0) $GREETING; (exit 3)")
+	require.NoError(t, err)
+	require.Equal(t, "sh hello", stdout)
+	require.Equal(t, 3, exitCode)
+}
+
+func TestBashOutputLimit(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	var lines []string
+	runner, err := bash.New(bash.WithDir(t.TempDir()), bash.WithOutputLimit(1<<10),
+		bash.WithStdoutLines(func(line string) { lines = append(lines, line) }),
+	)
+	require.NoError(t, err)
+	defer runner.Close()
+
+	stdout, _, exitCode, err := runner.Run("seq 1 100000")
+	require.NoError(t, err)
+	require.Equal(t, 0, exitCode)
+	require.True(t, strings.HasPrefix(stdout, "1\n2\n3\n"))
+	require.True(t, strings.HasSuffix(stdout, "\n99999\n100000"))
+	require.Contains(t, stdout, " bytes truncated ...\n")
+	require.Less(t, len(stdout), 1<<11)
+
+	require.Equal(t, "1", lines[0])
+	require.Contains(t, lines, "... the output exceeds 1024 bytes, its tail is logged when the command finishes")
+	require.Equal(t, "100000", lines[len(lines)-1])
+	require.Less(t, len(lines), 100000)
+
+	stdout, _, _, err = runner.Run("echo ok")
+	require.NoError(t, err)
+	require.Equal(t, "ok", stdout)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bash
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os/exec"
+)
+
+// Container is the container running the commands instead of the local bash
+type Container struct {
+	// Runtime is docker, podman or another CLI compatible with docker run. It's docker if it's empty
+	Runtime string
+	// Image is the image of the container, e.g. golang:1.20
+	Image string
+	// Mounts are the host dirs mounted into the container at the same paths
+	Mounts []string
+	// Dir is the dir in the container the commands start in. It's the workdir of the image if it's empty
+	Dir  string
+	name string
+}
+
+func (c *Container) command(shell []string) (name string, args []string) {
+	c.name = "gotestmd-" + randomHex()
+	args = []string{"run", "-i", "--rm", "--name", c.name, "--network", "host"}
+	for _, mount := range c.Mounts {
+		args = append(args, "-v", mount+":"+mount)
+	}
+	args = append(args, "--entrypoint", shell[0], c.Image)
+	return c.runtime(), append(args, shell[1:]...)
+}
+
+func (c *Container) dir() string {
+	return c.Dir
+}
+
+// kill removes the container, because killing the runtime CLI doesn't stop it
+func (c *Container) kill() {
+	// #nosec
+	_ = exec.Command(c.runtime(), "rm", "-f", c.name).Run()
+}
+
+func (c *Container) runtime() string {
+	if c.Runtime == "" {
+		return "docker"
+	}
+	return c.Runtime
+}
+
+func (c *Container) String() string {
+	return c.Image
+}
+
+func randomHex() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
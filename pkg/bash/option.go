@@ -32,3 +32,53 @@ func WithEnv(env []string) Option {
 		bash.env = env
 	}
 }
+
+// WithStdoutLines sets the handler receiving the lines of stdout while the commands run
+func WithStdoutLines(handler func(line string)) Option {
+	return func(bash *Bash) {
+		bash.stdoutLines = handler
+	}
+}
+
+// WithStderrLines sets the handler receiving the lines of stderr while the commands run
+func WithStderrLines(handler func(line string)) Option {
+	return func(bash *Bash) {
+		bash.stderrLines = handler
+	}
+}
+
+// WithShell sets the shell reading the commands instead of bash, e.g. sh, zsh or "busybox sh". The shell should
+// support POSIX echo, $? and redirections
+func WithShell(shell string) Option {
+	return func(bash *Bash) {
+		bash.shell = shell
+	}
+}
+
+// WithOutputLimit limits the size of stdout and stderr of a command kept in memory. The output exceeding the limit keeps
+// its head and tail with the number of the truncated bytes in the middle, and the lines of its middle aren't passed to
+// the handlers of WithStdoutLines and WithStderrLines. Limits below 1 KiB are raised to 1 KiB, zero doesn't limit the output
+func WithOutputLimit(limit int) Option {
+	return func(bash *Bash) {
+		if limit > 0 && limit < minOutputLimit {
+			limit = minOutputLimit
+		}
+		bash.outputLimit = limit
+	}
+}
+
+// WithSSH runs the commands on the remote host over SSH. The env variables set by WithEnv are exported on the remote
+// host, while ssh itself gets the env of the current process, e.g. SSH_AUTH_SOCK
+func WithSSH(ssh *SSH) Option {
+	return func(bash *Bash) {
+		bash.remote = ssh
+	}
+}
+
+// WithContainer runs the commands in a container started by docker or podman. The env variables set by WithEnv are
+// exported in the container
+func WithContainer(container *Container) Option {
+	return func(bash *Bash) {
+		bash.remote = container
+	}
+}
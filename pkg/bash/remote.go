@@ -0,0 +1,52 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bash
+
+import (
+	"fmt"
+	"strings"
+)
+
+// remote starts the shell on a remote host or in a container instead of the local bash
+type remote interface {
+	fmt.Stringer
+	// command returns the program starting the shell reading the commands from stdin and its arguments
+	command(shell []string) (name string, args []string)
+	// dir returns the dir the commands start in or an empty string for the default one
+	dir() string
+	// kill stops the commands left after the program is killed
+	kill()
+}
+
+// setup returns the command changing the dir and exporting the env variables in KEY=VALUE format
+func setup(dir string, env []string) string {
+	commands := []string{"true"}
+	if dir != "" {
+		commands = append(commands, "cd "+Quote(dir))
+	}
+	for _, kv := range env {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			commands = append(commands, "export "+key+"="+Quote(value))
+		}
+	}
+	return strings.Join(commands, " && ")
+}
+
+// Quote returns s quoted for bash, so it's passed to the commands as is
+func Quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bash
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// connectTimeout is the timeout in seconds of the connection to the host, so an unreachable host fails the shell
+const connectTimeout = 10
+
+// SSH is the remote host running the commands instead of the local bash
+type SSH struct {
+	// Host is [USER@]HOST
+	Host string
+	// Port is the SSH port, 22 if it's zero
+	Port int
+	// KeyFile is the private key of the user. The SSH config and agent are used if it's empty
+	KeyFile string
+	// Dir is the dir on the remote host the commands start in. It's the home dir of the user if it's empty
+	Dir  string
+	name string
+}
+
+// command runs the shell in its own process group on the remote host and records the group id, so kill can stop it:
+// without a pty ssh doesn't send SIGHUP to the remote commands when the connection is closed
+func (s *SSH) command(shell []string) (name string, args []string) {
+	s.name = "gotestmd-" + randomHex()
+	quoted := make([]string, len(shell))
+	for i, arg := range shell {
+		quoted[i] = Quote(arg)
+	}
+	script := "echo $$ > " + s.pgidFile() + "; \"$@\"; rm -f " + s.pgidFile()
+	return "ssh", append(s.args(), "setsid -w sh -c "+Quote(script)+" sh "+strings.Join(quoted, " "))
+}
+
+func (s *SSH) args() []string {
+	args := []string{"-T", "-o", "BatchMode=yes", "-o", "ConnectTimeout=" + strconv.Itoa(connectTimeout)}
+	if s.Port != 0 {
+		args = append(args, "-p", strconv.Itoa(s.Port))
+	}
+	if s.KeyFile != "" {
+		args = append(args, "-i", s.KeyFile)
+	}
+	return append(args, s.Host)
+}
+
+func (s *SSH) pgidFile() string {
+	return "/tmp/" + s.name + ".pgid"
+}
+
+func (s *SSH) dir() string {
+	return s.Dir
+}
+
+// kill kills the process group of the remote shell over another connection
+func (s *SSH) kill() {
+	// #nosec
+	_ = exec.Command("ssh", append(s.args(), "kill -KILL -- -$(cat "+s.pgidFile()+"); rm -f "+s.pgidFile())...).Run()
+}
+
+func (s *SSH) String() string {
+	return s.Host
+}
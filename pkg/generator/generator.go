@@ -0,0 +1,142 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package generator generates Go suites of gotestmd markdown documents, so the generation can be embedded into other
+// tools and tests without running the gotestmd binary
+package generator
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/gotestmd/internal/config"
+	"github.com/networkservicemesh/gotestmd/internal/generator"
+	"github.com/networkservicemesh/gotestmd/pkg/backend"
+	"github.com/networkservicemesh/gotestmd/pkg/rewrite"
+)
+
+// File is a generated file
+type File = backend.File
+
+// Generate parses the examples of inputDir, links them and returns the Go suites generated for outputDir with the
+// options. The files aren't written, so the caller decides how to save or compare them
+func Generate(inputDir, outputDir string, opts ...Option) ([]*File, error) {
+	o := newOptions(inputDir, outputDir, opts)
+	c := o.config
+	b := backend.Get(c.Format)
+	switch c.Format {
+	case config.FormatTestify, config.FormatTesting, config.FormatGinkgo:
+	default:
+		if b == nil {
+			formats := append([]string{config.FormatTestify, config.FormatTesting, config.FormatGinkgo}, backend.Names()...)
+			return nil, errors.Errorf("unknown format %v, expected one of: %v", c.Format, strings.Join(formats, ", "))
+		}
+	}
+	if err := c.Naming.Validate(); err != nil {
+		return nil, err
+	}
+	if o.baseSuite != "" {
+		var err error
+		if c.BasePkg, c.BaseType, err = config.ParseBaseSuite(o.baseSuite); err != nil {
+			return nil, err
+		}
+		c.PlainBase = false
+	}
+	for _, tag := range c.BuildTags {
+		if _, err := config.ParseBuildTag(tag); err != nil {
+			return nil, err
+		}
+	}
+	var matchRegex *regexp.Regexp
+	if c.Match != "" {
+		var err error
+		if matchRegex, err = regexp.Compile(c.Match); err != nil {
+			return nil, errors.Wrap(err, "invalid match")
+		}
+	}
+	var generatorOptions []generator.Option
+	if o.fsys != nil {
+		// the files of fsys aren't on the disk, so their paths are kept as passed
+		generatorOptions = append(generatorOptions, generator.WithRoot("."))
+	}
+	if o.templatesDir != "" {
+		templates, err := generator.LoadTemplates(o.templatesDir)
+		if err != nil {
+			return nil, err
+		}
+		generatorOptions = append(generatorOptions, generator.WithTemplates(templates))
+	}
+
+	examples, _, err := o.load()
+	if err != nil {
+		return nil, err
+	}
+	rewrite.Examples(examples, o.rewriters...)
+	if b != nil {
+		return b.Generate(outputDir, examples)
+	}
+	suites, err := generator.New(c, generatorOptions...).Generate(examples...)
+	if err != nil {
+		return nil, err
+	}
+	if err = generator.CheckNames(suites); err != nil {
+		return nil, err
+	}
+	if matchRegex != nil {
+		if suites = generator.Match(suites, matchRegex); len(suites) == 0 {
+			return nil, errors.Errorf("no matches found for pattern: %v", c.Match)
+		}
+	}
+
+	var result []*File
+	for _, suite := range suites {
+		var source strings.Builder
+		if err := suite.WriteFormat(&source, c.Format); err != nil {
+			return nil, errors.Wrapf(err, "suite %v", suite.Name())
+		}
+		file, err := goFile(suite.Location, source.String())
+		if err != nil {
+			return nil, errors.Wrapf(err, "suite %v", suite.Name())
+		}
+		result = append(result, file)
+	}
+	return result, nil
+}
+
+// GenerateMap generates the files like Generate and returns their contents by their cleaned paths. Combined with
+// WithFS and WithImportPrefix it doesn't touch the disk, so generated files can be compared without temp dirs
+func GenerateMap(inputDir, outputDir string, opts ...Option) (map[string][]byte, error) {
+	files, err := Generate(inputDir, outputDir, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var result = make(map[string][]byte, len(files))
+	for _, f := range files {
+		result[filepath.Clean(f.Path)] = f.Content
+	}
+	return result, nil
+}
+
+func goFile(location, source string) (*File, error) {
+	formatted, err := generator.Format(location, source)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot format")
+	}
+	return &File{Path: location, Content: []byte(formatted)}, nil
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/gotestmd/pkg/backend"
+	"github.com/networkservicemesh/gotestmd/pkg/generator"
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+	"github.com/networkservicemesh/gotestmd/pkg/rewrite"
+)
+
+func paths(files []*generator.File) []string {
+	var result []string
+	for _, f := range files {
+		result = append(result, f.Path)
+	}
+	return result
+}
+
+func TestGenerate(t *testing.T) {
+	out := t.TempDir()
+	files, err := generator.Generate("../../examples", out,
+		generator.WithImportPrefix("example.com/suites"),
+		generator.WithMatch("^consumer2$"),
+	)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		filepath.Join(out, "producer", "suite.gen.go"),
+		filepath.Join(out, "producer", "consumer2", "suite.gen.go"),
+	}, paths(files))
+	for _, f := range files {
+		if f.Path == filepath.Join(out, "producer", "consumer2", "suite.gen.go") {
+			require.Contains(t, string(f.Content), `"example.com/suites/producer"`)
+		}
+	}
+
+	files, err = generator.Generate("../../examples", out,
+		generator.WithImportPrefix("example.com/suites"),
+		generator.WithFormat(generator.FormatTesting),
+		generator.WithNaming(generator.Naming{Case: generator.NamingCamel}),
+		generator.WithPrune("../../examples/Producer/**"),
+	)
+	require.NoError(t, err)
+	require.NotContains(t, paths(files), filepath.Join(out, "producer", "suite.gen.go"))
+	require.Contains(t, string(files[0].Content), "func Run(t *testing.T)")
+}
+
+func TestGenerateMap(t *testing.T) {
+	fsys := fstest.MapFS{
+		"README.md":       {Data: []byte("# Docs\n\n## Includes\n\n- [Basic](basic)\n")},
+		"basic/README.md": {Data: []byte("# Basic\n\n## Run\n\n```bash\necho basic\n```\n")},
+		".git/README.md":  {Data: []byte("# Ignored\n\n## Run\n\n```bash\necho ignored\n```\n")},
+	}
+	files, err := generator.GenerateMap("not-existing/docs", "not-existing/suites",
+		generator.WithFS(fsys),
+		generator.WithImportPrefix("example.com/suites"),
+	)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	content := string(files[filepath.Join("not-existing", "suites", "suite.gen.go")])
+	require.Contains(t, content, "// Source: not-existing/docs/README.md sha256:")
+	require.Contains(t, content, "echo basic")
+
+	rules, err := rewrite.ParseRules("s/^echo /sudo echo /")
+	require.NoError(t, err)
+	files, err = generator.GenerateMap("not-existing/docs", "not-existing/suites",
+		generator.WithFS(fsys),
+		generator.WithImportPrefix("example.com/suites"),
+		generator.WithRewriters(rules...),
+	)
+	require.NoError(t, err)
+	require.Contains(t, string(files[filepath.Join("not-existing", "suites", "suite.gen.go")]), "sudo echo basic")
+
+	_, err = generator.GenerateMap("not-existing/docs", "not-existing/suites",
+		generator.WithFS(fstest.MapFS{"README.md": {Data: []byte("# Run\n```bash\necho a\n")}}),
+		generator.WithImportPrefix("example.com/suites"),
+	)
+	require.EqualError(t, err, "cannot parse examples:\nnot-existing/docs/README.md:2:1: unterminated bash block in Run section")
+}
+
+type namesBackend struct{}
+
+func (namesBackend) Name() string {
+	return "generator-test-names"
+}
+
+func (namesBackend) Generate(outputDir string, examples []*linker.LinkedExample) ([]*backend.File, error) {
+	var content []byte
+	for _, e := range linker.Roots(examples) {
+		content = append(content, e.Name+"\n"...)
+	}
+	return []*backend.File{{Path: filepath.Join(outputDir, "roots.txt"), Content: content}}, nil
+}
+
+func TestGenerateBackend(t *testing.T) {
+	backend.Register(namesBackend{})
+
+	out := t.TempDir()
+	files, err := generator.Generate("../../examples", out, generator.WithFormat("generator-test-names"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, filepath.Join(out, "roots.txt"), files[0].Path)
+	require.Contains(t, string(files[0].Content), "Tree\n")
+}
+
+func TestGenerateErrors(t *testing.T) {
+	_, err := generator.Generate("../../examples", t.TempDir(), generator.WithFormat("json"))
+	require.Contains(t, err.Error(), "unknown format json, expected one of: testify, testing, ginkgo")
+
+	_, err = generator.Generate("../../examples", t.TempDir(), generator.WithImportPrefix("example.com/suites"), generator.WithMatch("Unknown"))
+	require.EqualError(t, err, "no matches found for pattern: Unknown")
+}
+
+func names(examples []*linker.LinkedExample) []string {
+	var result []string
+	for _, e := range examples {
+		result = append(result, e.Name)
+	}
+	return result
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"README.md":              {Data: []byte("# Docs\n\n## Includes\n\n- [Basic](basic)\n")},
+		"basic/README.md":        {Data: []byte("# Basic\n\n## Requires\n\n- [Remote](https://example.com/remote.md)\n\n## Run\n\n```bash\necho basic\n```\n")},
+		"basic/orphan/README.md": {Data: []byte("# Orphan\n\nNothing runs the example.\n")},
+		"broken/README.md":       {Data: []byte("# Run\n```bash\necho a\n")},
+	}
+	fetcher := linker.URLFetcherFunc(func(u string) ([]byte, error) {
+		return []byte("# Remote\n\n## Run\n\n```bash\necho remote\n```\n"), nil
+	})
+
+	_, _, err := generator.Load("docs", generator.WithFS(fsys), generator.WithURLFetcher(fetcher))
+	require.IsType(t, &generator.ParseError{}, err)
+	require.EqualError(t, err, "cannot parse examples:\ndocs/broken/README.md:2:1: unterminated bash block in Run section")
+
+	// URLs aren't fetched by default
+	_, _, err = generator.Load("docs", generator.WithFS(fsys), generator.WithExclude("docs/broken"))
+	require.IsType(t, &generator.LinkError{}, err)
+
+	examples, _, err := generator.Load("docs", generator.WithFS(fsys), generator.WithExclude("docs/broken"), generator.WithURLFetcher(fetcher))
+	require.NoError(t, err)
+	require.Contains(t, names(examples), "basic")
+
+	_, _, err = generator.Load("docs", generator.WithFS(fsys), generator.WithInclude("docs"), generator.WithURLFetcher(fetcher))
+	require.IsType(t, &generator.LinkError{}, err)
+	require.Contains(t, err.Error(), "docs/README.md depends on docs/basic excluded by --include and --exclude")
+
+	examples, _, err = generator.Load("docs", generator.WithFS(fsys), generator.WithInclude("docs"), generator.WithExcludedDeps(), generator.WithURLFetcher(fetcher))
+	require.NoError(t, err)
+	require.Contains(t, names(examples), "basic")
+
+	delete(fsys, "broken/README.md")
+	_, _, err = generator.Load("docs", generator.WithFS(fsys), generator.WithURLFetcher(fetcher), generator.WithOrphanCheck(false))
+	require.NoError(t, err)
+	_, _, err = generator.Load("docs", generator.WithFS(fsys), generator.WithURLFetcher(fetcher), generator.WithOrphanCheck(true))
+	require.IsType(t, &generator.LinkError{}, err)
+	require.EqualError(t, err, "found 1 orphaned examples")
+	_, _, err = generator.Load("docs", generator.WithFS(fsys), generator.WithInclude("docs"), generator.WithURLFetcher(fetcher), generator.WithOrphanCheck(true))
+	require.EqualError(t, err, "orphaned examples can't be checked with include and exclude patterns")
+}
+
+func TestLoadRoots(t *testing.T) {
+	dir := t.TempDir()
+	main, other := filepath.Join(dir, "main"), filepath.Join(dir, "other")
+	require.NoError(t, os.MkdirAll(main, 0o700))
+	require.NoError(t, os.MkdirAll(other, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(main, "README.md"), []byte("# Main\n\n## Requires\n\n- [Other](../other)\n\n## Run\n\n```bash\necho main\n```\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(other, "README.md"), []byte("# Other\n\n## Run\n\n```bash\necho other\n```\n"), 0o600))
+
+	_, _, err := generator.Load(main)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cannot find "+filepath.Join("..", "other")+" required by")
+
+	// Examples of the roots are named relative to the main input dir
+	examples, _, err := generator.Load(main, generator.WithRoot(other, ""))
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"", filepath.Join("..", "other")}, names(examples))
+}
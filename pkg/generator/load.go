@@ -0,0 +1,238 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/gotestmd/internal/glob"
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
+)
+
+// ParseError is the error of Load and Generate if the markdown files can't be parsed
+type ParseError struct {
+	Errs parser.ErrorList
+}
+
+func (e *ParseError) Error() string {
+	return "cannot parse examples:\n" + e.Errs.Error()
+}
+
+// LinkError is the error of Load and Generate if the examples can't be linked, e.g. because of broken links, cycles
+// or orphaned examples with WithOrphanCheck
+type LinkError struct {
+	Err error
+}
+
+func (e *LinkError) Error() string {
+	return e.Err.Error()
+}
+
+// Load parses the README.md files of inputDir, the roots and their subdirs except .git and links them. Returns the
+// linked examples and the examples removed by WithPrune and WithTags. Options of the generated files are ignored
+func Load(inputDir string, opts ...Option) ([]*linker.LinkedExample, []*linker.Pruned, error) {
+	return newOptions(inputDir, "", opts).load()
+}
+
+// Dirs returns inputDir and its subdirs except .git, the dirs README.md files of the examples are searched in
+func Dirs(inputDir string) []string {
+	dirs, _ := (*selection)(nil).walk(os.DirFS(inputDir), inputDir)
+	return dirs
+}
+
+func (o *options) load() ([]*linker.LinkedExample, []*linker.Pruned, error) {
+	c := &o.config
+	linkerOptions, err := o.linkerOptions()
+	if err != nil {
+		return nil, nil, err
+	}
+	sel, err := newSelection(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	if o.orphans && c.FailOnOrphans && len(c.Include)+len(c.Exclude) > 0 {
+		return nil, nil, errors.New("orphaned examples can't be checked with include and exclude patterns")
+	}
+	var inputDirs = []string{c.InputDir}
+	for _, root := range c.Roots {
+		inputDirs = append(inputDirs, root.InputDir)
+	}
+	l := linker.New(inputDirs[0], append(linkerOptions, linker.WithRoots(inputDirs[1:]...))...)
+
+	dirs, excluded := o.walk(sel, inputDirs)
+	examples, err := o.parseExamples(dirs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if examples, err = sel.addDependencies(o.parseDir, examples, excluded); err != nil {
+		return nil, nil, &LinkError{Err: err}
+	}
+
+	linkedExamples, err := l.Link(examples...)
+	if err != nil {
+		return nil, nil, &LinkError{Err: errors.Errorf("cannot build examples: %v", err.Error())}
+	}
+	for _, pruned := range l.Pruned() {
+		logrus.Infof("pruned %v", pruned)
+	}
+	// Parents of selected examples can be excluded, so only complete trees are checked
+	if o.orphans && len(c.Include)+len(c.Exclude) == 0 {
+		if err = reportOrphans(linkedExamples, c.FailOnOrphans); err != nil {
+			return nil, nil, err
+		}
+	}
+	return linkedExamples, l.Pruned(), nil
+}
+
+// walk returns the selected and the excluded dirs of the input dirs. The main input dir is read from the fs of WithFS
+// if it's set
+func (o *options) walk(sel *selection, inputDirs []string) (dirs, excluded []string) {
+	var visited = map[string]bool{}
+	for i, inputDir := range inputDirs {
+		fsys := os.DirFS(inputDir)
+		if i == 0 && o.fsys != nil {
+			fsys = o.fsys
+		}
+		selected, skipped := sel.walk(fsys, inputDir)
+		for _, dir := range selected {
+			if !visited[filepath.Clean(dir)] {
+				visited[filepath.Clean(dir)] = true
+				dirs = append(dirs, dir)
+			}
+		}
+		excluded = append(excluded, skipped...)
+	}
+	return dirs, excluded
+}
+
+// parseExamples parses README.md files of the dirs. Dirs without them are skipped
+func (o *options) parseExamples(dirs []string) ([]*parser.Example, error) {
+	var examples []*parser.Example
+	var parseErrs parser.ErrorList
+	for _, parsed := range o.parseDirs(dirs) {
+		if errs, ok := parsed.err.(parser.ErrorList); ok {
+			parseErrs = append(parseErrs, errs...)
+			continue
+		}
+		if parsed.err != nil && !os.IsNotExist(parsed.err) {
+			return nil, parsed.err
+		}
+		if parsed.err == nil {
+			examples = append(examples, parsed.example)
+		}
+	}
+	if len(parseErrs) > 0 {
+		parseErrs.Sort()
+		return nil, &ParseError{Errs: parseErrs}
+	}
+	return examples, nil
+}
+
+// linkerOptions returns the options of the linker of the config
+func (o *options) linkerOptions() ([]linker.Option, error) {
+	c := &o.config
+	var result []linker.Option
+	if o.urlFetcher != nil {
+		result = append(result, linker.WithURLFetcher(o.urlFetcher))
+	}
+	if c.SkipOptional {
+		result = append(result, linker.WithoutOptional())
+	}
+	if len(c.Prune) > 0 {
+		patterns, err := glob.CompileAll(c.Prune...)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, linker.WithPrune(patterns))
+	}
+	if len(c.RunTags) > 0 || len(c.SkipTags) > 0 {
+		result = append(result, linker.WithTags(c.RunTags, c.SkipTags))
+	}
+	return result, nil
+}
+
+// parsedDir is the example parsed from README.md of a dir or the error of the parser
+type parsedDir struct {
+	example *parser.Example
+	err     error
+}
+
+// parseDirs parses README.md files of the dirs by a pool of GOMAXPROCS workers. The results are in the order of the
+// dirs, so the examples and the errors don't depend on the scheduling
+func (o *options) parseDirs(dirs []string) []parsedDir {
+	var result = make([]parsedDir, len(dirs))
+	var indexes = make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < runtime.GOMAXPROCS(0) && i < len(dirs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				ex, err := o.parseDir(dirs[index])
+				result[index] = parsedDir{example: ex, err: err}
+			}
+		}()
+	}
+	for i := range dirs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return result
+}
+
+// parseDir parses README.md of the dir. The files of the input dir are read from the fs of WithFS if it's set
+func (o *options) parseDir(dir string) (*parser.Example, error) {
+	location := path.Join(dir, "README.md")
+	if o.fsys == nil {
+		return o.parser.ParseFile(location)
+	}
+	rel, err := filepath.Rel(o.config.InputDir, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return o.parser.ParseFile(location)
+	}
+	f, err := o.fsys.Open(path.Join(filepath.ToSlash(rel), "README.md"))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	return o.parser.ParseNamed(f, filepath.Join(dir, "README.md"))
+}
+
+// reportOrphans warns about the examples that don't end up in any generated suite and fails if there are such examples and fail
+// is set
+func reportOrphans(examples []*linker.LinkedExample, fail bool) error {
+	orphans := linker.Orphans(examples)
+	for _, orphan := range orphans {
+		logrus.Warnf("orphaned example %v: it has no steps and nothing running it includes or requires it", filepath.Join(orphan.Dir, "README.md"))
+	}
+	if fail && len(orphans) > 0 {
+		return &LinkError{Err: errors.Errorf("found %v orphaned examples", len(orphans))}
+	}
+	return nil
+}
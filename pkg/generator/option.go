@@ -0,0 +1,231 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"io/fs"
+
+	"github.com/networkservicemesh/gotestmd/internal/config"
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
+	"github.com/networkservicemesh/gotestmd/pkg/rewrite"
+)
+
+// Formats of generated Go suites
+const (
+	// FormatTestify generates testify suites
+	FormatTestify = config.FormatTestify
+	// FormatTesting generates standard library tests without testify
+	FormatTesting = config.FormatTesting
+	// FormatGinkgo generates ginkgo specs
+	FormatGinkgo = config.FormatGinkgo
+)
+
+// Case styles of generated test and suite names
+const (
+	// NamingTitle capitalizes the first letter of each word and lowercases the rest, e.g. leaf-A becomes Leaf_a
+	NamingTitle = config.NamingTitle
+	// NamingCamel joins the words capitalizing their first letters, e.g. leaf-A becomes LeafA
+	NamingCamel = config.NamingCamel
+	// NamingPreserve keeps the case and capitalizes only the first letter, e.g. leaf-A becomes Leaf_A
+	NamingPreserve = config.NamingPreserve
+)
+
+// Naming contains the rules of generated test and suite names
+type Naming struct {
+	// Case is the case style of the names, NamingTitle by default
+	Case string
+	// Separator replaces characters that can't be used in Go identifiers. Camel case drops them
+	Separator string
+	// Prefix and Suffix are added to the names of tests and suites
+	Prefix string
+	Suffix string
+	// DigitPrefix is added to suite types starting with a digit
+	DigitPrefix string
+}
+
+type options struct {
+	config       config.Config
+	fsys         fs.FS
+	templatesDir string
+	baseSuite    string
+	rewriters    []rewrite.Rewriter
+	urlFetcher   linker.URLFetcher
+	orphans      bool
+	parser       *parser.Parser
+}
+
+func newOptions(inputDir, outputDir string, opts []Option) *options {
+	var o = &options{config: config.FromArgs([]string{inputDir, outputDir}), parser: parser.New()}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Option is an option for Generate and Load
+type Option func(o *options)
+
+// WithFS reads the markdown files from fsys rooted at the input dir instead of the disk. Examples required by remote
+// links are still downloaded, the files of the roots are read from the disk
+func WithFS(fsys fs.FS) Option {
+	return func(o *options) {
+		o.fsys = fsys
+	}
+}
+
+// WithRoot adds an input dir linked together with the main one, so Requires and Includes links can point to its
+// examples. Its suites are generated in the output dir
+func WithRoot(inputDir, outputDir string) Option {
+	return func(o *options) {
+		o.config.Roots = append(o.config.Roots, config.Root{InputDir: inputDir, OutputDir: outputDir})
+	}
+}
+
+// WithInclude parses only the example dirs matching the globs, e.g. examples/features/**
+func WithInclude(globs ...string) Option {
+	return func(o *options) {
+		o.config.Include = append(o.config.Include, globs...)
+	}
+}
+
+// WithExclude skips the example dirs matching the globs with their subtrees
+func WithExclude(globs ...string) Option {
+	return func(o *options) {
+		o.config.Exclude = append(o.config.Exclude, globs...)
+	}
+}
+
+// WithExcludedDeps adds the excluded examples required or included by the selected ones instead of failing
+func WithExcludedDeps() Option {
+	return func(o *options) {
+		o.config.ExcludedDeps = config.ExcludedDepsInclude
+	}
+}
+
+// WithoutOptional drops optional dependencies even if they exist in the input dirs
+func WithoutOptional() Option {
+	return func(o *options) {
+		o.config.SkipOptional = true
+	}
+}
+
+// WithURLFetcher downloads the markdown files required by URL with the fetcher, e.g. linker.NewURLCache. Requires
+// links with URLs fail to link without it
+func WithURLFetcher(fetcher linker.URLFetcher) Option {
+	return func(o *options) {
+		o.urlFetcher = fetcher
+	}
+}
+
+// WithOrphanCheck warns about the examples that don't end up in any generated suite and fails with a LinkError if fail
+// is set.
+// The check is skipped with WithInclude and WithExclude, since parents of the selected examples can be excluded, and
+// Load fails if fail is set with them
+func WithOrphanCheck(fail bool) Option {
+	return func(o *options) {
+		o.orphans = true
+		o.config.FailOnOrphans = fail
+	}
+}
+
+// WithFormat sets the format of generated suites: FormatTestify, FormatTesting, FormatGinkgo or the name of a registered
+// backend. FormatTestify is used by default
+func WithFormat(format string) Option {
+	return func(o *options) {
+		o.config.Format = format
+	}
+}
+
+// WithTemplates sets the dir of NAME.tmpl files overriding the built-in templates and the data available in them as
+// {{ data "KEY" }}
+func WithTemplates(dir string, data map[string]string) Option {
+	return func(o *options) {
+		o.templatesDir = dir
+		o.config.TemplateData = data
+	}
+}
+
+// WithNaming sets the rules of generated test and suite names. Empty Separator and DigitPrefix are replaced by _
+func WithNaming(naming Naming) Option {
+	return func(o *options) {
+		var defaults = config.DefaultNaming()
+		o.config.Naming = config.Naming(naming)
+		if o.config.Naming.Case == "" {
+			o.config.Naming.Case = defaults.Case
+		}
+		if o.config.Naming.Separator == "" {
+			o.config.Naming.Separator = defaults.Separator
+		}
+		if o.config.Naming.DigitPrefix == "" {
+			o.config.Naming.DigitPrefix = defaults.DigitPrefix
+		}
+	}
+}
+
+// WithMatch keeps only the suites matching the regex by name or having tests matching it with the suites they include
+// and require. Registered backends get all examples
+func WithMatch(pattern string) Option {
+	return func(o *options) {
+		o.config.Match = pattern
+	}
+}
+
+// WithPrune removes the example dirs matching the globs with their subtrees and all examples depending on them, e.g.
+// examples/experimental/**
+func WithPrune(globs ...string) Option {
+	return func(o *options) {
+		o.config.Prune = append(o.config.Prune, globs...)
+	}
+}
+
+// WithTags keeps only the examples having any of the run tags of the front matter with the examples they include and
+// need, and removes the examples having any of the skip tags
+func WithTags(run, skip []string) Option {
+	return func(o *options) {
+		o.config.RunTags, o.config.SkipTags = run, skip
+	}
+}
+
+// WithImportPrefix sets the import path of the output dir. By default it's detected from the nearest go.mod
+func WithImportPrefix(prefix string) Option {
+	return func(o *options) {
+		o.config.ImportPrefix = prefix
+	}
+}
+
+// WithBaseSuite sets the suite type embedded by generated suites in IMPORT_PATH.TYPE format
+func WithBaseSuite(baseSuite string) Option {
+	return func(o *options) {
+		o.baseSuite = baseSuite
+	}
+}
+
+// WithBuildTags adds go:build constraints to all generated suites, e.g. integration
+func WithBuildTags(tags ...string) Option {
+	return func(o *options) {
+		o.config.BuildTags = append(o.config.BuildTags, tags...)
+	}
+}
+
+// WithRewriters rewrites each line of the bash blocks of the examples with the rewriters applied in order, e.g. rules
+// parsed by rewrite.ParseRules
+func WithRewriters(rewriters ...rewrite.Rewriter) Option {
+	return func(o *options) {
+		o.rewriters = append(o.rewriters, rewriters...)
+	}
+}
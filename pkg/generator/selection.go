@@ -0,0 +1,178 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/gotestmd/internal/config"
+	"github.com/networkservicemesh/gotestmd/internal/glob"
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
+)
+
+// selection selects the example dirs found in the input dirs by WithInclude and WithExclude globs
+type selection struct {
+	include glob.Patterns
+	exclude glob.Patterns
+	// includeDeps adds excluded examples required by the selected ones instead of failing
+	includeDeps bool
+}
+
+func newSelection(c *config.Config) (*selection, error) {
+	var result = &selection{includeDeps: c.ExcludedDeps == config.ExcludedDepsInclude}
+	var err error
+	if result.include, err = glob.CompileAll(c.Include...); err != nil {
+		return nil, err
+	}
+	if result.exclude, err = glob.CompileAll(c.Exclude...); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// walk returns the selected and the excluded dirs of fsys rooted at the root. Excluding a dir excludes its subtree.
+// .git dirs are skipped
+func (s *selection) walk(fsys fs.FS, root string) (selected, excluded []string) {
+	var excludedTrees = map[string]bool{}
+	_ = fs.WalkDir(fsys, ".",
+		func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			if d.Name() == ".git" {
+				return fs.SkipDir
+			}
+			path := filepath.Join(root, filepath.FromSlash(name))
+			if s == nil {
+				selected = append(selected, path)
+				return nil
+			}
+			if excludedTrees[filepath.Dir(path)] || s.exclude.Match(path) != nil {
+				excludedTrees[path] = true
+				excluded = append(excluded, path)
+				return nil
+			}
+			if len(s.include) > 0 && s.include.Match(path) == nil {
+				excluded = append(excluded, path)
+				return nil
+			}
+			selected = append(selected, path)
+			return nil
+		})
+	return selected, excluded
+}
+
+// addDependencies adds excluded examples required or included by the selected ones.
+// Fails if there are such examples and excluded dependencies shouldn't be included
+func (s *selection) addDependencies(parseDir func(dir string) (*parser.Example, error), examples []*parser.Example, excludedDirs []string) ([]*parser.Example, error) {
+	if s == nil || len(excludedDirs) == 0 {
+		return examples, nil
+	}
+	var excluded = map[string]bool{}
+	for _, dir := range excludedDirs {
+		excluded[filepath.Clean(dir)] = true
+	}
+	var parsed = map[string]*parser.Example{}
+	var parseErrs = map[string]parser.ErrorList{}
+	parse := func(dir string) *parser.Example {
+		if _, ok := parsed[dir]; !ok {
+			ex, err := parseDir(dir)
+			if errs, ok := err.(parser.ErrorList); ok {
+				parseErrs[dir] = errs
+			}
+			parsed[dir] = ex
+		}
+		return parsed[dir]
+	}
+	var selected = map[string]bool{}
+	var ids = map[string]string{}
+	for _, e := range examples {
+		selected[filepath.Clean(e.Dir)] = true
+		if e.ID != "" {
+			ids[e.ID] = filepath.Clean(e.Dir)
+		}
+	}
+	var excludedIDs map[string]string
+	resolve := func(e *parser.Example, link string) string {
+		if !linker.IsAlias(link) {
+			return filepath.Join(e.Dir, link)
+		}
+		id := strings.TrimPrefix(link, "@")
+		if dir, ok := ids[id]; ok {
+			return dir
+		}
+		if excludedIDs == nil {
+			// Aliases can point anywhere, so all excluded examples are parsed to find the id
+			excludedIDs = map[string]string{}
+			for dir := range excluded {
+				if ex := parse(dir); ex != nil && ex.ID != "" {
+					excludedIDs[ex.ID] = dir
+				}
+			}
+		}
+		return excludedIDs[id]
+	}
+
+	var result = examples
+	var depErrs parser.ErrorList
+	for i := 0; i < len(result); i++ {
+		e := result[i]
+		var links = append([]string{}, e.Includes...)
+		for _, require := range e.Requires {
+			if linker.IsRemote(require) || linker.IsURL(require) {
+				continue
+			}
+			if j := strings.LastIndex(require, "#"); j >= 0 {
+				require = require[:j]
+			}
+			links = append(links, require)
+		}
+		for _, link := range links {
+			dir := resolve(e, link)
+			if dir == "" || selected[dir] {
+				continue
+			}
+			if !excluded[dir] {
+				continue
+			}
+			if !s.includeDeps {
+				return nil, errors.Errorf("%v depends on %v excluded by --include and --exclude, use --excluded-deps=%v to add it", e.Source, dir, config.ExcludedDepsInclude)
+			}
+			selected[dir] = true
+			dep := parse(dir)
+			depErrs = append(depErrs, parseErrs[dir]...)
+			if dep != nil {
+				logrus.Infof("%v is added as a dependency of %v", dir, e.Source)
+				result = append(result, dep)
+			}
+		}
+	}
+	if len(depErrs) > 0 {
+		depErrs.Sort()
+		return nil, errors.Errorf("cannot parse examples:\n%v", depErrs.Error())
+	}
+	return result, nil
+}
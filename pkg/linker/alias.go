@@ -0,0 +1,83 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linker
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// aliasPrefix marks links referencing an example by the id from its front matter: @basic, @basic#Kernel2Kernel
+const aliasPrefix = "@"
+
+// IsAlias returns true if the link references an example by its id
+func IsAlias(link string) bool {
+	return strings.HasPrefix(link, aliasPrefix)
+}
+
+// indexIDs returns examples by the ids declared in their front matter
+func indexIDs(examples []*LinkedExample) (map[string]*LinkedExample, error) {
+	ids := map[string]*LinkedExample{}
+	for _, e := range examples {
+		if e.ID == "" {
+			continue
+		}
+		if other, ok := ids[e.ID]; ok {
+			return nil, errors.Errorf("duplicate id %v of examples %v and %v", e.ID, displayName(other), displayName(e))
+		}
+		ids[e.ID] = e
+	}
+	return ids, nil
+}
+
+// resolveAliases replaces aliases in links with example names
+func resolveAliases(aliases map[string]*LinkedExample, examples []*LinkedExample) error {
+	resolve := func(e *LinkedExample, links []string, optional bool) error {
+		for i, link := range links {
+			if !IsAlias(link) {
+				continue
+			}
+			id, test := strings.TrimPrefix(link, aliasPrefix), ""
+			if j := strings.Index(id, testSeparator); j >= 0 {
+				id, test = id[:j], id[j:]
+			}
+			target, ok := aliases[id]
+			if !ok {
+				if optional {
+					continue
+				}
+				return errors.Errorf("unknown id %v referenced by %v", id, displayName(e))
+			}
+			links[i] = target.Name + test
+		}
+		return nil
+	}
+
+	for _, e := range examples {
+		if err := resolve(e, e.Includes, false); err != nil {
+			return err
+		}
+		if err := resolve(e, e.Requires, false); err != nil {
+			return err
+		}
+		if err := resolve(e, e.OptionalRequires, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
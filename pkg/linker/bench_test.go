@@ -0,0 +1,94 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linker_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/networkservicemesh/gotestmd/internal/glob"
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
+)
+
+// largeTree returns a tree of about n examples: groups of suites with leaf tests, where each suite requires the
+// previous suite of its group, the first test of the suite before it by path and the first suite of the previous group
+// by alias, so the dependency chains are as long as the groups
+func largeTree(n int) []*parser.Example {
+	const suites, tests = 20, 4
+	groups := n / (suites * (tests + 1))
+	root := &parser.Example{Dir: "examples"}
+	var result = []*parser.Example{root}
+	for g := 0; g < groups; g++ {
+		group := fmt.Sprintf("group%v", g)
+		root.Includes = append(root.Includes, group)
+		groupExample := &parser.Example{Dir: "examples/" + group}
+		result = append(result, groupExample)
+		for s := 0; s < suites; s++ {
+			suite := fmt.Sprintf("suite%v", s)
+			groupExample.Includes = append(groupExample.Includes, suite)
+			e := &parser.Example{Dir: "examples/" + group + "/" + suite, Run: []string{"echo " + suite}}
+			if s == 0 {
+				e.ID = group
+				if g > 0 {
+					e.Requires = append(e.Requires, fmt.Sprintf("@group%v", g-1))
+				}
+			} else {
+				e.Requires = append(e.Requires, fmt.Sprintf("../suite%v", s-1), fmt.Sprintf("../suite%v#Test0", s-1))
+			}
+			result = append(result, e)
+			for t := 0; t < tests; t++ {
+				test := fmt.Sprintf("test%v", t)
+				e.Includes = append(e.Includes, test)
+				result = append(result, &parser.Example{Dir: e.Dir + "/" + test, Run: []string{"echo " + test}})
+			}
+		}
+	}
+	return result
+}
+
+func benchmarkLink(b *testing.B, options ...linker.Option) {
+	for _, n := range []int{1000, 5000, 20000} {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				examples := largeTree(n)
+				b.StartTimer()
+				if _, err := linker.New("examples/", options...).Link(examples...); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkLink(b *testing.B) {
+	benchmarkLink(b)
+}
+
+func BenchmarkLinkPrune(b *testing.B) {
+	// The first suites of the groups are pruned with all suites of the tree requiring them
+	patterns, err := glob.CompileAll("examples/group*/suite0")
+	if err != nil {
+		b.Fatal(err)
+	}
+	benchmarkLink(b, linker.WithPrune(patterns))
+}
+
+func BenchmarkLinkRunTags(b *testing.B) {
+	benchmarkLink(b, linker.WithTags([]string{"smoke"}, nil))
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linker
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	includesLink = "includes"
+	requiresLink = "requires"
+)
+
+// Link represents a directed link between two examples
+type Link struct {
+	From *LinkedExample
+	To   *LinkedExample
+	Kind string
+}
+
+// CycleError is returned when links between examples form a cycle
+type CycleError struct {
+	Links []*Link
+}
+
+// Error returns the full dependency chain of the cycle and suggests the link to break
+func (e *CycleError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("dependency cycle detected: ")
+	for _, l := range e.Links {
+		sb.WriteString(displayName(l.From))
+		sb.WriteString(" → ")
+	}
+	sb.WriteString(displayName(e.Links[0].From))
+	for _, l := range e.Links {
+		_, _ = fmt.Fprintf(&sb, "\n\t%v (%v) %v %v", displayName(l.From), readme(l.From), l.Kind, displayName(l.To))
+	}
+	last := e.Links[len(e.Links)-1]
+	_, _ = fmt.Fprintf(&sb, "\nconsider removing the link to %v from %v", displayName(last.To), readme(last.From))
+	return sb.String()
+}
+
+func displayName(e *LinkedExample) string {
+	if e.Name == "" {
+		return "."
+	}
+	return e.Name
+}
+
+func readme(e *LinkedExample) string {
+	return filepath.Join(e.Dir, "README.md")
+}
+
+// findCycle returns the first cycle reachable over the links returned by next
+func findCycle(examples []*LinkedExample, next func(*LinkedExample) []*Link) *CycleError {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	var state = map[*LinkedExample]int{}
+	var stack []*Link
+
+	var visit func(e *LinkedExample) *CycleError
+	visit = func(e *LinkedExample) *CycleError {
+		state[e] = visiting
+		for _, l := range next(e) {
+			switch state[l.To] {
+			case visiting:
+				start := len(stack)
+				for i := range stack {
+					if stack[i].From == l.To {
+						start = i
+						break
+					}
+				}
+				var links = append([]*Link{}, stack[start:]...)
+				return &CycleError{Links: append(links, l)}
+			case visited:
+				continue
+			}
+			stack = append(stack, l)
+			if err := visit(l.To); err != nil {
+				return err
+			}
+			stack = stack[:len(stack)-1]
+		}
+		state[e] = visited
+		return nil
+	}
+
+	for _, e := range examples {
+		if state[e] != 0 {
+			continue
+		}
+		if err := visit(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linker
+
+// Roots returns the examples not included by other examples. Their suites run the rest of the tree
+func Roots(examples []*LinkedExample) []*LinkedExample {
+	var result []*LinkedExample
+	for _, e := range examples {
+		if len(e.Parents) == 0 {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// Dependents returns the examples depending on the example: the examples it includes and the examples requiring it or
+// its tests, recursively. Their suites run the steps of the example, so they are affected by its changes
+func Dependents(examples []*LinkedExample, e *LinkedExample) []*LinkedExample {
+	var dependents = map[*LinkedExample][]*LinkedExample{}
+	var index = indexByName(examples)
+	for _, example := range examples {
+		for _, dep := range dependencies(index, example) {
+			dependents[dep] = append(dependents[dep], example)
+		}
+	}
+
+	var found = map[*LinkedExample]bool{}
+	var visit func(e *LinkedExample)
+	visit = func(e *LinkedExample) {
+		for _, dependent := range dependents[e] {
+			if !found[dependent] {
+				found[dependent] = true
+				visit(dependent)
+			}
+		}
+	}
+	visit(e)
+
+	var result []*LinkedExample
+	for _, example := range examples {
+		if found[example] && example != e {
+			result = append(result, example)
+		}
+	}
+	return result
+}
+
+// Dependencies returns the examples set up before the example: its parents, the examples it requires and the examples
+// of its required tests with their dependencies recursively in topological order
+func Dependencies(examples []*LinkedExample, e *LinkedExample) []*LinkedExample {
+	var index = indexByName(examples)
+	var found = map[*LinkedExample]bool{}
+	var visit func(e *LinkedExample)
+	visit = func(e *LinkedExample) {
+		for _, dep := range dependencies(index, e) {
+			if !found[dep] {
+				found[dep] = true
+				visit(dep)
+			}
+		}
+	}
+	visit(e)
+
+	var result []*LinkedExample
+	for _, example := range TopologicalOrder(examples) {
+		if found[example] {
+			result = append(result, example)
+		}
+	}
+	return result
+}
+
+// TopologicalOrder returns the examples ordered so each example follows the examples it depends on: its parents, the
+// examples it requires and the examples of its required tests. The order of independent examples is kept
+func TopologicalOrder(examples []*LinkedExample) []*LinkedExample {
+	var index = indexByName(examples)
+	var visited = map[*LinkedExample]bool{}
+	var result []*LinkedExample
+	var visit func(e *LinkedExample)
+	visit = func(e *LinkedExample) {
+		if visited[e] || index[e.Name] != e {
+			return
+		}
+		// Linked examples have no cycles, so the example can be marked before its dependencies
+		visited[e] = true
+		for _, dep := range dependencies(index, e) {
+			visit(dep)
+		}
+		result = append(result, e)
+	}
+	for _, e := range examples {
+		visit(e)
+	}
+	return result
+}
+
+// dependencies returns the examples of the index the example directly depends on
+func dependencies(index map[string]*LinkedExample, e *LinkedExample) []*LinkedExample {
+	var result []*LinkedExample
+	result = append(result, e.Parents...)
+	for _, require := range append(append([]string{}, e.Requires...), e.OptionalRequires...) {
+		if dep, ok := index[require]; ok {
+			result = append(result, dep)
+		}
+	}
+	return append(result, e.RequiredTests...)
+}
+
+func indexByName(examples []*LinkedExample) map[string]*LinkedExample {
+	var index = make(map[string]*LinkedExample, len(examples))
+	for _, e := range examples {
+		index[e.Name] = e
+	}
+	return index
+}
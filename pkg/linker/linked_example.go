@@ -0,0 +1,161 @@
+// Copyright (c) 2020-2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linker
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
+)
+
+// LinkedExample represents parser.Example with links
+type LinkedExample struct {
+	*parser.Example
+	Name string
+	// Root is the input dir containing the example
+	Root     string
+	Children []*LinkedExample
+	Parents  []*LinkedExample
+	// RequiredTests are tests of other examples whose steps should be run before this example
+	RequiredTests []*LinkedExample
+	// Remote is set if the example is located in another Go module
+	Remote *Remote
+	// parentDependencies memoizes hasParentDependency, suites are the names of the included examples that aren't leaves
+	parentDependencies map[string]bool
+	suites             map[string]bool
+}
+
+// hasParentDependency returns true if the example is set up by the parents: it's a parent, a dependency of a parent
+// or a dependency of its parents recursively. Answers are memoized, so the checks of large trees walk each ancestor once
+// per dependency instead of copying the dependencies of all ancestors into each example
+func (e *LinkedExample) hasParentDependency(dep string) bool {
+	if result, ok := e.parentDependencies[dep]; ok {
+		return result
+	}
+	var result bool
+	for _, parent := range e.Parents {
+		if parent.Name == dep || parent.hasSuite(dep) || contains(parent.Requires, dep) || parent.hasParentDependency(dep) {
+			result = true
+			break
+		}
+	}
+	if e.parentDependencies == nil {
+		e.parentDependencies = map[string]bool{}
+	}
+	e.parentDependencies[dep] = result
+	return result
+}
+
+// hasSuite returns true if the example includes the example that isn't a leaf
+func (e *LinkedExample) hasSuite(name string) bool {
+	if e.suites == nil {
+		e.suites = map[string]bool{}
+		for _, child := range e.Children {
+			if !child.IsLeaf() {
+				e.suites[child.Name] = true
+			}
+		}
+	}
+	return e.suites[name]
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// IsLeaf returns true if the example have not children and is not using as a dependency
+func (e *LinkedExample) IsLeaf() bool {
+	return len(e.Children) == 0 && len(e.Requires) == 0 && len(e.RequiredTests) == 0 && len(e.Parents) > 0
+}
+
+// Dependencies returns unique dependecies for this example
+func (e *LinkedExample) Dependencies() []string {
+	var deps []string
+
+	for _, child := range e.Children {
+		if child.IsLeaf() {
+			continue
+		}
+		deps = append(deps, child.Name)
+	}
+
+	return append(e.ParentDependencies(), deps...)
+}
+
+// ParentDependencies returns suites to setup before this example
+func (e *LinkedExample) ParentDependencies() []string {
+	var deps []string
+
+	for _, dep := range e.Requires {
+		if !e.hasParentDependency(dep) {
+			deps = append(deps, dep)
+		}
+	}
+
+	return deps
+}
+
+// NewLinkedExample creates new linked example based on parser.Example
+func NewLinkedExample(root string, e *parser.Example) *LinkedExample {
+	var result = new(LinkedExample)
+	result.Example = e
+	result.Root = root
+	rel, err := filepath.Rel(root, e.Dir)
+	if err != nil {
+		rel = strings.TrimPrefix(e.Dir, root)
+	}
+	result.Name = joinName(root, "", rel)
+
+	for i := 0; i < len(e.Includes); i++ {
+		if IsAlias(e.Includes[i]) {
+			continue
+		}
+		e.Includes[i] = joinName(root, result.Name, e.Includes[i])
+	}
+	for i := 0; i < len(e.Requires); i++ {
+		if IsRemote(e.Requires[i]) || IsAlias(e.Requires[i]) || IsURL(e.Requires[i]) {
+			continue
+		}
+		e.Requires[i] = joinName(root, result.Name, e.Requires[i])
+	}
+	for i := 0; i < len(e.OptionalRequires); i++ {
+		if IsRemote(e.OptionalRequires[i]) || IsAlias(e.OptionalRequires[i]) || IsURL(e.OptionalRequires[i]) {
+			continue
+		}
+		e.OptionalRequires[i] = joinName(root, result.Name, e.OptionalRequires[i])
+	}
+
+	return result
+}
+
+// joinName resolves the link relative to the example name. Names are relative to the root, the root example has an empty name
+func joinName(root, name, link string) string {
+	result := filepath.Join(name, link)
+	if rel, err := filepath.Rel(root, filepath.Join(root, result)); err == nil {
+		result = rel
+	}
+	if result == "." {
+		return ""
+	}
+	return result
+}
@@ -0,0 +1,329 @@
+// Copyright (c) 2020-2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package linker provides a linker to add links between examples
+package linker
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/gotestmd/internal/glob"
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
+)
+
+// testSeparator separates the example path and the test name in Requires links: ../basic#Kernel2Kernel
+const testSeparator = "#"
+
+var nameRegex = regexp.MustCompile("[^a-zA-Z0-9]+")
+
+// Linker can add links between examples
+type Linker struct {
+	root         string
+	roots        []string
+	fetcher      Fetcher
+	urlFetcher   URLFetcher
+	skipOptional bool
+	prune        glob.Patterns
+	runTags      []string
+	skipTags     []string
+	pruned       []*Pruned
+}
+
+// Option is an option for the Linker
+type Option func(l *Linker)
+
+// WithFetcher sets the fetcher used to download examples located in other Go modules
+func WithFetcher(fetcher Fetcher) Option {
+	return func(l *Linker) {
+		l.fetcher = fetcher
+	}
+}
+
+// WithURLFetcher sets the fetcher used to download examples referenced by URL. Requires links with URLs fail to link
+// without it, so examples are never downloaded unless the caller asks for it
+func WithURLFetcher(fetcher URLFetcher) Option {
+	return func(l *Linker) {
+		l.urlFetcher = fetcher
+	}
+}
+
+// WithoutOptional drops optional Requires even if they exist in the input tree
+func WithoutOptional() Option {
+	return func(l *Linker) {
+		l.skipOptional = true
+	}
+}
+
+// WithPrune removes examples matching the patterns together with their subtrees and all examples depending on them
+func WithPrune(patterns glob.Patterns) Option {
+	return func(l *Linker) {
+		l.prune = patterns
+	}
+}
+
+// WithTags removes examples by their tags like WithPrune. If run tags are set, only the examples having any of them are
+// kept with the examples they include and the examples they need. Examples having any of skip tags are removed
+func WithTags(run, skip []string) Option {
+	return func(l *Linker) {
+		l.runTags, l.skipTags = run, skip
+	}
+}
+
+// WithRoots adds input dirs linked together with the main root. Examples of all roots are named relative to the main root
+func WithRoots(roots ...string) Option {
+	return func(l *Linker) {
+		l.roots = append(l.roots, roots...)
+	}
+}
+
+// New creates new Linker instance
+func New(root string, options ...Option) *Linker {
+	l := &Linker{
+		root:    root,
+		fetcher: GoModFetcher,
+	}
+	for _, o := range options {
+		o(l)
+	}
+	return l
+}
+
+// Link adds all possible links between examples. Return error if any link is invalid
+func (l *Linker) Link(examples ...*parser.Example) ([]*LinkedExample, error) {
+	index := map[string]*LinkedExample{}
+	var result []*LinkedExample
+	for _, example := range examples {
+		linkedExample := NewLinkedExample(l.root, example)
+		linkedExample.Root = l.rootOf(example.Dir)
+		if other, ok := index[linkedExample.Name]; ok {
+			return nil, errors.Errorf("duplicate example %v: %v and %v", displayName(linkedExample), other.Dir, example.Dir)
+		}
+		index[linkedExample.Name] = linkedExample
+		result = append(result, linkedExample)
+	}
+	ids, err := indexIDs(result)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveAliases(ids, result); err != nil {
+		return nil, err
+	}
+	if err := checkVersions(ids, result); err != nil {
+		return nil, err
+	}
+	l.resolveOptional(index, result)
+	remotes, err := l.fetchRemotes(index, result)
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, remotes...)
+	downloaded, err := l.fetchURLs(index, result)
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, downloaded...)
+	for _, linkedExample := range result {
+		for _, include := range linkedExample.Includes {
+			child := index[include]
+			if child == nil {
+				return nil, errors.Errorf("unknown include %v for example %v", include, linkedExample.Name)
+			}
+			child.Parents = append(child.Parents, linkedExample)
+			linkedExample.Children = append(linkedExample.Children, child)
+		}
+	}
+	if err := findCycle(result, includeLinks); err != nil {
+		return nil, err
+	}
+	if err := resolveTests(index, result); err != nil {
+		return nil, err
+	}
+	if err := checkRequires(index, result); err != nil {
+		return nil, err
+	}
+	result, l.pruned = prune(l.pruneReasons(index, result), index, result)
+	for _, linkedExample := range result {
+		var filteredRequires []string
+		for _, require := range linkedExample.Requires {
+			if !linkedExample.hasParentDependency(require) {
+				filteredRequires = append(filteredRequires, require)
+			}
+		}
+		linkedExample.Requires = filteredRequires
+	}
+	if err := findCycle(result, dependencyLinks(index)); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// rootOf returns the most specific input root containing the dir
+func (l *Linker) rootOf(dir string) string {
+	var result = l.root
+	var longest = -1
+	for _, root := range append([]string{l.root}, l.roots...) {
+		rel, err := filepath.Rel(root, dir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if len(filepath.Clean(root)) > longest {
+			result, longest = root, len(filepath.Clean(root))
+		}
+	}
+	return result
+}
+
+// Pruned returns examples removed by the prune patterns during the last Link call
+func (l *Linker) Pruned() []*Pruned {
+	return l.pruned
+}
+
+func includeLinks(e *LinkedExample) []*Link {
+	var links []*Link
+	for _, child := range e.Children {
+		links = append(links, &Link{From: e, To: child, Kind: includesLink})
+	}
+	return links
+}
+
+func dependencyLinks(index map[string]*LinkedExample) func(*LinkedExample) []*Link {
+	return func(e *LinkedExample) []*Link {
+		links := includeLinks(e)
+		for _, require := range e.Requires {
+			if dep, ok := index[require]; ok {
+				links = append(links, &Link{From: e, To: dep, Kind: requiresLink})
+			}
+		}
+		return links
+	}
+}
+
+// resolveTests moves Requires pointing to a test of another example (path#Test) to RequiredTests
+func resolveTests(index map[string]*LinkedExample, examples []*LinkedExample) error {
+	var tests = map[*LinkedExample]map[string]*LinkedExample{}
+	for _, e := range examples {
+		var requires []string
+		for _, require := range e.Requires {
+			i := strings.LastIndex(require, testSeparator)
+			if i < 0 || IsRemote(require) {
+				requires = append(requires, require)
+				continue
+			}
+			target, name := require[:i], require[i+len(testSeparator):]
+			suite := index[target]
+			if suite == nil {
+				return errors.Errorf("unknown example %v required by %v", target, displayName(e))
+			}
+			if tests[suite] == nil {
+				tests[suite] = indexTests(suite)
+			}
+			test := tests[suite][testKey(name)]
+			if test == nil {
+				return errors.Errorf("unknown test %v of example %v required by %v", name, displayName(suite), displayName(e))
+			}
+			e.RequiredTests = append(e.RequiredTests, test)
+		}
+		e.Requires = requires
+	}
+	return nil
+}
+
+// checkRequires returns an error if an example requires an example missing in the input tree
+func checkRequires(index map[string]*LinkedExample, examples []*LinkedExample) error {
+	for _, e := range examples {
+		for _, require := range e.Requires {
+			if index[require] == nil {
+				return errors.Errorf("cannot find %v required by %v", require, displayName(e))
+			}
+		}
+	}
+	return nil
+}
+
+// indexTests returns the tests of the suite by their keys. The first test wins if the keys of several tests are the same
+func indexTests(suite *LinkedExample) map[string]*LinkedExample {
+	var result = map[string]*LinkedExample{}
+	for _, child := range suite.Children {
+		if !child.IsLeaf() {
+			continue
+		}
+		if key := testKey(filepath.Base(child.Name)); result[key] == nil {
+			result[key] = child
+		}
+	}
+	return result
+}
+
+// testKey returns the name of the test with only the letters and digits in lower case, so Kernel2Kernel, kernel2kernel
+// and Kernel-2-Kernel point to the same test
+func testKey(name string) string {
+	return strings.ToLower(nameRegex.ReplaceAllString(name, ""))
+}
+
+// resolveOptional adds optional Requires to the regular ones if they exist in the input tree
+func (l *Linker) resolveOptional(index map[string]*LinkedExample, examples []*LinkedExample) {
+	for _, e := range examples {
+		for _, require := range e.OptionalRequires {
+			if l.skipOptional {
+				logrus.Infof("optional dependency %v of %v is skipped", require, displayName(e))
+				continue
+			}
+			if _, ok := index[require]; !ok && !IsRemote(require) && !IsURL(require) {
+				logrus.Infof("optional dependency %v of %v is not found", require, displayName(e))
+				continue
+			}
+			e.Requires = append(e.Requires, require)
+		}
+	}
+}
+
+// fetchRemotes downloads and parses examples from other Go modules referenced by Requires
+func (l *Linker) fetchRemotes(index map[string]*LinkedExample, examples []*LinkedExample) ([]*LinkedExample, error) {
+	var result []*LinkedExample
+	var p = parser.New()
+	for _, e := range examples {
+		for _, require := range e.Requires {
+			remote, ok := ParseRemote(require)
+			if !ok || index[require] != nil {
+				continue
+			}
+			dir, err := l.fetcher.Fetch(remote.Module, remote.Version)
+			if err != nil {
+				return nil, errors.Errorf("cannot fetch %v required by %v: %v", require, displayName(e), err.Error())
+			}
+			remoteExample, err := p.ParseFile(filepath.Join(dir, filepath.FromSlash(remote.Path), "README.md"))
+			if err != nil {
+				return nil, errors.Errorf("cannot parse %v required by %v: %v", require, displayName(e), err.Error())
+			}
+			// Dependencies of the remote example are set up by its own suite
+			remoteExample.Includes = nil
+			remoteExample.Requires = nil
+			linkedExample := &LinkedExample{
+				Example: remoteExample,
+				Name:    require,
+				Remote:  remote,
+			}
+			index[require] = linkedExample
+			result = append(result, linkedExample)
+		}
+	}
+	return result, nil
+}
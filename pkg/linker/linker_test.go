@@ -0,0 +1,436 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linker_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/gotestmd/internal/glob"
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
+)
+
+func TestLinkRequiresCycle(t *testing.T) {
+	_, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/a", Requires: []string{"../b"}, Run: []string{"echo a"}},
+		&parser.Example{Dir: "examples/b", Requires: []string{"../c"}, Run: []string{"echo b"}},
+		&parser.Example{Dir: "examples/c", Requires: []string{"../a"}, Run: []string{"echo c"}},
+	)
+	require.Error(t, err)
+
+	cycle, ok := err.(*linker.CycleError)
+	require.True(t, ok)
+	require.Len(t, cycle.Links, 3)
+	require.Equal(t, `dependency cycle detected: a → b → c → a
+	a (examples/a/README.md) requires b
+	b (examples/b/README.md) requires c
+	c (examples/c/README.md) requires a
+consider removing the link to a from examples/c/README.md`, err.Error())
+}
+
+func TestLinkIncludesCycle(t *testing.T) {
+	_, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/a", Includes: []string{"b"}},
+		&parser.Example{Dir: "examples/a/b", Includes: []string{".."}},
+	)
+	require.Error(t, err)
+	require.IsType(t, new(linker.CycleError), err)
+	require.Contains(t, err.Error(), "a → a/b → a")
+}
+
+func TestLinkRequiresParent(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/a", Includes: []string{"b"}},
+		&parser.Example{Dir: "examples/a/b", Requires: []string{".."}, Run: []string{"echo b"}},
+	)
+	require.NoError(t, err)
+	require.Len(t, examples, 2)
+	require.Empty(t, examples[1].Requires)
+}
+
+func TestLinkRemoteRequires(t *testing.T) {
+	moduleDir := t.TempDir()
+	exampleDir := filepath.Join(moduleDir, "examples", "basic")
+	require.NoError(t, os.MkdirAll(exampleDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(exampleDir, "README.md"), []byte("# Run\n```bash\necho basic\n```\n"), 0o600))
+
+	var fetched []string
+	fetcher := linker.FetcherFunc(func(module, version string) (string, error) {
+		fetched = append(fetched, module+"@"+version)
+		return moduleDir, nil
+	})
+
+	const remote = "github.com/networkservicemesh/deployments-k8s//examples/basic@v1.9.0"
+	examples, err := linker.New("examples/", linker.WithFetcher(fetcher)).Link(
+		&parser.Example{Dir: "examples/a", Requires: []string{remote}, Run: []string{"echo a"}},
+		&parser.Example{Dir: "examples/b", Requires: []string{remote}, Run: []string{"echo b"}},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{"github.com/networkservicemesh/deployments-k8s@v1.9.0"}, fetched)
+	require.Len(t, examples, 3)
+	require.Equal(t, []string{remote}, examples[0].Requires)
+	require.Equal(t, remote, examples[2].Name)
+	require.Equal(t, "examples/basic", examples[2].Remote.Path)
+	require.Equal(t, []string{"echo basic"}, examples[2].Run)
+}
+
+func TestParseRemote(t *testing.T) {
+	remote, ok := linker.ParseRemote("github.com/networkservicemesh/deployments-k8s//examples/basic@v1.9.0")
+	require.True(t, ok)
+	require.Equal(t, &linker.Remote{Module: "github.com/networkservicemesh/deployments-k8s", Path: "examples/basic", Version: "v1.9.0"}, remote)
+
+	remote, ok = linker.ParseRemote("github.com/networkservicemesh/deployments-k8s//examples/basic/")
+	require.True(t, ok)
+	require.Equal(t, "examples/basic", remote.Path)
+	require.Equal(t, "latest", remote.Version)
+
+	for _, link := range []string{
+		"../basic",
+		"examples//basic",
+		"../deployments-k8s//examples/basic",
+		"github.com/networkservicemesh/deployments-k8s//",
+		"github.com/networkservicemesh/deployments-k8s//examples/basic@",
+		"https://github.com/networkservicemesh/deployments-k8s//examples/basic",
+	} {
+		_, ok = linker.ParseRemote(link)
+		require.False(t, ok, link)
+	}
+}
+
+func TestLinkOptionalRequires(t *testing.T) {
+	newExamples := func() []*parser.Example {
+		return []*parser.Example{
+			{Dir: "examples/a", OptionalRequires: []string{"../b", "../missing"}, Run: []string{"echo a"}},
+			{Dir: "examples/b", Run: []string{"echo b"}},
+		}
+	}
+
+	examples, err := linker.New("examples/").Link(newExamples()...)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b"}, examples[0].Requires)
+
+	examples, err = linker.New("examples/", linker.WithoutOptional()).Link(newExamples()...)
+	require.NoError(t, err)
+	require.Empty(t, examples[0].Requires)
+}
+
+func TestLinkMissingRequires(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/a", OptionalRequires: []string{"../missing"}, Run: []string{"echo a"}},
+	)
+	require.NoError(t, err)
+	require.Empty(t, examples[0].Requires)
+
+	_, err = linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/a", Requires: []string{"../missing"}, Run: []string{"echo a"}},
+	)
+	require.EqualError(t, err, "cannot find missing required by a")
+}
+
+func TestLinkRequiredTest(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/basic", Includes: []string{"Kernel2Kernel", "Kernel2Memif"}, Run: []string{"echo basic"}},
+		&parser.Example{Dir: "examples/basic/Kernel2Kernel", Run: []string{"echo k2k"}},
+		&parser.Example{Dir: "examples/basic/Kernel2Memif", Run: []string{"echo k2m"}},
+		&parser.Example{Dir: "examples/consumer", Requires: []string{"../basic#kernel2kernel"}, Run: []string{"echo consumer"}},
+	)
+	require.NoError(t, err)
+	require.Empty(t, examples[3].Requires)
+	require.Len(t, examples[3].RequiredTests, 1)
+	require.Equal(t, "basic/Kernel2Kernel", examples[3].RequiredTests[0].Name)
+
+	_, err = linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/basic", Run: []string{"echo basic"}},
+		&parser.Example{Dir: "examples/consumer", Requires: []string{"../basic#Unknown"}, Run: []string{"echo consumer"}},
+	)
+	require.EqualError(t, err, "unknown test Unknown of example basic required by consumer")
+}
+
+func TestLinkPrune(t *testing.T) {
+	patterns, err := glob.CompileAll("examples/experimental/**")
+	require.NoError(t, err)
+
+	l := linker.New("examples/", linker.WithPrune(patterns))
+	examples, err := l.Link(
+		&parser.Example{Dir: "examples/tree", Includes: []string{"leaf", "../experimental/leaf"}, Run: []string{"echo tree"}},
+		&parser.Example{Dir: "examples/tree/leaf", Run: []string{"echo leaf"}},
+		&parser.Example{Dir: "examples/experimental", Includes: []string{"leaf"}, Run: []string{"echo experimental"}},
+		&parser.Example{Dir: "examples/experimental/leaf", Run: []string{"echo experimental leaf"}},
+		&parser.Example{Dir: "examples/consumer", Requires: []string{"../experimental"}, Run: []string{"echo consumer"}},
+	)
+	require.NoError(t, err)
+	require.Len(t, examples, 2)
+	require.Len(t, examples[0].Children, 1)
+
+	var report []string
+	for _, p := range l.Pruned() {
+		report = append(report, p.String())
+	}
+	require.Equal(t, []string{
+		"experimental: matches examples/experimental/**",
+		"experimental/leaf: matches examples/experimental/**",
+		"consumer: requires pruned experimental",
+	}, report)
+}
+
+func TestLinkTags(t *testing.T) {
+	var slow = parser.FrontMatter{Tags: []string{"slow"}}
+	var smoke = parser.FrontMatter{Tags: []string{"smoke"}}
+	var source = func() []*parser.Example {
+		return []*parser.Example{
+			{Dir: "examples/tree", Includes: []string{"fast", "slow"}, Run: []string{"echo tree"}},
+			{Dir: "examples/tree/fast", FrontMatter: smoke, Run: []string{"echo fast"}},
+			{Dir: "examples/tree/slow", FrontMatter: slow, Run: []string{"echo slow"}},
+			{Dir: "examples/producer", Run: []string{"echo producer"}},
+			{Dir: "examples/consumer", FrontMatter: smoke, Requires: []string{"../producer"}, Run: []string{"echo consumer"}},
+			{Dir: "examples/heavy", FrontMatter: slow, Run: []string{"echo heavy"}},
+			{Dir: "examples/heavy-consumer", Requires: []string{"../heavy"}, Run: []string{"echo heavy consumer"}},
+		}
+	}
+	var names = func(examples []*linker.LinkedExample) []string {
+		var result []string
+		for _, e := range examples {
+			result = append(result, e.Name)
+		}
+		return result
+	}
+
+	l := linker.New("examples/", linker.WithTags(nil, []string{"slow"}))
+	examples, err := l.Link(source()...)
+	require.NoError(t, err)
+	require.Equal(t, []string{"tree", "tree/fast", "producer", "consumer"}, names(examples))
+	require.Len(t, examples[0].Children, 1)
+	require.Equal(t, "heavy-consumer: requires pruned heavy", l.Pruned()[2].String())
+
+	l = linker.New("examples/", linker.WithTags([]string{"smoke"}, nil))
+	examples, err = l.Link(source()...)
+	require.NoError(t, err)
+	require.Equal(t, []string{"tree", "tree/fast", "producer", "consumer"}, names(examples))
+	require.Equal(t, "tree/slow: has none of tags smoke", l.Pruned()[0].String())
+}
+
+func TestOrphans(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/producer", Run: []string{"echo producer"}},
+		&parser.Example{Dir: "examples/producer/consumer", Requires: []string{".."}, Run: []string{"echo consumer"}},
+		&parser.Example{Dir: "examples/docs/orphan"},
+	)
+	require.NoError(t, err)
+
+	// the consumer requiring its parent gets its own suite
+	orphans := linker.Orphans(examples)
+	require.Len(t, orphans, 1)
+	require.Equal(t, "docs/orphan", orphans[0].Name)
+
+	examples, err = linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/producer", Includes: []string{"consumer"}, Run: []string{"echo producer"}},
+		&parser.Example{Dir: "examples/producer/consumer", Run: []string{"echo consumer"}},
+		&parser.Example{Dir: "examples/docs/check"},
+		&parser.Example{Dir: "examples/app", Requires: []string{"../docs/check"}, Run: []string{"echo app"}},
+	)
+	require.NoError(t, err)
+	require.Empty(t, linker.Orphans(examples))
+}
+
+func TestLinkMultipleRoots(t *testing.T) {
+	examples, err := linker.New("apps/examples/", linker.WithRoots("infra/examples/")).Link(
+		&parser.Example{Dir: "apps/examples/consumer", Requires: []string{"../../../infra/examples/producer"}, Run: []string{"echo consumer"}},
+		&parser.Example{Dir: "infra/examples/producer", Run: []string{"echo producer"}},
+	)
+	require.NoError(t, err)
+	require.Equal(t, "consumer", examples[0].Name)
+	require.Equal(t, "apps/examples/", examples[0].Root)
+	require.Equal(t, "../../infra/examples/producer", examples[1].Name)
+	require.Equal(t, "infra/examples/", examples[1].Root)
+	require.Equal(t, []string{examples[1].Name}, examples[0].Requires)
+	require.Empty(t, linker.Orphans(examples))
+}
+
+func TestLinkAliases(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/moved/basic", FrontMatter: parser.FrontMatter{ID: "basic"}, Includes: []string{"Kernel2Kernel"}, Run: []string{"echo basic"}},
+		&parser.Example{Dir: "examples/moved/basic/Kernel2Kernel", Run: []string{"echo k2k"}},
+		&parser.Example{Dir: "examples/consumer", Requires: []string{"@basic"}, Run: []string{"echo consumer"}},
+		&parser.Example{Dir: "examples/test", Requires: []string{"@basic#Kernel2Kernel"}, Run: []string{"echo test"}},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{"moved/basic"}, examples[2].Requires)
+	require.Len(t, examples[3].RequiredTests, 1)
+	require.Equal(t, "moved/basic/Kernel2Kernel", examples[3].RequiredTests[0].Name)
+
+	_, err = linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/consumer", Requires: []string{"@basic"}, Run: []string{"echo consumer"}},
+	)
+	require.EqualError(t, err, "unknown id basic referenced by consumer")
+
+	_, err = linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/a", FrontMatter: parser.FrontMatter{ID: "basic"}},
+		&parser.Example{Dir: "examples/b", FrontMatter: parser.FrontMatter{ID: "basic"}},
+	)
+	require.EqualError(t, err, "duplicate id basic of examples a and b")
+
+	_, err = linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/a"},
+		&parser.Example{Dir: "examples/./a/"},
+	)
+	require.EqualError(t, err, "duplicate example a: examples/a and examples/./a/")
+}
+
+func TestLinkVersionConstraints(t *testing.T) {
+	spire := func(version string) *parser.Example {
+		return &parser.Example{Dir: "examples/spire", FrontMatter: parser.FrontMatter{ID: "spire", Version: version}, Run: []string{"echo spire"}}
+	}
+	consumer := &parser.Example{
+		Dir:         "examples/consumer",
+		FrontMatter: parser.FrontMatter{Constraints: []string{"spire >= 1.5, < 2"}},
+		Requires:    []string{"@spire"},
+		Run:         []string{"echo consumer"},
+	}
+
+	_, err := linker.New("examples/").Link(spire("1.5.2"), consumer)
+	require.NoError(t, err)
+
+	consumer.Requires = []string{"@spire"}
+	_, err = linker.New("examples/").Link(spire("1.4"), consumer)
+	require.EqualError(t, err, "consumer (examples/consumer/README.md) requires spire >= 1.5, < 2, but spire (examples/spire/README.md) has version 1.4")
+
+	consumer.Requires = []string{"@spire"}
+	_, err = linker.New("examples/").Link(spire(""), consumer)
+	require.EqualError(t, err, "consumer (examples/consumer/README.md) requires spire >= 1.5, < 2, but spire (examples/spire/README.md) has no version")
+}
+
+func TestParseConstraint(t *testing.T) {
+	c, err := linker.ParseConstraint("spire >= 1.5, != 1.6.1, < v2")
+	require.NoError(t, err)
+	require.Equal(t, "spire", c.ID)
+	require.True(t, c.Match([]int{1, 5}))
+	require.True(t, c.Match([]int{1, 9, 3}))
+	require.False(t, c.Match([]int{1, 6, 1}))
+	require.False(t, c.Match([]int{2}))
+	require.False(t, c.Match([]int{1, 4, 9}))
+
+	_, err = linker.ParseConstraint("spire ~ 1.5")
+	require.Error(t, err)
+	_, err = linker.ParseConstraint("spire >= 1.x")
+	require.Error(t, err)
+}
+
+func TestLinkURLRequires(t *testing.T) {
+	var requests int
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = fmt.Fprint(w, "# Run\n```bash\necho quickstart\n```\n")
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	link := func(cache *linker.URLCache) ([]*linker.LinkedExample, error) {
+		var options []linker.Option
+		if cache != nil {
+			cache.Client = server.Client()
+			options = append(options, linker.WithURLFetcher(cache))
+		}
+		return linker.New("examples/", options...).Link(
+			&parser.Example{Dir: "examples/consumer", Requires: []string{server.URL + "/spire/main/README.md"}, Run: []string{"echo consumer"}},
+		)
+	}
+
+	examples, err := link(linker.NewURLCache(cacheDir, false))
+	require.NoError(t, err)
+	require.Len(t, examples, 2)
+	require.Equal(t, []string{"echo quickstart"}, examples[1].Run)
+	require.Equal(t, []string{examples[1].Name}, examples[0].Requires)
+	require.Regexp(t, "^remote/x127_0_0_1_[0-9]+/spire/main/readme$", examples[1].Name)
+
+	_, err = link(linker.NewURLCache(cacheDir, true))
+	require.NoError(t, err)
+	require.Equal(t, 1, requests)
+
+	_, err = link(linker.NewURLCache(t.TempDir(), true))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "offline mode is enabled")
+
+	_, err = link(nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "downloading is disabled")
+	require.Equal(t, 1, requests)
+
+	_, err = linker.New("examples/", linker.WithURLFetcher(linker.NewURLCache(cacheDir, true))).Link(
+		&parser.Example{Dir: "examples/consumer", Requires: []string{"http://example.com/README.md"}, Run: []string{"echo consumer"}},
+	)
+	require.EqualError(t, err, "insecure url http://example.com/README.md required by consumer: only https urls are supported")
+
+	cache := linker.NewURLCache(cacheDir, false)
+	cache.Client = server.Client()
+	examples, err = linker.New("examples/", linker.WithURLFetcher(cache)).Link(
+		&parser.Example{Dir: "examples/consumer", Requires: []string{server.URL + "/spire/main/README.md"}, Run: []string{"echo consumer"}},
+		&parser.Example{Dir: "examples/other", Requires: []string{server.URL + "/spire/main/README.md"}, Run: []string{"echo other"}},
+		&parser.Example{Dir: "examples/single", Requires: []string{server.URL + "/spire/main/single.md"}, Run: []string{"echo single"}},
+	)
+	require.NoError(t, err)
+	require.Len(t, examples, 5)
+	require.Equal(t, examples[0].Requires, examples[1].Requires)
+	require.NotEqual(t, examples[0].Requires, examples[2].Requires)
+
+	_, err = linker.New("examples/", linker.WithURLFetcher(cache)).Link(
+		&parser.Example{Dir: "examples/consumer", Requires: []string{server.URL + "/spire/main/README.md"}, Run: []string{"echo consumer"}},
+		&parser.Example{Dir: "examples/" + examples[0].Requires[0], Source: "examples/remote/README.md", Run: []string{"echo local"}},
+	)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "as examples/remote/README.md")
+
+	_, err = linker.New("examples/", linker.WithURLFetcher(cache)).Link(
+		&parser.Example{Dir: "examples/consumer", Requires: []string{server.URL + "/spire/main/README.md"}, Run: []string{"echo consumer"}},
+		&parser.Example{Dir: "examples/other", Requires: []string{server.URL + "/spire/main/README.markdown"}, Run: []string{"echo other"}},
+	)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "has the same name")
+}
+
+func TestGraph(t *testing.T) {
+	examples, err := linker.New("examples/").Link(
+		&parser.Example{Dir: "examples/consumer", Requires: []string{"../spire"}, Run: []string{"echo consumer"}},
+		&parser.Example{Dir: "examples/spire", Includes: []string{"check"}, Run: []string{"echo spire"}},
+		&parser.Example{Dir: "examples/spire/check", Run: []string{"echo check"}},
+		&parser.Example{Dir: "examples/other", Run: []string{"echo other"}},
+		&parser.Example{Dir: "examples/single", Requires: []string{"../spire#check"}, Run: []string{"echo single"}},
+	)
+	require.NoError(t, err)
+	var names = func(examples []*linker.LinkedExample) []string {
+		var result []string
+		for _, e := range examples {
+			result = append(result, e.Name)
+		}
+		return result
+	}
+
+	require.Equal(t, []string{"consumer", "spire", "other", "single"}, names(linker.Roots(examples)))
+	require.Equal(t, []string{"consumer", "spire/check", "single"}, names(linker.Dependents(examples, examples[1])))
+	require.Equal(t, []string{"single"}, names(linker.Dependents(examples, examples[2])))
+	require.Empty(t, linker.Dependents(examples, examples[3]))
+	require.Equal(t, []string{"spire", "consumer", "spire/check", "other", "single"}, names(linker.TopologicalOrder(examples)))
+	require.Equal(t, []string{"spire"}, names(linker.Dependencies(examples, examples[0])))
+	require.Equal(t, []string{"spire", "spire/check"}, names(linker.Dependencies(examples, examples[4])))
+	require.Empty(t, linker.Dependencies(examples, examples[1]))
+}
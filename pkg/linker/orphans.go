@@ -0,0 +1,80 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linker
+
+import (
+	"path/filepath"
+)
+
+// Orphans returns nested examples that don't end up in any generated suite: they have no steps of their own to
+// generate a suite for and nothing having a suite includes or requires them.
+// Such examples are usually documentation that is never exercised by the generated tests.
+func Orphans(examples []*LinkedExample) []*LinkedExample {
+	var index = map[string]*LinkedExample{}
+	for _, e := range examples {
+		index[e.Name] = e
+	}
+
+	var exercised = map[*LinkedExample]bool{}
+	var queue []*LinkedExample
+	for _, e := range examples {
+		if hasSteps(e) {
+			exercised[e] = true
+			queue = append(queue, e)
+		}
+	}
+	for len(queue) > 0 {
+		e := queue[0]
+		queue = queue[1:]
+		var deps = append(append([]*LinkedExample(nil), e.Children...), e.RequiredTests...)
+		for _, require := range append(append([]string(nil), e.Requires...), e.OptionalRequires...) {
+			if dep, ok := index[require]; ok {
+				deps = append(deps, dep)
+			}
+		}
+		for _, dep := range deps {
+			if !exercised[dep] {
+				exercised[dep] = true
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	var result []*LinkedExample
+	for _, e := range examples {
+		if !exercised[e] && e.Remote == nil && !isTopLevel(e) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// hasSteps returns true if the example gets a suite running anything: its own steps, its tests or its dependencies
+func hasSteps(e *LinkedExample) bool {
+	if e.Remote != nil || e.IsLeaf() {
+		return false
+	}
+	return len(e.Run)+len(e.Cleanup)+len(e.Children)+len(e.Requires)+len(e.RequiredTests) > 0
+}
+
+func isTopLevel(e *LinkedExample) bool {
+	rel, err := filepath.Rel(e.Root, e.Dir)
+	if err != nil {
+		return filepath.Dir(e.Name) == "."
+	}
+	return filepath.Dir(rel) == "."
+}
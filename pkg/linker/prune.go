@@ -0,0 +1,188 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Pruned describes an example removed by the prune patterns
+type Pruned struct {
+	Example *LinkedExample
+	Reason  string
+}
+
+// String returns the pruned example with the reason
+func (p *Pruned) String() string {
+	return displayName(p.Example) + ": " + p.Reason
+}
+
+// pruneReasons returns the reasons of removing the examples matching the prune patterns or removed by the tags
+func (l *Linker) pruneReasons(index map[string]*LinkedExample, examples []*LinkedExample) map[*LinkedExample]string {
+	var reasons = map[*LinkedExample]string{}
+	var tagged map[*LinkedExample]bool
+	if len(l.runTags) > 0 {
+		tagged = runTagged(l.runTags, index, examples)
+	}
+	for _, e := range examples {
+		if p := l.prune.Match(e.Dir); p != nil {
+			reasons[e] = fmt.Sprintf("matches %v", p)
+			continue
+		}
+		if tag := findTag(e.Tags, l.skipTags); tag != "" {
+			reasons[e] = fmt.Sprintf("has skipped tag %v", tag)
+			continue
+		}
+		if tagged != nil && !tagged[e] {
+			reasons[e] = fmt.Sprintf("has none of tags %v", strings.Join(l.runTags, ", "))
+		}
+	}
+	return reasons
+}
+
+// runTagged returns the examples having any of the tags with the examples they include and the examples they need:
+// the examples including them and the examples they require
+func runTagged(tags []string, index map[string]*LinkedExample, examples []*LinkedExample) map[*LinkedExample]bool {
+	var result = map[*LinkedExample]bool{}
+	var include func(e *LinkedExample)
+	include = func(e *LinkedExample) {
+		if result[e] {
+			return
+		}
+		result[e] = true
+		for _, child := range e.Children {
+			include(child)
+		}
+	}
+	for _, e := range examples {
+		if findTag(e.Tags, tags) != "" {
+			include(e)
+		}
+	}
+
+	var needed = map[*LinkedExample]bool{}
+	var need func(e *LinkedExample)
+	need = func(e *LinkedExample) {
+		if needed[e] {
+			return
+		}
+		needed[e], result[e] = true, true
+		for _, parent := range e.Parents {
+			need(parent)
+		}
+		for _, require := range e.Requires {
+			if dep, ok := index[require]; ok {
+				need(dep)
+			}
+		}
+		for _, test := range e.RequiredTests {
+			need(test)
+		}
+	}
+	for _, e := range examples {
+		if result[e] {
+			need(e)
+		}
+	}
+	return result
+}
+
+// findTag returns the first of the tags found in the list or an empty string
+func findTag(tags, list []string) string {
+	for _, tag := range tags {
+		for _, item := range list {
+			if tag == item {
+				return tag
+			}
+		}
+	}
+	return ""
+}
+
+// prune removes the examples with the reasons together with their subtrees and all examples depending on them
+func prune(reasons map[*LinkedExample]string, index map[string]*LinkedExample, examples []*LinkedExample) (kept []*LinkedExample, pruned []*Pruned) {
+	if len(reasons) == 0 {
+		return examples, nil
+	}
+
+	// dependents are the examples that can be pruned with the example: its children and the examples requiring it or
+	// its tests. Only they are checked when the example is pruned, so large trees are pruned in linear time
+	var dependents = map[*LinkedExample][]*LinkedExample{}
+	var queue []*LinkedExample
+	for _, e := range examples {
+		for _, dep := range dependencies(index, e) {
+			dependents[dep] = append(dependents[dep], e)
+		}
+		if _, ok := reasons[e]; ok {
+			queue = append(queue, e)
+		}
+	}
+	for len(queue) > 0 {
+		e := queue[0]
+		queue = queue[1:]
+		for _, dependent := range dependents[e] {
+			if _, ok := reasons[dependent]; ok {
+				continue
+			}
+			if reason := pruneReason(dependent, index, reasons); reason != "" {
+				reasons[dependent] = reason
+				queue = append(queue, dependent)
+			}
+		}
+	}
+
+	for _, e := range examples {
+		if reason, ok := reasons[e]; ok {
+			pruned = append(pruned, &Pruned{Example: e, Reason: reason})
+			continue
+		}
+		e.Children = withoutPruned(e.Children, reasons)
+		e.Parents = withoutPruned(e.Parents, reasons)
+		kept = append(kept, e)
+	}
+	return kept, pruned
+}
+
+func pruneReason(e *LinkedExample, index map[string]*LinkedExample, reasons map[*LinkedExample]string) string {
+	if len(e.Parents) > 0 && len(withoutPruned(e.Parents, reasons)) == 0 {
+		return fmt.Sprintf("included only by pruned %v", displayName(e.Parents[0]))
+	}
+	for _, require := range e.Requires {
+		if dep, ok := index[require]; ok {
+			if _, ok := reasons[dep]; ok {
+				return fmt.Sprintf("requires pruned %v", displayName(dep))
+			}
+		}
+	}
+	for _, test := range e.RequiredTests {
+		if _, ok := reasons[test]; ok {
+			return fmt.Sprintf("requires pruned test %v", displayName(test))
+		}
+	}
+	return ""
+}
+
+func withoutPruned(examples []*LinkedExample, reasons map[*LinkedExample]string) []*LinkedExample {
+	var result []*LinkedExample
+	for _, e := range examples {
+		if _, ok := reasons[e]; !ok {
+			result = append(result, e)
+		}
+	}
+	return result
+}
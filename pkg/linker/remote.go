@@ -0,0 +1,114 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linker
+
+import (
+	"bytes"
+	"encoding/json"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/mod/module"
+)
+
+const (
+	remoteSeparator = "//"
+	latestVersion   = "latest"
+)
+
+// Remote represents a reference to an example located in another Go module.
+// Format: MODULE//PATH[@VERSION], e.g. github.com/networkservicemesh/deployments-k8s//examples/basic@v1.9.0
+type Remote struct {
+	Module  string
+	Path    string
+	Version string
+}
+
+// ParseRemote parses a reference to a remote example. Returns false if s is not a remote reference: the part before //
+// must be a valid module path and the part after it must be a path in the module
+func ParseRemote(s string) (*Remote, bool) {
+	if strings.Contains(s, "://") {
+		return nil, false
+	}
+	i := strings.Index(s, remoteSeparator)
+	if i <= 0 || module.CheckPath(s[:i]) != nil {
+		return nil, false
+	}
+	result := &Remote{
+		Module:  s[:i],
+		Path:    s[i+len(remoteSeparator):],
+		Version: latestVersion,
+	}
+	if j := strings.LastIndex(result.Path, "@"); j >= 0 {
+		result.Version = result.Path[j+1:]
+		result.Path = result.Path[:j]
+	}
+	result.Path = strings.Trim(path.Clean("/"+result.Path), "/")
+	if result.Path == "" || result.Version == "" {
+		return nil, false
+	}
+	return result, true
+}
+
+// IsRemote returns true if the dependency points to an example located in another Go module
+func IsRemote(dep string) bool {
+	_, ok := ParseRemote(dep)
+	return ok
+}
+
+// String returns the remote reference in MODULE//PATH@VERSION format
+func (r *Remote) String() string {
+	return r.Module + remoteSeparator + r.Path + "@" + r.Version
+}
+
+// Fetcher downloads a remote Go module and returns a local directory containing its sources
+type Fetcher interface {
+	Fetch(module, version string) (string, error)
+}
+
+// FetcherFunc is a function adapter for Fetcher
+type FetcherFunc func(module, version string) (string, error)
+
+// Fetch calls f(module, version)
+func (f FetcherFunc) Fetch(module, version string) (string, error) {
+	return f(module, version)
+}
+
+// GoModFetcher downloads remote modules into the Go module cache using `go mod download`
+var GoModFetcher Fetcher = FetcherFunc(func(module, version string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	// #nosec
+	cmd := exec.Command("go", "mod", "download", "-json", module+"@"+version)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", errors.Errorf("cannot download %v@%v: %v %v", module, version, err.Error(), strings.TrimSpace(stderr.String()))
+	}
+	var info struct {
+		Dir   string
+		Error string
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &info); err != nil {
+		return "", errors.Wrapf(err, "cannot download %v@%v", module, version)
+	}
+	if info.Error != "" {
+		return "", errors.Errorf("cannot download %v@%v: %v", module, version, info.Error)
+	}
+	return info.Dir, nil
+})
@@ -0,0 +1,182 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linker
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
+)
+
+// urlExamplesDir is the name prefix of examples downloaded by URL
+const urlExamplesDir = "remote"
+
+// IsURL returns true if the dependency is an http(s) URL of a markdown file. Only https URLs are fetched, http ones are
+// recognized to fail with a clear error instead of being linked as relative paths
+func IsURL(dep string) bool {
+	return strings.HasPrefix(dep, "https://") || strings.HasPrefix(dep, "http://")
+}
+
+// URLFetcher downloads a markdown file by URL
+type URLFetcher interface {
+	FetchURL(u string) ([]byte, error)
+}
+
+// URLFetcherFunc is a function adapter for URLFetcher
+type URLFetcherFunc func(u string) ([]byte, error)
+
+// FetchURL calls f(u)
+func (f URLFetcherFunc) FetchURL(u string) ([]byte, error) {
+	return f(u)
+}
+
+// URLCache downloads files over HTTP and keeps them in a local directory
+type URLCache struct {
+	// Dir is the cache directory
+	Dir string
+	// Offline disables downloading, only cached files are used
+	Offline bool
+	Client  *http.Client
+}
+
+// NewURLCache creates a URLCache storing files in dir
+func NewURLCache(dir string, offline bool) *URLCache {
+	return &URLCache{
+		Dir:     dir,
+		Offline: offline,
+		Client:  &http.Client{Timeout: time.Minute},
+	}
+}
+
+// DefaultURLCacheDir returns the directory used to cache downloaded examples by default
+func DefaultURLCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "gotestmd")
+}
+
+// FetchURL returns the cached file or downloads it if it's not cached yet
+func (c *URLCache) FetchURL(u string) ([]byte, error) {
+	sum := sha256.Sum256([]byte(u))
+	file := filepath.Join(c.Dir, hex.EncodeToString(sum[:])+".md")
+	if content, err := os.ReadFile(filepath.Clean(file)); err == nil {
+		return content, nil
+	}
+	if c.Offline {
+		return nil, errors.Errorf("%v is not cached in %v and offline mode is enabled", u, c.Dir)
+	}
+	resp, err := c.Client.Get(u)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot download %v", u)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("cannot download %v: %v", u, resp.Status)
+	}
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot download %v", u)
+	}
+	if err := os.MkdirAll(c.Dir, os.ModePerm); err != nil {
+		return nil, errors.Wrapf(err, "cannot create cache dir %v", c.Dir)
+	}
+	if err := os.WriteFile(file, content, 0o600); err != nil {
+		return nil, errors.Wrapf(err, "cannot cache %v", u)
+	}
+	return content, nil
+}
+
+// urlName returns the example name for the URL: remote/HOST/PATH without the extension of the file
+func urlName(u *url.URL) string {
+	pieces := []string{urlExamplesDir, u.Host}
+	pieces = append(pieces, strings.Split(strings.Trim(strings.TrimSuffix(u.Path, path.Ext(u.Path)), "/"), "/")...)
+	for i := range pieces {
+		pieces[i] = strings.ToLower(nameRegex.ReplaceAllString(pieces[i], "_"))
+		// Pieces are used as package names that can't start with a digit
+		if pieces[i] != "" && pieces[i][0] >= '0' && pieces[i][0] <= '9' {
+			pieces[i] = "x" + pieces[i]
+		}
+	}
+	return path.Join(pieces...)
+}
+
+// fetchURLs downloads and parses markdown files referenced by URL in Requires. The links are replaced with names of the examples
+func (l *Linker) fetchURLs(index map[string]*LinkedExample, examples []*LinkedExample) ([]*LinkedExample, error) {
+	var result []*LinkedExample
+	var p = parser.New()
+	for _, e := range examples {
+		for i, require := range e.Requires {
+			if !IsURL(require) {
+				continue
+			}
+			u, err := url.Parse(require)
+			if err != nil {
+				return nil, errors.Errorf("invalid url %v required by %v: %v", require, displayName(e), err.Error())
+			}
+			if u.Scheme != "https" {
+				return nil, errors.Errorf("insecure url %v required by %v: only https urls are supported", require, displayName(e))
+			}
+			u.Fragment = ""
+			name := urlName(u)
+			e.Requires[i] = name
+			if other := index[name]; other != nil {
+				if other.Source == u.String() {
+					continue
+				}
+				return nil, errors.Errorf("url %v required by %v has the same name %v as %v", require, displayName(e), name, other.Source)
+			}
+			if l.urlFetcher == nil {
+				return nil, errors.Errorf("cannot fetch %v required by %v: downloading is disabled", require, displayName(e))
+			}
+			content, err := l.urlFetcher.FetchURL(u.String())
+			if err != nil {
+				return nil, errors.Errorf("cannot fetch %v required by %v: %v", require, displayName(e), err.Error())
+			}
+			urlExample, err := p.Parse(bytes.NewReader(content))
+			if err != nil {
+				return nil, errors.Errorf("cannot parse %v required by %v: %v", require, displayName(e), err.Error())
+			}
+			// Relative links of the downloaded file point outside the input tree
+			urlExample.Includes = nil
+			urlExample.Requires = nil
+			urlExample.OptionalRequires = nil
+			urlExample.Dir = l.root
+			urlExample.Source = u.String()
+			linkedExample := &LinkedExample{
+				Example: urlExample,
+				Name:    name,
+			}
+			index[name] = linkedExample
+			result = append(result, linkedExample)
+		}
+	}
+	return result, nil
+}
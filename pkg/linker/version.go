@@ -0,0 +1,164 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linker
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// operators are ordered so that longer operators are matched first
+var operators = []string{">=", "<=", "!=", "==", ">", "<", "="}
+
+// Constraint is a requirement to the version of another example: spire >= 1.5, < 2
+type Constraint struct {
+	ID     string
+	Ranges []Range
+}
+
+// Range is a single comparison of a version: >= 1.5
+type Range struct {
+	Op      string
+	Version []int
+}
+
+// ParseConstraint parses a constraint in the ID OP VERSION[, OP VERSION...] format
+func ParseConstraint(s string) (*Constraint, error) {
+	fields := strings.Fields(s)
+	if len(fields) < 2 {
+		return nil, errors.Errorf("invalid constraint %q: expected ID OP VERSION", s)
+	}
+	result := &Constraint{ID: fields[0]}
+	for _, part := range strings.Split(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(s), fields[0])), ",") {
+		part = strings.TrimSpace(part)
+		var op string
+		for _, o := range operators {
+			if strings.HasPrefix(part, o) {
+				op = o
+				break
+			}
+		}
+		if op == "" {
+			return nil, errors.Errorf("invalid constraint %q: unknown operator in %q", s, part)
+		}
+		version, err := ParseVersion(strings.TrimSpace(strings.TrimPrefix(part, op)))
+		if err != nil {
+			return nil, errors.Errorf("invalid constraint %q: %v", s, err.Error())
+		}
+		result.Ranges = append(result.Ranges, Range{Op: op, Version: version})
+	}
+	return result, nil
+}
+
+// Match returns true if the version satisfies all ranges of the constraint
+func (c *Constraint) Match(version []int) bool {
+	for _, r := range c.Ranges {
+		if !r.Match(version) {
+			return false
+		}
+	}
+	return true
+}
+
+// Match returns true if the version satisfies the range
+func (r Range) Match(version []int) bool {
+	cmp := CompareVersions(version, r.Version)
+	switch r.Op {
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case "<":
+		return cmp < 0
+	case "!=":
+		return cmp != 0
+	default:
+		return cmp == 0
+	}
+}
+
+// ParseVersion parses a dotted numeric version with an optional v prefix: v1.5.0
+func ParseVersion(s string) ([]int, error) {
+	if s == "" {
+		return nil, errors.New("empty version")
+	}
+	var result []int
+	for _, part := range strings.Split(strings.TrimPrefix(s, "v"), ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, errors.Errorf("invalid version %q", s)
+		}
+		result = append(result, n)
+	}
+	return result, nil
+}
+
+// CompareVersions returns -1, 0 or 1 if a is less than, equal to or greater than b. Missing components are zeros
+func CompareVersions(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// checkVersions validates version constraints declared in the front matter of the examples
+func checkVersions(ids map[string]*LinkedExample, examples []*LinkedExample) error {
+	for _, e := range examples {
+		if e.Version == "" {
+			continue
+		}
+		if _, err := ParseVersion(e.Version); err != nil {
+			return errors.Errorf("%v (%v): %v", displayName(e), readme(e), err.Error())
+		}
+	}
+	for _, e := range examples {
+		for _, s := range e.Constraints {
+			c, err := ParseConstraint(s)
+			if err != nil {
+				return errors.Errorf("%v (%v): %v", displayName(e), readme(e), err.Error())
+			}
+			target, ok := ids[c.ID]
+			if !ok {
+				return errors.Errorf("%v (%v) requires %v, but no example has id %v", displayName(e), readme(e), s, c.ID)
+			}
+			if target.Version == "" {
+				return errors.Errorf("%v (%v) requires %v, but %v (%v) has no version", displayName(e), readme(e), s, displayName(target), readme(target))
+			}
+			version, _ := ParseVersion(target.Version)
+			if !c.Match(version) {
+				return errors.Errorf("%v (%v) requires %v, but %v (%v) has version %v", displayName(e), readme(e), s, displayName(target), readme(target), target.Version)
+			}
+		}
+	}
+	return nil
+}
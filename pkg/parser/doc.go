@@ -0,0 +1,32 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package parser reads gotestmd markdown documents.
+//
+// A document is parsed into an Example with the bash blocks of its Run, Cleanup, Before each and After each sections,
+// the links of its Includes and Requires sections and the directives of its YAML front matter (FrontMatter).
+// Blocks and Directives return the bash blocks and the front matter keys with their positions in the document.
+// A malformed document results in ErrorList with the Position of each problem:
+//
+//	example, err := parser.New().ParseFile("examples/basic/README.md")
+//	if errs, ok := err.(parser.ErrorList); ok {
+//		for _, e := range errs {
+//			fmt.Println(e.Pos, e.Msg)
+//		}
+//	}
+//
+// Links are returned as written in the document. Resolving them to other examples is done by the linker.
+package parser
@@ -0,0 +1,105 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Position represents a location in a markdown file
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+// String returns the position in file:line:column format
+func (p Position) String() string {
+	var sb strings.Builder
+	if p.File != "" {
+		sb.WriteString(p.File)
+		sb.WriteString(":")
+	}
+	_, _ = fmt.Fprintf(&sb, "%d", p.Line)
+	if p.Column > 0 {
+		_, _ = fmt.Fprintf(&sb, ":%d", p.Column)
+	}
+	return sb.String()
+}
+
+// Error represents a problem found at a specific position of a markdown file
+type Error struct {
+	Pos Position
+	Msg string
+}
+
+// Error returns the error in file:line:column: message format
+func (e *Error) Error() string {
+	return e.Pos.String() + ": " + e.Msg
+}
+
+// ErrorList is a list of parse errors
+type ErrorList []*Error
+
+// Add appends a new error to the list
+func (l *ErrorList) Add(pos Position, msg string) {
+	*l = append(*l, &Error{Pos: pos, Msg: msg})
+}
+
+// Sort sorts the list by file, line and column keeping the order of errors at the same position
+func (l ErrorList) Sort() {
+	sort.SliceStable(l, func(i, j int) bool {
+		a, b := l[i].Pos, l[j].Pos
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
+
+// Err returns nil for an empty list or the list sorted by position otherwise
+func (l ErrorList) Err() error {
+	if len(l) == 0 {
+		return nil
+	}
+	l.Sort()
+	return l
+}
+
+// Error returns all errors of the list separated by new lines
+func (l ErrorList) Error() string {
+	var lines = make([]string, 0, len(l))
+	for _, err := range l {
+		lines = append(lines, err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// position converts a byte offset of the source into a line and column
+func position(source string, offset int) Position {
+	if offset > len(source) {
+		offset = len(source)
+	}
+	line := strings.Count(source[:offset], "\n") + 1
+	column := offset - strings.LastIndex(source[:offset], "\n")
+	return Position{Line: line, Column: column}
+}
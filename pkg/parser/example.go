@@ -0,0 +1,137 @@
+// Copyright (c) 2020-2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+// Example represents a markdown example. Contains all needed for generating suites content.
+type Example struct {
+	FrontMatter
+	Includes []string
+	Requires []string
+	// OptionalRequires are used only if they exist in the input tree
+	OptionalRequires []string
+	Run              []string
+	Cleanup          []string
+	// BeforeEach and AfterEach run before and after each test of the example
+	BeforeEach []string
+	AfterEach  []string
+	Dir        string
+	// Sections are the lines of the sections found in the source by their names, e.g. Run
+	Sections map[string]int
+	// Source is the file or URL the example is parsed from
+	Source string
+	// Hash is the sha256 of the source content with normalized line endings in the sha256:HEX format
+	Hash string
+	// runLines, cleanupLines, beforeEachLines and afterEachLines are the lines of the first commands of the blocks in
+	// the source, shells are the shells of the blocks with the shell=NAME attribute by the lines. Blocks returns them
+	// with the scripts
+	runLines        []int
+	cleanupLines    []int
+	beforeEachLines []int
+	afterEachLines  []int
+	shells          map[int]string
+	// directives are the top-level keys of the front matter
+	directives []*Directive
+}
+
+// Sections of a document
+const (
+	SectionRun        = "Run"
+	SectionCleanup    = "Cleanup"
+	SectionBeforeEach = "Before each"
+	SectionAfterEach  = "After each"
+	SectionIncludes   = "Includes"
+	SectionRequires   = "Requires"
+)
+
+// Block is a bash block of a section of an example
+type Block struct {
+	// Section is the name of the section, e.g. Run
+	Section string
+	// Pos is the position of the first command of the block. Its line is zero if it's unknown
+	Pos    Position
+	Script string
+	// Shell is the shell set by the shell=NAME attribute of the block, e.g. ```bash shell=sh. The block is run by bash
+	// if it's empty
+	Shell string
+}
+
+// Directive is a top-level key of the front matter of an example, e.g. retry
+type Directive struct {
+	Key string
+	// Pos is the position of the key
+	Pos Position
+	// Known is false for the keys not supported by gotestmd, usually typos
+	Known bool
+}
+
+// Blocks returns the bash blocks of the example in the order they run: Before each, Run, After each and Cleanup
+func (e *Example) Blocks() []*Block {
+	var result []*Block
+	result = e.appendBlocks(result, SectionBeforeEach, e.BeforeEach, e.beforeEachLines)
+	result = e.appendBlocks(result, SectionRun, e.Run, e.runLines)
+	result = e.appendBlocks(result, SectionAfterEach, e.AfterEach, e.afterEachLines)
+	return e.appendBlocks(result, SectionCleanup, e.Cleanup, e.cleanupLines)
+}
+
+func (e *Example) appendBlocks(blocks []*Block, section string, scripts []string, lines []int) []*Block {
+	for i, script := range scripts {
+		block := &Block{Section: section, Pos: Position{File: e.Source}, Script: script}
+		if i < len(lines) {
+			block.Pos.Line = lines[i]
+			block.Shell = e.shells[lines[i]]
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// AddBlock appends the block to its section of the example, e.g. to build an example without parsing a document.
+// The file of its position is ignored, the blocks have the Source of the example. Blocks of other sections are ignored.
+// The shell of a block is kept only if its line is set
+func (e *Example) AddBlock(b *Block) {
+	var lines *[]int
+	switch b.Section {
+	case SectionRun:
+		e.Run, lines = append(e.Run, b.Script), &e.runLines
+	case SectionCleanup:
+		e.Cleanup, lines = append(e.Cleanup, b.Script), &e.cleanupLines
+	case SectionBeforeEach:
+		e.BeforeEach, lines = append(e.BeforeEach, b.Script), &e.beforeEachLines
+	case SectionAfterEach:
+		e.AfterEach, lines = append(e.AfterEach, b.Script), &e.afterEachLines
+	default:
+		return
+	}
+	*lines = append(*lines, b.Pos.Line)
+	if b.Shell != "" && b.Pos.Line != 0 {
+		if e.shells == nil {
+			e.shells = map[int]string{}
+		}
+		e.shells[b.Pos.Line] = b.Shell
+	}
+}
+
+// Directives returns the top-level keys of the front matter of the example in the order of the source
+func (e *Example) Directives() []*Directive {
+	var result []*Directive
+	for _, d := range e.directives {
+		directive := *d
+		directive.Pos.File = e.Source
+		result = append(result, &directive)
+	}
+	return result
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"go/build/constraint"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const frontMatterDelim = "---"
+
+// NonZeroExitCode is the exit-code of the examples whose commands are expected to fail with any code
+const NonZeroExitCode = "non-zero"
+
+var envNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+var artifactNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+var namespaceRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// FrontMatter represents the YAML block at the beginning of a markdown file:
+//
+//	---
+//	id: basic
+//	---
+type FrontMatter struct {
+	// ID is an alias of the example. Other examples can use it in links as @ID
+	ID string `yaml:"id"`
+	// Priority orders sibling suites: suites with lower priority run first
+	Priority int `yaml:"priority"`
+	// Parallel marks the example as safe to run in parallel with its siblings
+	Parallel bool `yaml:"parallel"`
+	// Timeout limits the time of the generated suite, e.g. 10m
+	Timeout time.Duration `yaml:"timeout"`
+	// CommandTimeout limits the time of each command of the generated suite, e.g. 2m
+	CommandTimeout time.Duration `yaml:"command-timeout"`
+	// Retry sets how the commands of Run sections are retried, e.g. for eventually consistent checks
+	Retry *Retry `yaml:"retry"`
+	// ExitCode is the exit code the commands of Run sections are expected to fail with, e.g. 2 or non-zero,
+	// so the example documents an error path
+	ExitCode string `yaml:"exit-code"`
+	// Env are the env variables set for the commands of the example by the runner, so blocks don't need to export them
+	Env map[string]string `yaml:"env"`
+	// Image is the image of the container running the commands of the example and of its tests, e.g. golang:1.20
+	Image string `yaml:"image"`
+	// Artifacts are the commands whose output is collected by name when a test of the example fails, e.g.
+	// pods: kubectl get pods -A
+	Artifacts map[string]string `yaml:"artifacts"`
+	// Isolate sets the state shared by the tests of the example that is checked and restored after each test, so a
+	// test leaking its state into its siblings fails
+	Isolate *Isolate `yaml:"isolate"`
+	// SkipUnlessEnv is an env variable that should be set to run the generated suite, e.g. E2E
+	SkipUnlessEnv string `yaml:"skip-unless-env"`
+	// SoftFail makes the generated test continue after a failed step and report all failed steps at the end
+	SoftFail bool `yaml:"soft-fail"`
+	// BuildTags are go:build constraints of the generated suite, e.g. integration
+	BuildTags []string `yaml:"build-tags"`
+	// Version is a version of the example, e.g. 1.5.0
+	Version string `yaml:"version"`
+	// Constraints are versions of other examples this example is compatible with, e.g. spire >= 1.5, < 2
+	Constraints []string `yaml:"requires"`
+	// Tags select the example with --run-tags and --skip-tags, e.g. slow
+	Tags []string `yaml:"tags"`
+}
+
+// Retry sets how the failed commands are retried
+//
+//	retry:
+//	  timeout: 5m
+//	  interval: 1s
+//	  backoff: 2
+//	  max-interval: 30s
+//	  until: Running
+type Retry struct {
+	// Timeout is the time the command is retried for
+	Timeout time.Duration `yaml:"timeout"`
+	// Interval is the pause between the attempts
+	Interval time.Duration `yaml:"interval"`
+	// Backoff multiplies the pause after each attempt up to MaxInterval
+	Backoff     float64       `yaml:"backoff"`
+	MaxInterval time.Duration `yaml:"max-interval"`
+	// Until is a regex the stdout of the command should match to succeed
+	Until string `yaml:"until"`
+}
+
+// Isolate sets the state checked and restored after each test
+//
+//	isolate:
+//	  env: true
+//	  namespaces: [nsm-system, ns-basic]
+type Isolate struct {
+	// Env checks the env of the test process inherited by the runners of the next tests
+	Env bool `yaml:"env"`
+	// Namespaces are the Kubernetes namespaces the tests shouldn't leave behind
+	Namespaces []string `yaml:"namespaces"`
+}
+
+// validate returns the problems of the retry settings
+func (r *Retry) validate() []string {
+	var result []string
+	if r.Timeout < 0 || r.Interval < 0 || r.MaxInterval < 0 {
+		result = append(result, "negative retry duration")
+	}
+	if r.Backoff != 0 && r.Backoff < 1 {
+		result = append(result, "invalid retry backoff "+strconv.FormatFloat(r.Backoff, 'g', -1, 64)+", expected at least 1")
+	}
+	if _, err := regexp.Compile(r.Until); err != nil {
+		result = append(result, "invalid retry until: "+err.Error())
+	}
+	return result
+}
+
+// SortedKeys returns the sorted keys of the env variables or the artifacts
+func SortedKeys(m map[string]string) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// parseFrontMatter parses the front matter with its directives and replaces it with empty lines, so positions in the
+// source stay the same
+func parseFrontMatter(source string, errs *ErrorList) (FrontMatter, []*Directive, string) {
+	var result FrontMatter
+
+	if !strings.HasPrefix(source, frontMatterDelim+"\n") {
+		return result, nil, source
+	}
+	end := strings.Index(source[len(frontMatterDelim):], "\n"+frontMatterDelim)
+	if end < 0 {
+		errs.Add(Position{Line: 1, Column: 1}, "unterminated front matter")
+		return result, nil, source
+	}
+	end += len(frontMatterDelim)
+	content := source[len(frontMatterDelim):end]
+	end += len("\n" + frontMatterDelim)
+
+	if err := yaml.Unmarshal([]byte(content), &result); err != nil {
+		errs.Add(Position{Line: 1, Column: 1}, "invalid front matter: "+err.Error())
+	}
+	directives := parseDirectives(content)
+	if result.SkipUnlessEnv != "" && !envNameRegex.MatchString(result.SkipUnlessEnv) {
+		errs.Add(Position{Line: 1, Column: 1}, "invalid env variable "+result.SkipUnlessEnv)
+	}
+	for _, key := range SortedKeys(result.Env) {
+		if !envNameRegex.MatchString(key) {
+			errs.Add(Position{Line: 1, Column: 1}, "invalid env variable "+key)
+		}
+	}
+	for _, name := range SortedKeys(result.Artifacts) {
+		if !artifactNameRegex.MatchString(name) {
+			errs.Add(Position{Line: 1, Column: 1}, "invalid artifact name "+strconv.Quote(name))
+		}
+	}
+	for _, tag := range result.Tags {
+		if strings.TrimSpace(tag) == "" || strings.Contains(tag, ",") {
+			errs.Add(Position{Line: 1, Column: 1}, "invalid tag "+strconv.Quote(tag))
+		}
+	}
+	if result.Isolate != nil {
+		for _, ns := range result.Isolate.Namespaces {
+			if !namespaceRegex.MatchString(ns) {
+				errs.Add(Position{Line: 1, Column: 1}, "invalid namespace "+strconv.Quote(ns)+" in isolate")
+			}
+		}
+	}
+	if result.Retry != nil {
+		for _, msg := range result.Retry.validate() {
+			errs.Add(Position{Line: 1, Column: 1}, msg)
+		}
+	}
+	if result.ExitCode != "" && result.ExitCode != NonZeroExitCode {
+		if code, err := strconv.Atoi(result.ExitCode); err != nil || code < 0 || code > 255 {
+			errs.Add(Position{Line: 1, Column: 1}, "invalid exit code "+strconv.Quote(result.ExitCode)+", expected a number or "+NonZeroExitCode)
+		}
+	}
+	for _, tag := range result.BuildTags {
+		if _, err := constraint.Parse("//go:build " + tag); err != nil {
+			errs.Add(Position{Line: 1, Column: 1}, "invalid build tag "+tag+": "+err.Error())
+		}
+	}
+
+	return result, directives, strings.Repeat("\n", strings.Count(source[:end], "\n")) + source[end:]
+}
+
+// parseDirectives returns the top-level keys of the front matter. Keys that don't match any field of FrontMatter aren't known.
+// The content starts at the first line of the source, so the lines are the same
+func parseDirectives(content string) []*Directive {
+	var known = map[string]bool{}
+	var t = reflect.TypeOf(FrontMatter{})
+	for i := 0; i < t.NumField(); i++ {
+		known[strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]] = true
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &node); err != nil || len(node.Content) == 0 || node.Content[0].Kind != yaml.MappingNode {
+		return nil
+	}
+	var result []*Directive
+	keys := node.Content[0].Content
+	for i := 0; i < len(keys); i += 2 {
+		result = append(result, &Directive{Key: keys[i].Value, Pos: Position{Line: keys[i].Line, Column: keys[i].Column}, Known: known[keys[i].Value]})
+	}
+	return result
+}
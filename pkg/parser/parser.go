@@ -0,0 +1,250 @@
+// Copyright (c) 2020-2021 Doc.ai and/or its affiliates.
+//
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Parser is markdown file reader. It's safe for concurrent use
+type Parser struct {
+	linkRegex *regexp.Regexp
+	// shellRegex matches the shell=NAME attribute following ```bash
+	shellRegex *regexp.Regexp
+}
+
+// New creates new Parser instance
+func New() *Parser {
+	return &Parser{
+		linkRegex:  regexp.MustCompile(`\[.*\]\(.*\)`),
+		shellRegex: regexp.MustCompile("^[ \t]+shell=([^\\s`]+)[ \t]*\n"),
+	}
+}
+
+// ParseFile reads file. Parse errors are returned as ErrorList with positions pointing to the file
+func (p *Parser) ParseFile(filePath string) (*Example, error) {
+	f, err := os.Open(filepath.Clean(filePath))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	return p.ParseNamed(f, filePath)
+}
+
+// ParseNamed reads r as the file at filePath, so the positions of errors and the dir of the example point to it
+func (p *Parser) ParseNamed(r io.Reader, filePath string) (*Example, error) {
+	v, err := p.Parse(r)
+	if errs, ok := err.(ErrorList); ok {
+		for _, e := range errs {
+			e.Pos.File = filePath
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	v.Dir = filepath.Dir(filePath)
+	v.Source = filePath
+	return v, nil
+}
+
+// Parse reads io.Reader. Returns ErrorList if the document is malformed
+func (p *Parser) Parse(r io.Reader) (*Example, error) {
+	bytes, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	source := normalize(string(bytes))
+
+	var errs ErrorList
+	frontMatter, directives, source := parseFrontMatter(source, &errs)
+	shells := map[int]string{}
+
+	parseScript := func(section string) (blocks []string, lines []int) {
+		const (
+			scriptBegin = "```bash"
+			scriptEnd   = "```"
+		)
+
+		s, offset := parseSection(section, source)
+
+		for start := strings.Index(s, scriptBegin); start >= 0; start = strings.Index(s, scriptBegin) {
+			blockStart := offset + start
+			start += len(scriptBegin)
+			var shell string
+			if m := p.shellRegex.FindStringSubmatchIndex(s[start:]); m != nil {
+				shell = s[start+m[2] : start+m[3]]
+				start += m[1] - 1
+			}
+
+			end := strings.Index(s[start:], scriptEnd)
+			if end < 0 {
+				errs.Add(position(source, blockStart), "unterminated bash block in "+sectionName(section)+" section")
+				break
+			}
+			end += start
+
+			block := s[start:end]
+			blocks = append(blocks, strings.TrimSpace(block))
+			lines = append(lines, position(source, offset+start+len(block)-len(strings.TrimLeftFunc(block, unicode.IsSpace))).Line)
+			if shell != "" {
+				shells[lines[len(lines)-1]] = shell
+			}
+			offset += end + len(scriptEnd)
+			s = s[end+len(scriptEnd):]
+		}
+		return blocks, lines
+	}
+
+	parseLinks := func(section string) []link {
+		s, offset := parseSection(section, source)
+		if offset < 0 {
+			return nil
+		}
+
+		// Sections without links are reported by lint
+		var result []link
+		for _, loc := range p.linkRegex.FindAllStringIndex(s, -1) {
+			l := parseLink(s[loc[0]:loc[1]])
+			if l.target == "" {
+				errs.Add(position(source, offset+loc[0]), "empty link target in "+sectionName(section)+" section")
+				continue
+			}
+			result = append(result, l)
+		}
+		return result
+	}
+
+	// Line endings are normalized, so the hash doesn't depend on the checkout settings
+	sum := sha256.Sum256([]byte(normalize(string(bytes))))
+	result := &Example{
+		FrontMatter: frontMatter,
+		directives:  directives,
+		Hash:        "sha256:" + hex.EncodeToString(sum[:]),
+	}
+	result.Sections = map[string]int{}
+	for _, section := range []string{SectionRun, SectionCleanup, SectionBeforeEach, SectionAfterEach, SectionIncludes, SectionRequires} {
+		if _, offset := parseSection("# "+section, source); offset >= 0 {
+			result.Sections[section] = position(source, offset).Line
+		}
+	}
+	result.Cleanup, result.cleanupLines = parseScript("# " + SectionCleanup)
+	result.Run, result.runLines = parseScript("# " + SectionRun)
+	result.BeforeEach, result.beforeEachLines = parseScript("# " + SectionBeforeEach)
+	result.AfterEach, result.afterEachLines = parseScript("# " + SectionAfterEach)
+	for _, l := range parseLinks("# " + SectionIncludes) {
+		result.Includes = append(result.Includes, l.target)
+	}
+	for _, l := range parseLinks("# " + SectionRequires) {
+		if l.title == optionalTitle {
+			result.OptionalRequires = append(result.OptionalRequires, l.target)
+			continue
+		}
+		result.Requires = append(result.Requires, l.target)
+	}
+	if len(shells) > 0 {
+		result.shells = shells
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// normalize drops a leading UTF-8 BOM and converts CRLF and CR line endings to LF
+func normalize(s string) string {
+	s = strings.TrimPrefix(s, "\uFEFF")
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ReplaceAll(s, "\r", "\n")
+}
+
+// optionalTitle marks a Requires link as optional: [Name](../path "optional")
+const optionalTitle = "optional"
+
+type link struct {
+	target string
+	title  string
+}
+
+// parseLink parses [text](target "title") markdown link
+func parseLink(s string) link {
+	start := strings.IndexRune(s, '(') + len("(")
+	end := strings.IndexRune(s, ')')
+	fields := strings.SplitN(strings.TrimSpace(s[start:end]), " ", 2)
+	result := link{target: fields[0]}
+	if len(fields) == 2 {
+		result.title = strings.Trim(strings.TrimSpace(fields[1]), `"'`)
+	}
+	return result
+}
+
+func sectionName(section string) string {
+	return strings.TrimSpace(strings.TrimLeft(section, "#"))
+}
+
+// parseSection returns the content of the section and its offset in s. Offset is -1 if there is no such section.
+// The section ends at the next heading starting a line outside of code blocks, so # in links, text and comments of
+// the blocks is a part of the section. The heading may follow the section right away, i.e. the section may be empty
+func parseSection(section, s string) (string, int) {
+	const sectionEnd = "\n#"
+
+	start := strings.Index(s, section)
+	if start == -1 {
+		return "", -1
+	}
+
+	s = s[start+len(section):]
+	start += len(section)
+
+	var end, offset int
+	for blockEnd := 0; ; offset += blockEnd {
+		if end = strings.Index(s[offset:], sectionEnd); end < 0 {
+			return s, start
+		}
+
+		if blockEnd = skipBlocks(s[offset:], end); blockEnd <= end {
+			break
+		}
+	}
+	return s[:end+offset], start
+}
+
+func skipBlocks(s string, sectionEnd int) (end int) {
+	const blockDelim = "```"
+
+	for start := strings.Index(s[end:], blockDelim); start > 0; start = strings.Index(s[end:], blockDelim) {
+		if start += end + len(blockDelim); start > sectionEnd {
+			return end
+		}
+
+		if end = strings.Index(s[start:], blockDelim); end < 0 {
+			return len(s)
+		}
+		end += start + len(blockDelim)
+	}
+	return end
+}
@@ -0,0 +1,382 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
+)
+
+const sample = `# Example
+
+## Requires
+
+- [Producer](../Producer)
+
+## Run
+
+` + "```bash" + `
+echo "first"
+echo "second"
+` + "```" + `
+
+## Cleanup
+
+` + "```bash" + `
+echo "cleanup"
+` + "```" + `
+`
+
+func TestParseLF(t *testing.T) {
+	ex, err := parser.New().Parse(strings.NewReader(sample))
+	require.NoError(t, err)
+	require.Equal(t, []string{"echo \"first\"\necho \"second\""}, ex.Run)
+	require.Equal(t, []string{"echo \"cleanup\""}, ex.Cleanup)
+	require.Equal(t, []string{"../Producer"}, ex.Requires)
+	require.Equal(t, []*parser.Block{
+		{Section: parser.SectionRun, Pos: parser.Position{Line: 10}, Script: "echo \"first\"\necho \"second\""},
+		{Section: parser.SectionCleanup, Pos: parser.Position{Line: 17}, Script: "echo \"cleanup\""},
+	}, ex.Blocks())
+}
+
+func TestParseCRLF(t *testing.T) {
+	expected, err := parser.New().Parse(strings.NewReader(sample))
+	require.NoError(t, err)
+
+	actual, err := parser.New().Parse(strings.NewReader(strings.ReplaceAll(sample, "\n", "\r\n")))
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func TestParseBOM(t *testing.T) {
+	expected, err := parser.New().Parse(strings.NewReader(sample))
+	require.NoError(t, err)
+
+	actual, err := parser.New().Parse(strings.NewReader("\uFEFF" + strings.ReplaceAll(sample, "\n", "\r\n")))
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func TestParseMixedLineEndings(t *testing.T) {
+	expected, err := parser.New().Parse(strings.NewReader(sample))
+	require.NoError(t, err)
+
+	lines := strings.Split(sample, "\n")
+	var sb strings.Builder
+	for i, line := range lines {
+		sb.WriteString(line)
+		if i+1 == len(lines) {
+			break
+		}
+		switch i % 3 {
+		case 0:
+			sb.WriteString("\r\n")
+		case 1:
+			sb.WriteString("\r")
+		default:
+			sb.WriteString("\n")
+		}
+	}
+
+	actual, err := parser.New().Parse(strings.NewReader(sb.String()))
+	require.NoError(t, err)
+	// A lone CR before CRLF merges two line breaks, so the content, its hash and lines of blocks differ
+	require.Equal(t, expected.FrontMatter, actual.FrontMatter)
+	require.Equal(t, expected.Requires, actual.Requires)
+	require.Equal(t, expected.Run, actual.Run)
+	require.Equal(t, expected.Cleanup, actual.Cleanup)
+	for _, block := range actual.Run {
+		require.NotContains(t, block, "\r")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	source := `# Broken
+
+## Requires
+
+Nothing is required here.
+
+## Includes
+
+- [Empty]()
+
+## Run
+
+` + "```bash" + `
+echo "never closed"
+`
+
+	_, err := parser.New().Parse(strings.NewReader(source))
+	require.Error(t, err)
+
+	// Errors are sorted by position, Requires without links is reported by lint
+	errs, ok := err.(parser.ErrorList)
+	require.True(t, ok)
+	require.Len(t, errs, 2)
+	require.Equal(t, "9:3: empty link target in Includes section", errs[0].Error())
+	require.Equal(t, "13:1: unterminated bash block in Run section", errs[1].Error())
+}
+
+func TestParseEmptyLinkSections(t *testing.T) {
+	example, err := parser.New().Parse(strings.NewReader("# A\n\n## Requires\n\nNothing is required.\n\n## Includes\n\n## Run\n\n```bash\necho a\n```\n"))
+	require.NoError(t, err)
+	require.Empty(t, example.Requires)
+	require.Empty(t, example.Includes)
+	require.Equal(t, 3, example.Sections[parser.SectionRequires])
+}
+
+func TestParseFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "README.md")
+	require.NoError(t, os.WriteFile(file, []byte("# Run\n\n```bash\necho\n"), 0o600))
+
+	_, err := parser.New().ParseFile(file)
+	require.EqualError(t, err, file+":3:1: unterminated bash block in Run section")
+}
+
+func TestParseOptionalRequires(t *testing.T) {
+	source := `# Requires
+
+- [Producer](../Producer)
+- [Observability](../Observability "optional")
+`
+
+	ex, err := parser.New().Parse(strings.NewReader(source))
+	require.NoError(t, err)
+	require.Equal(t, []string{"../Producer"}, ex.Requires)
+	require.Equal(t, []string{"../Observability"}, ex.OptionalRequires)
+}
+
+func TestParseLinkWithFragment(t *testing.T) {
+	source := `# Requires
+
+- [Kernel2Kernel](../basic#Kernel2Kernel)
+
+## Run
+
+` + "```bash" + `
+echo "run"
+` + "```" + `
+## Cleanup
+`
+
+	ex, err := parser.New().Parse(strings.NewReader(source))
+	require.NoError(t, err)
+	require.Equal(t, []string{"../basic#Kernel2Kernel"}, ex.Requires)
+	require.Equal(t, []string{`echo "run"`}, ex.Run)
+}
+
+func TestParseSectionEnd(t *testing.T) {
+	// Sections end at the next heading at the start of a line outside of bash blocks
+	source := `## Includes
+## Run
+
+See issue #42.
+
+` + "```bash" + `
+# a comment
+echo "run"
+` + "```" + `
+## Cleanup
+
+` + "```bash" + `
+echo "cleanup"
+` + "```" + `
+`
+
+	ex, err := parser.New().Parse(strings.NewReader(source))
+	require.NoError(t, err)
+	require.Empty(t, ex.Includes)
+	require.Equal(t, []string{"# a comment\necho \"run\""}, ex.Run)
+	require.Equal(t, []string{`echo "cleanup"`}, ex.Cleanup)
+}
+
+func TestParseFrontMatter(t *testing.T) {
+	source := `---
+id: basic
+priority: 10
+# Requires
+---
+# Requires
+
+- [Producer](@producer)
+
+# Run
+` + "```bash\necho basic\n```\n"
+
+	ex, err := parser.New().Parse(strings.NewReader(source))
+	require.NoError(t, err)
+	require.Equal(t, "basic", ex.ID)
+	require.Equal(t, 10, ex.Priority)
+	require.Equal(t, []string{"@producer"}, ex.Requires)
+	require.Equal(t, []string{"echo basic"}, ex.Run)
+
+	_, err = parser.New().Parse(strings.NewReader("---\nid: [basic\n---\n"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "1:1: invalid front matter")
+
+	ex, err = parser.New().Parse(strings.NewReader("---\nbuild-tags: [integration, linux || darwin]\n---\n"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"integration", "linux || darwin"}, ex.BuildTags)
+
+	_, err = parser.New().Parse(strings.NewReader("---\nbuild-tags: [\"integration &&\"]\n---\n"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "1:1: invalid build tag integration &&")
+
+	_, err = parser.New().Parse(strings.NewReader("---\nskip-unless-env: E2E TESTS\n---\n"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "1:1: invalid env variable E2E TESTS")
+
+	ex, err = parser.New().Parse(strings.NewReader("---\ntags: [smoke, slow]\n---\n"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"smoke", "slow"}, ex.Tags)
+
+	_, err = parser.New().Parse(strings.NewReader("---\ntags: [\"smoke,slow\"]\n---\n"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `1:1: invalid tag "smoke,slow"`)
+
+	ex, err = parser.New().Parse(strings.NewReader("---\nretry:\n  timeout: 5m\n  interval: 1s\n  backoff: 1.5\n  max-interval: 30s\n  until: Running\n---\n"))
+	require.NoError(t, err)
+	require.Equal(t, &parser.Retry{Timeout: 5 * time.Minute, Interval: time.Second, Backoff: 1.5, MaxInterval: 30 * time.Second, Until: "Running"}, ex.Retry)
+
+	_, err = parser.New().Parse(strings.NewReader("---\nretry:\n  backoff: 0.5\n  until: \"(\"\n---\n"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "1:1: invalid retry backoff 0.5, expected at least 1")
+	require.Contains(t, err.Error(), "1:1: invalid retry until: error parsing regexp: missing closing ): `(`")
+
+	ex, err = parser.New().Parse(strings.NewReader("---\nexit-code: 2\n---\n"))
+	require.NoError(t, err)
+	require.Equal(t, "2", ex.ExitCode)
+
+	ex, err = parser.New().Parse(strings.NewReader("---\nexit-code: non-zero\n---\n"))
+	require.NoError(t, err)
+	require.Equal(t, parser.NonZeroExitCode, ex.ExitCode)
+
+	ex, err = parser.New().Parse(strings.NewReader("---\nenv:\n  NAMESPACE: ns-1\n  REPLICAS: 2\n---\n"))
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"NAMESPACE": "ns-1", "REPLICAS": "2"}, ex.Env)
+
+	_, err = parser.New().Parse(strings.NewReader("---\nenv:\n  NAME SPACE: ns-1\n---\n"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "1:1: invalid env variable NAME SPACE")
+
+	ex, err = parser.New().Parse(strings.NewReader("---\nimage: golang:1.20\n---\n"))
+	require.NoError(t, err)
+	require.Equal(t, "golang:1.20", ex.Image)
+
+	ex, err = parser.New().Parse(strings.NewReader("---\nartifacts:\n  pods: kubectl get pods -A\n---\n"))
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"pods": "kubectl get pods -A"}, ex.Artifacts)
+
+	_, err = parser.New().Parse(strings.NewReader("---\nartifacts:\n  ../pods: kubectl get pods -A\n---\n"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `1:1: invalid artifact name "../pods"`)
+
+	ex, err = parser.New().Parse(strings.NewReader("---\nisolate:\n  env: true\n  namespaces: [nsm-system, ns-basic]\n---\n"))
+	require.NoError(t, err)
+	require.Equal(t, &parser.Isolate{Env: true, Namespaces: []string{"nsm-system", "ns-basic"}}, ex.Isolate)
+
+	_, err = parser.New().Parse(strings.NewReader("---\nisolate:\n  namespaces: [NS_1]\n---\n"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `1:1: invalid namespace "NS_1" in isolate`)
+
+	_, err = parser.New().Parse(strings.NewReader("---\nexit-code: any\n---\n"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `1:1: invalid exit code "any", expected a number or non-zero`)
+}
+
+func TestParseFileSource(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "README.md")
+	require.NoError(t, os.WriteFile(file, []byte("# Run\n```bash\necho a\n```\n"), 0o600))
+
+	ex, err := parser.New().ParseFile(file)
+	require.NoError(t, err)
+	require.Equal(t, file, ex.Source)
+	require.Equal(t, "sha256:460454d3809b5fdbb9bd9edbc863651efec4fce2ba4094df1fc38d20fef44d1e", ex.Hash)
+}
+
+func TestParseBeforeAfterEach(t *testing.T) {
+	ex, err := parser.New().Parse(strings.NewReader("# Suite\n\n## Run\n```bash\necho run\n```\n\n## Before each\n```bash\necho before\n```\n\n## After each\n```bash\necho after\n```\n"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"echo run"}, ex.Run)
+	require.Equal(t, []string{"echo before"}, ex.BeforeEach)
+	require.Equal(t, []string{"echo after"}, ex.AfterEach)
+	require.Equal(t, []*parser.Block{
+		{Section: parser.SectionBeforeEach, Pos: parser.Position{Line: 10}, Script: "echo before"},
+		{Section: parser.SectionRun, Pos: parser.Position{Line: 5}, Script: "echo run"},
+		{Section: parser.SectionAfterEach, Pos: parser.Position{Line: 15}, Script: "echo after"},
+	}, ex.Blocks())
+}
+
+func TestExampleBlocks(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "README.md")
+	require.NoError(t, os.WriteFile(file, []byte("# Run\n```bash\necho run\n```\n\n# Cleanup\n```bash\necho cleanup\n```\n\n# Before each\n```bash\necho before\n```\n"), 0o600))
+
+	ex, err := parser.New().ParseFile(file)
+	require.NoError(t, err)
+	require.Equal(t, []*parser.Block{
+		{Section: parser.SectionBeforeEach, Pos: parser.Position{File: file, Line: 13}, Script: "echo before"},
+		{Section: parser.SectionRun, Pos: parser.Position{File: file, Line: 3}, Script: "echo run"},
+		{Section: parser.SectionCleanup, Pos: parser.Position{File: file, Line: 8}, Script: "echo cleanup"},
+	}, ex.Blocks())
+}
+
+func TestParseShellAttribute(t *testing.T) {
+	ex, err := parser.New().Parse(strings.NewReader("# Run\n```bash shell=sh\necho posix\n```\n\n```bash\necho bash\n```\n\n# Cleanup\n```bash shell=zsh \necho cleanup\n```\n"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"echo posix", "echo bash"}, ex.Run)
+	require.Equal(t, []string{"echo cleanup"}, ex.Cleanup)
+	require.Equal(t, []*parser.Block{
+		{Section: parser.SectionRun, Pos: parser.Position{Line: 3}, Script: "echo posix", Shell: "sh"},
+		{Section: parser.SectionRun, Pos: parser.Position{Line: 7}, Script: "echo bash"},
+		{Section: parser.SectionCleanup, Pos: parser.Position{Line: 12}, Script: "echo cleanup", Shell: "zsh"},
+	}, ex.Blocks())
+}
+
+func TestExampleAddBlock(t *testing.T) {
+	ex := &parser.Example{Source: "examples/README.md"}
+	ex.AddBlock(&parser.Block{Section: parser.SectionRun, Pos: parser.Position{Line: 5}, Script: "echo posix", Shell: "sh"})
+	ex.AddBlock(&parser.Block{Section: parser.SectionAfterEach, Pos: parser.Position{Line: 9}, Script: "echo after"})
+	ex.AddBlock(&parser.Block{Section: parser.SectionIncludes, Script: "echo ignored"})
+
+	require.Equal(t, []string{"echo posix"}, ex.Run)
+	require.Equal(t, []string{"echo after"}, ex.AfterEach)
+	require.Equal(t, []*parser.Block{
+		{Section: parser.SectionRun, Pos: parser.Position{File: "examples/README.md", Line: 5}, Script: "echo posix", Shell: "sh"},
+		{Section: parser.SectionAfterEach, Pos: parser.Position{File: "examples/README.md", Line: 9}, Script: "echo after"},
+	}, ex.Blocks())
+}
+
+func TestExampleDirectives(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "README.md")
+	require.NoError(t, os.WriteFile(file, []byte("---\nretry:\n  timeout: 1m\nowner: team\n---\n# Run\n```bash\necho a\n```\n"), 0o600))
+
+	ex, err := parser.New().ParseFile(file)
+	require.NoError(t, err)
+	require.Equal(t, []*parser.Directive{
+		{Key: "retry", Pos: parser.Position{File: file, Line: 2, Column: 1}, Known: true},
+		{Key: "owner", Pos: parser.Position{File: file, Line: 4, Column: 1}},
+	}, ex.Directives())
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rewrite rewrites the commands of the examples before suites are generated from them, so one tree of examples
+// can target several environments, e.g. another cluster or image registry
+package rewrite
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
+)
+
+// Command is a line of a bash block of an example
+type Command struct {
+	Example *linker.LinkedExample
+	// Section is the section of the block, e.g. parser.SectionRun
+	Section string
+	Line    string
+}
+
+// Rewriter returns the new line of the command
+type Rewriter interface {
+	Rewrite(c *Command) string
+}
+
+// Func is a function implementing Rewriter
+type Func func(c *Command) string
+
+// Rewrite calls the function
+func (f Func) Rewrite(c *Command) string {
+	return f(c)
+}
+
+// Rule replaces the matches of Regex in the lines with Replacement. $1 and ${name} in Replacement are expanded to the
+// submatches, so $ of env variables is written as $$
+type Rule struct {
+	Regex       *regexp.Regexp
+	Replacement string
+}
+
+// Rewrite replaces the matches of the rule in the line of the command
+func (r *Rule) Rewrite(c *Command) string {
+	return r.Regex.ReplaceAllString(c.Line, r.Replacement)
+}
+
+// ParseRule parses a rule in the s/REGEX/REPLACEMENT/ format. Any character following s is the delimiter, e.g.
+// s|registry.k8s.io/|mirror.example.com/|
+func ParseRule(s string) (*Rule, error) {
+	if len(s) < 2 || s[0] != 's' {
+		return nil, errors.Errorf("invalid rewrite rule %q, expected s/REGEX/REPLACEMENT/", s)
+	}
+	parts := strings.Split(s[2:], s[1:2])
+	if len(parts) != 3 || parts[0] == "" || parts[2] != "" {
+		return nil, errors.Errorf("invalid rewrite rule %q, expected s%vREGEX%vREPLACEMENT%v", s, s[1:2], s[1:2], s[1:2])
+	}
+	regex, err := regexp.Compile(parts[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid regex of rewrite rule %q", s)
+	}
+	return &Rule{Regex: regex, Replacement: parts[1]}, nil
+}
+
+// ParseRules parses the rules in the s/REGEX/REPLACEMENT/ format
+func ParseRules(rules ...string) ([]Rewriter, error) {
+	var result []Rewriter
+	for _, s := range rules {
+		rule, err := ParseRule(s)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, rule)
+	}
+	return result, nil
+}
+
+// Examples rewrites each line of the bash blocks of the examples with the rewriters applied in order. The parsed
+// examples are replaced with copies, so the callers holding them see the original commands
+func Examples(examples []*linker.LinkedExample, rewriters ...Rewriter) {
+	if len(rewriters) == 0 {
+		return
+	}
+	for _, e := range examples {
+		copied := *e.Example
+		copied.Run = rewriteBlocks(e, parser.SectionRun, e.Run, rewriters)
+		copied.Cleanup = rewriteBlocks(e, parser.SectionCleanup, e.Cleanup, rewriters)
+		copied.BeforeEach = rewriteBlocks(e, parser.SectionBeforeEach, e.BeforeEach, rewriters)
+		copied.AfterEach = rewriteBlocks(e, parser.SectionAfterEach, e.AfterEach, rewriters)
+		e.Example = &copied
+	}
+}
+
+func rewriteBlocks(e *linker.LinkedExample, section string, blocks []string, rewriters []Rewriter) []string {
+	var result []string
+	for _, block := range blocks {
+		lines := strings.Split(block, "\n")
+		for i := range lines {
+			for _, r := range rewriters {
+				lines[i] = r.Rewrite(&Command{Example: e, Section: section, Line: lines[i]})
+			}
+		}
+		result = append(result, strings.Join(lines, "\n"))
+	}
+	return result
+}
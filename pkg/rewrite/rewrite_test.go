@@ -0,0 +1,77 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rewrite_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/gotestmd/pkg/linker"
+	"github.com/networkservicemesh/gotestmd/pkg/parser"
+	"github.com/networkservicemesh/gotestmd/pkg/rewrite"
+)
+
+func TestParseRule(t *testing.T) {
+	rule, err := rewrite.ParseRule("s|registry.k8s.io/(\\w+)|mirror.example.com/$1|")
+	require.NoError(t, err)
+	require.Equal(t, "image: mirror.example.com/pause:3.9", rule.Rewrite(&rewrite.Command{Line: "image: registry.k8s.io/pause:3.9"}))
+
+	rule, err = rewrite.ParseRule("s/^kubectl /kubectl --kubeconfig $$KUBECONFIG2 /")
+	require.NoError(t, err)
+	require.Equal(t, "kubectl --kubeconfig $KUBECONFIG2 apply -k .", rule.Rewrite(&rewrite.Command{Line: "kubectl apply -k ."}))
+
+	for rule, msg := range map[string]string{
+		"":          `invalid rewrite rule "", expected s/REGEX/REPLACEMENT/`,
+		"y/a/b/":    `invalid rewrite rule "y/a/b/", expected s/REGEX/REPLACEMENT/`,
+		"s/a/b":     `invalid rewrite rule "s/a/b", expected s/REGEX/REPLACEMENT/`,
+		"s|a|b|c|":  `invalid rewrite rule "s|a|b|c|", expected s|REGEX|REPLACEMENT|`,
+		"s//b/":     `invalid rewrite rule "s//b/", expected s/REGEX/REPLACEMENT/`,
+		"s/(a/b/":   "invalid regex of rewrite rule \"s/(a/b/\": error parsing regexp: missing closing ): `(a`",
+		"s/a/b/ccc": `invalid rewrite rule "s/a/b/ccc", expected s/REGEX/REPLACEMENT/`,
+	} {
+		_, err = rewrite.ParseRule(rule)
+		require.EqualError(t, err, msg, rule)
+	}
+}
+
+func TestExamples(t *testing.T) {
+	parsed := &parser.Example{
+		Dir:     "examples/basic",
+		Run:     []string{"kubectl apply -k .\necho kubectl", "kubectl get pods"},
+		Cleanup: []string{"kubectl delete ns basic"},
+	}
+	examples, err := linker.New("examples/").Link(parsed)
+	require.NoError(t, err)
+	rules, err := rewrite.ParseRules("s/^kubectl /kubectl --context=kind-2 /")
+	require.NoError(t, err)
+
+	var sections []string
+	rewrite.Examples(examples, append(rules, rewrite.Func(func(c *rewrite.Command) string {
+		sections = append(sections, c.Example.Name+" "+c.Section)
+		if c.Section == parser.SectionCleanup {
+			return "sudo " + c.Line
+		}
+		return c.Line
+	}))...)
+
+	require.Equal(t, []string{"kubectl --context=kind-2 apply -k .\necho kubectl", "kubectl --context=kind-2 get pods"}, examples[0].Run)
+	require.Equal(t, []string{"sudo kubectl --context=kind-2 delete ns basic"}, examples[0].Cleanup)
+	require.Equal(t, "basic Run,basic Run,basic Run,basic Cleanup", strings.Join(sections, ","))
+	require.Equal(t, []string{"kubectl apply -k .\necho kubectl", "kubectl get pods"}, parsed.Run)
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shell
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var artifactsDirFlag = flag.String("gotestmd.artifacts-dir", os.Getenv("ARTIFACTS_DIR"), "directory to collect the transcript, env and artifacts of the failed tests into, ARTIFACTS_DIR by default. Nothing is collected if it's empty")
+
+// transcriptSize is the number of the last attempts of the commands kept in the transcript
+const transcriptSize = 100
+
+// artifact is a command whose output is collected when the test fails
+type artifact struct {
+	name string
+	cmd  string
+}
+
+// Artifact registers the command whose output is collected into NAME.log when the test fails, e.g.
+//
+//	r.Artifact("pods", "kubectl get pods -A -o wide")
+func (r *Runner) Artifact(name, cmd string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.artifacts = append(r.artifacts, artifact{name: name, cmd: cmd})
+}
+
+// record adds the attempt of the command to the transcript
+func (r *Runner) record(cmd string, res result) {
+	var sb strings.Builder
+	_, _ = sb.WriteString("$ " + cmd + "\n")
+	for _, output := range []string{res.stdout, res.stderr} {
+		if output != "" {
+			_, _ = sb.WriteString(output + "\n")
+		}
+	}
+	_, _ = sb.WriteString("# exit code " + strconv.Itoa(res.exitCode) + " after " + res.duration.String() + "\n\n")
+	r.transcript = append(r.transcript, sb.String())
+	if len(r.transcript) > transcriptSize {
+		r.transcript = r.transcript[len(r.transcript)-transcriptSize:]
+	}
+}
+
+// collectArtifactsIfFailed collects the artifacts if the test has failed without a failed command of the runner,
+// e.g. on a failed assertion of a hand-written suite
+func (r *Runner) collectArtifactsIfFailed() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if t, ok := r.t.(interface{ Failed() bool }); ok && t.Failed() {
+		r.collectArtifacts()
+	}
+}
+
+// collectArtifacts writes the transcript, the env of the shell and the output of the artifact commands into the dir of
+// the test in the artifacts dir. The artifacts are collected once, on the first failure, while the resources created
+// by the test still exist
+func (r *Runner) collectArtifacts() {
+	if *artifactsDirFlag == "" || r.collected || r.bash == nil {
+		return
+	}
+	r.collected = true
+
+	dir := filepath.Join(*artifactsDirFlag, filepath.FromSlash(r.t.Name()), filepath.Base(r.Dir()))
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		r.logger.Errorf("can't create artifacts dir: %v", err)
+		return
+	}
+	r.writeArtifact(dir, "transcript", strings.Join(r.transcript, ""))
+	r.writeArtifact(dir, "env", r.artifactOutput("env | sort"))
+	for _, a := range r.artifacts {
+		r.writeArtifact(dir, a.name, r.artifactOutput(a.cmd))
+	}
+	r.t.Logf("artifacts are collected into %v", dir)
+}
+
+// artifactOutput runs the artifact command and returns its stdout and stderr. The command isn't interrupted by the
+// context of the runner, so artifacts are collected when a suite times out too
+func (r *Runner) artifactOutput(cmd string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), *timeoutFlag)
+	defer cancel()
+	stdout, stderr, exitCode, err := r.bash.RunContext(ctx, cmd)
+	if err == context.DeadlineExceeded {
+		r.restart()
+	}
+	if err != nil {
+		return "can't run " + cmd + ": " + err.Error() + "\n"
+	}
+	result := stdout + "\n"
+	if stderr != "" {
+		result += stderr + "\n"
+	}
+	if exitCode != 0 {
+		result += "# exit code " + strconv.Itoa(exitCode) + "\n"
+	}
+	return result
+}
+
+func (r *Runner) writeArtifact(dir, name, content string) {
+	if err := os.WriteFile(filepath.Join(dir, name+".log"), []byte(content), 0o600); err != nil {
+		r.logger.Errorf("can't write artifact %v: %v", name, err)
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shell
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+var recordFlag = flag.String("gotestmd.record", os.Getenv("GOTESTMD_RECORD"), "directory to record the output and exit codes of the commands of the runners into, GOTESTMD_RECORD by default")
+var replayFlag = flag.String("gotestmd.replay", os.Getenv("GOTESTMD_REPLAY"), "directory to replay the recorded commands of the runners from without running them, GOTESTMD_REPLAY by default")
+
+// cassettePaths counts the runners of a test in the same dir, so each of them has its own cassette
+var cassettePaths = struct {
+	sync.Mutex
+	count map[string]int
+}{count: map[string]int{}}
+
+// cassette is the recorded commands of a runner
+type cassette struct {
+	path         string
+	replay       bool
+	Interactions []*interaction `json:"interactions"`
+	// next is the index of the next interaction to replay
+	next    int
+	current *interaction
+}
+
+// interaction is a command with its attempts
+type interaction struct {
+	Cmd      string             `json:"cmd"`
+	Attempts []*cassetteAttempt `json:"attempts"`
+	// next is the index of the next attempt to replay
+	next int
+}
+
+// cassetteAttempt is a recorded attempt of a command
+type cassetteAttempt struct {
+	Stdout   string `json:"stdout,omitempty"`
+	Stderr   string `json:"stderr,omitempty"`
+	ExitCode int    `json:"exitCode"`
+	TimedOut bool   `json:"timedOut,omitempty"`
+}
+
+// newCassette returns the cassette of the runner of the test in the dir or nil if the commands are neither recorded nor
+// replayed. The cassette is dir/TEST/BASE.json, where BASE is the base of the dir of the runner
+func newCassette(t TestingT, runnerDir string) (*cassette, error) {
+	if *recordFlag != "" && *replayFlag != "" {
+		return nil, errors.New("commands can't be recorded and replayed at once")
+	}
+	dir, replay := *recordFlag, false
+	if *replayFlag != "" {
+		dir, replay = *replayFlag, true
+	}
+	if dir == "" {
+		return nil, nil
+	}
+	path := filepath.Join(dir, filepath.FromSlash(t.Name()), filepath.Base(runnerDir))
+	cassettePaths.Lock()
+	cassettePaths.count[path]++
+	if n := cassettePaths.count[path]; n > 1 {
+		path += "-" + strconv.Itoa(n)
+	}
+	cassettePaths.Unlock()
+
+	result := &cassette{path: path + ".json", replay: replay}
+	if !replay {
+		return result, nil
+	}
+	// #nosec
+	data, err := os.ReadFile(result.path)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't read cassette")
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, errors.Wrapf(err, "can't parse cassette %v", result.path)
+	}
+	return result, nil
+}
+
+// begin starts the interaction of the command. The replayed command should be the next command of the cassette
+func (c *cassette) begin(cmd string) error {
+	if !c.replay {
+		c.current = &interaction{Cmd: cmd}
+		c.Interactions = append(c.Interactions, c.current)
+		return nil
+	}
+	if c.next >= len(c.Interactions) {
+		return errors.Errorf("no more commands in cassette %v", c.path)
+	}
+	c.current = c.Interactions[c.next]
+	c.next++
+	if c.current.Cmd != cmd {
+		return errors.Errorf("expected command %q in cassette %v", c.current.Cmd, c.path)
+	}
+	return nil
+}
+
+// record adds the attempt to the current interaction
+func (c *cassette) record(stdout, stderr string, exitCode int, err error) {
+	c.current.Attempts = append(c.current.Attempts, &cassetteAttempt{
+		Stdout:   stdout,
+		Stderr:   stderr,
+		ExitCode: exitCode,
+		TimedOut: err == context.DeadlineExceeded,
+	})
+}
+
+// replayAttempt returns the next attempt of the current interaction. A timed out attempt returns context.DeadlineExceeded
+func (c *cassette) replayAttempt() (stdout, stderr string, exitCode int, err error) {
+	if c.current.next >= len(c.current.Attempts) {
+		return "", "", 0, errors.Errorf("no more attempts of %q in cassette %v", c.current.Cmd, c.path)
+	}
+	a := c.current.Attempts[c.current.next]
+	c.current.next++
+	if a.TimedOut {
+		return "", "", 0, context.DeadlineExceeded
+	}
+	return a.Stdout, a.Stderr, a.ExitCode, nil
+}
+
+// replayed returns true if all attempts of the current replayed interaction are served, so the command isn't retried
+func (c *cassette) replayed() bool {
+	return c != nil && c.replay && c.current.next >= len(c.current.Attempts)
+}
+
+// save writes the recorded cassette
+func (c *cassette) save() error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "can't marshal cassette")
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o750); err != nil {
+		return errors.Wrap(err, "can't create cassette dir")
+	}
+	return errors.Wrap(os.WriteFile(c.path, data, 0o600), "can't write cassette")
+}
+
+// runAttempt runs the attempt of the command in the shell, recording it, or replays it from the cassette
+func (r *Runner) runAttempt(ctx context.Context, cmd string) (stdout, stderr string, exitCode int, err error) {
+	if r.cassette != nil && r.cassette.replay {
+		stdout, stderr, exitCode, err = r.cassette.replayAttempt()
+		for _, line := range strings.Split(stdout, "\n") {
+			if line != "" {
+				r.logLine("stdout", line)
+			}
+		}
+		for _, line := range strings.Split(stderr, "\n") {
+			if line != "" {
+				r.logLine("stderr", line)
+			}
+		}
+		return stdout, stderr, exitCode, err
+	}
+	stdout, stderr, exitCode, err = r.bash.RunContext(ctx, cmd)
+	if r.cassette != nil && (err == nil || err == context.DeadlineExceeded) {
+		r.cassette.record(stdout, stderr, exitCode, err)
+	}
+	return stdout, stderr, exitCode, err
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shell
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/networkservicemesh/gotestmd/pkg/bash"
+)
+
+var containerRuntimeFlag = flag.String("gotestmd.container-runtime", os.Getenv("GOTESTMD_CONTAINER_RUNTIME"), "docker, podman or another CLI compatible with docker run starting the containers of UseImage, GOTESTMD_CONTAINER_RUNTIME or docker by default")
+
+// UseImage runs the next commands of the runner in a container of the image, so the tools of the host aren't required.
+// The module root is mounted into the container at the same path, the variables set by the runner are kept
+func (r *Runner) UseImage(image string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.image = image
+	r.restart()
+}
+
+// container returns the container of the image running the commands in the dir
+func container(image, dir string) *bash.Container {
+	mount := dir
+	if root := findRoot(); root != "" {
+		if rel, err := filepath.Rel(root, dir); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			mount = root
+		}
+	}
+	return &bash.Container{
+		Runtime: *containerRuntimeFlag,
+		Image:   image,
+		Mounts:  []string{mount},
+		Dir:     dir,
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shell
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+var debugFlag = flag.String("gotestmd.debug", os.Getenv("GOTESTMD_DEBUG"), "pause the test on a failed command before the cleanup, so the state can be inspected: true waits for Enter and shell opens a shell in the dir and env of the runner, GOTESTMD_DEBUG by default")
+
+// debugMu serializes the pauses of the parallel tests, so one operator handles them one by one
+var debugMu sync.Mutex
+
+// debugMode returns the mode set by the gotestmd.debug flag or an empty string if the tests aren't paused
+func debugMode() string {
+	switch strings.ToLower(*debugFlag) {
+	case "", "0", "false":
+		return ""
+	case "shell":
+		return "shell"
+	default:
+		return "true"
+	}
+}
+
+// pause prints the dir and the env of the failed command on the terminal and waits for the operator before the test
+// fails and runs the cleanup. The shell mode opens an interactive bash in the dir and with the exported variables of the
+// shell of the runner instead, and the test continues when it exits. The shell isn't opened for the runners on SSH hosts
+// and in containers
+func (r *Runner) pause(cmd string) {
+	mode := debugMode()
+	if mode == "" || r.bash == nil {
+		return
+	}
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		r.t.Logf("can't pause the test on the failed command: %v", err)
+		return
+	}
+	defer func() { _ = tty.Close() }()
+
+	debugMu.Lock()
+	defer debugMu.Unlock()
+
+	dir := strings.TrimSpace(r.shellOutput("pwd"))
+	exports := r.shellOutput("export -p")
+	_, _ = fmt.Fprintf(tty, "\n%v is paused on the failed command in %v:\n%v\n\n%v\n", r.t.Name(), r.location(), cmd, exports)
+	if mode == "shell" && r.image == "" && remoteHost(r.dir) == nil {
+		_, _ = fmt.Fprintf(tty, "exit the shell to continue the test\n")
+		if err := r.debugShell(tty, dir, exports); err != nil {
+			r.t.Logf("can't open the debug shell: %v", err)
+		}
+		return
+	}
+	_, _ = fmt.Fprintf(tty, "press Enter to continue the test\n")
+	_, _ = bufio.NewReader(tty).ReadString('\n')
+}
+
+// shellOutput returns the stdout of the command run by the shell of the runner
+func (r *Runner) shellOutput(cmd string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), *timeoutFlag)
+	defer cancel()
+	stdout, _, _, err := r.bash.RunContext(ctx, cmd)
+	if err == context.DeadlineExceeded {
+		r.restart()
+	}
+	return stdout
+}
+
+// debugShell runs an interactive bash on the terminal in the dir, sourcing the exported variables of the runner
+func (r *Runner) debugShell(tty *os.File, dir, exports string) error {
+	rcDir, err := os.MkdirTemp("", "gotestmd-debug-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(rcDir) }()
+	rcFile := filepath.Join(rcDir, "bashrc")
+	if err := os.WriteFile(rcFile, []byte(exports+"\nPS1='(gotestmd) \\w \\$ '\n"), 0o600); err != nil {
+		return err
+	}
+	shell := exec.Command("bash", "--rcfile", rcFile, "-i") // #nosec
+	shell.Dir = dir
+	shell.Stdin, shell.Stdout, shell.Stderr = tty, tty, tty
+	return shell.Run()
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shell
+
+import (
+	"flag"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/networkservicemesh/gotestmd/pkg/bash"
+)
+
+var dryRunFlag = flag.Bool("gotestmd.dry-run", envBool("GOTESTMD_DRY_RUN"), "log the commands of the runners with their dir and env instead of running them, GOTESTMD_DRY_RUN by default")
+
+func envBool(key string) bool {
+	result, _ := strconv.ParseBool(os.Getenv(key))
+	return result
+}
+
+// logDryRun logs the command the runner would run with the dir and the env it would run in
+func (r *Runner) logDryRun(cmd string) {
+	r.t.Logf("dry run in %v:\n%v\nenv: %v", r.location(), cmd, strings.Join(r.dryRunEnv(), " "))
+}
+
+// location returns the dir the commands of the runner run in, on the remote host or in the container if any
+func (r *Runner) location() string {
+	if r.image != "" {
+		return r.dir + " in container " + r.image
+	}
+	if ssh := remoteHost(r.dir); ssh != nil {
+		return ssh.Host + ":" + ssh.Dir
+	}
+	return r.dir
+}
+
+// dryRunEnv returns the env of the runner with the variables set by Setenv and Unsetenv applied
+func (r *Runner) dryRunEnv() []string {
+	var keys []string
+	values := map[string]string{}
+	seen := map[string]bool{}
+	set := func(key, value string) {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+		values[key] = value
+	}
+	for _, kv := range r.env {
+		key, value, _ := strings.Cut(kv, "=")
+		set(key, bash.Quote(value))
+	}
+	for _, v := range r.vars {
+		if v.unset {
+			delete(values, v.key)
+			continue
+		}
+		set(v.key, bash.Quote(v.value))
+	}
+	var result []string
+	for _, key := range keys {
+		if value, ok := values[key]; ok {
+			result = append(result, key+"="+value)
+		}
+	}
+	return result
+}
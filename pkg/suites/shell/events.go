@@ -0,0 +1,109 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shell
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var eventsFlag = flag.String("gotestmd.events", os.Getenv("GOTESTMD_EVENTS"), "JSONL file the events of the commands of the runners are appended to, GOTESTMD_EVENTS by default. A relative path is relative to the module root")
+
+// eventsMu serializes the writes of the runners of parallel tests
+var eventsMu sync.Mutex
+
+// event is a command run by a runner
+type event struct {
+	Test  string `json:"test"`
+	Cmd   string `json:"cmd"`
+	Dir   string `json:"dir"`
+	Host  string `json:"host,omitempty"`
+	Image string `json:"image,omitempty"`
+	// Start and End are the times the first attempt started and the last attempt ended
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	ExitCode int       `json:"exitCode"`
+	Attempts int       `json:"attempts"`
+	// Failure is empty if the command succeeded
+	Failure string `json:"failure,omitempty"`
+	// StdoutSha256 and StderrSha256 are the digests of the output of the last attempt
+	StdoutSha256 string `json:"stdoutSha256"`
+	StderrSha256 string `json:"stderrSha256"`
+}
+
+// logEvent appends the event of the command to the file set by the gotestmd.events flag
+func (r *Runner) logEvent(cmd string, start time.Time, attempts int, failure string) {
+	if *eventsFlag == "" {
+		return
+	}
+	e := &event{
+		Test:         r.t.Name(),
+		Cmd:          cmd,
+		Dir:          r.dir,
+		Image:        r.image,
+		Start:        start,
+		End:          time.Now(),
+		ExitCode:     r.last.exitCode,
+		Attempts:     attempts,
+		Failure:      failure,
+		StdoutSha256: digest(r.last.stdout),
+		StderrSha256: digest(r.last.stderr),
+	}
+	if ssh := remoteHost(r.dir); ssh != nil && r.image == "" {
+		e.Host, e.Dir = ssh.Host, ssh.Dir
+	}
+	if err := writeEvent(e); err != nil {
+		r.logger.Errorf("can't write event: %v", err)
+	}
+}
+
+func writeEvent(e *event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrap(err, "can't marshal event")
+	}
+	path := *eventsFlag
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(findRoot(), path)
+	}
+
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+	// #nosec
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return errors.Wrap(err, "can't open events file")
+	}
+	_, err = f.Write(append(data, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return errors.Wrap(err, "can't write events file")
+}
+
+func digest(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
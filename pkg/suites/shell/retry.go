@@ -0,0 +1,139 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shell
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// defaultInterval is the pause between the attempts of a failed command
+const defaultInterval = 100 * time.Millisecond
+
+type retryOptions struct {
+	timeout        time.Duration
+	interval       time.Duration
+	backoff        float64
+	maxInterval    time.Duration
+	attemptTimeout time.Duration
+	match          func(stdout string) bool
+	// expected describes the expected exit codes accepted by exitCode. It's empty for the default zero exit code
+	expected string
+	exitCode func(code int) bool
+}
+
+func newRetryOptions(options ...RetryOption) *retryOptions {
+	result := &retryOptions{
+		timeout:  *timeoutFlag,
+		interval: defaultInterval,
+		backoff:  1,
+		exitCode: func(code int) bool {
+			return code == 0
+		},
+	}
+	for _, o := range options {
+		o(result)
+	}
+	return result
+}
+
+// exitCodeError returns the message of the command failed with the unexpected exit code or an empty string
+func (o *retryOptions) exitCodeError(code int) string {
+	switch {
+	case o.exitCode(code):
+		return ""
+	case o.expected == "":
+		return "command failed with exit code " + strconv.Itoa(code)
+	default:
+		return "command exited with code " + strconv.Itoa(code) + ", expected " + o.expected
+	}
+}
+
+// nextInterval returns the pause after the current one grown by the backoff
+func (o *retryOptions) nextInterval(interval time.Duration) time.Duration {
+	next := time.Duration(float64(interval) * o.backoff)
+	if o.maxInterval > 0 && next > o.maxInterval {
+		return o.maxInterval
+	}
+	return next
+}
+
+// RetryOption is an option of RunRetry
+type RetryOption func(o *retryOptions)
+
+// WithRetryTimeout retries the command until the timeout passes instead of the gotestmd.t flag
+func WithRetryTimeout(timeout time.Duration) RetryOption {
+	return func(o *retryOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithInterval sets the pause between the attempts, 100ms by default
+func WithInterval(interval time.Duration) RetryOption {
+	return func(o *retryOptions) {
+		o.interval = interval
+	}
+}
+
+// WithBackoff multiplies the pause by the factor after each attempt up to maxInterval. Zero maxInterval doesn't limit it
+func WithBackoff(factor float64, maxInterval time.Duration) RetryOption {
+	return func(o *retryOptions) {
+		o.backoff = factor
+		o.maxInterval = maxInterval
+	}
+}
+
+// WithAttemptTimeout interrupts an attempt of the command that doesn't finish in the timeout like RunWithTimeout
+func WithAttemptTimeout(timeout time.Duration) RetryOption {
+	return func(o *retryOptions) {
+		o.attemptTimeout = timeout
+	}
+}
+
+// WithOutput retries the command until it succeeds with the stdout accepted by the predicate
+func WithOutput(predicate func(stdout string) bool) RetryOption {
+	return func(o *retryOptions) {
+		o.match = predicate
+	}
+}
+
+// WithOutputMatching retries the command until it succeeds with the stdout matching the regex. Panics if the regex
+// is invalid
+func WithOutputMatching(pattern string) RetryOption {
+	return WithOutput(regexp.MustCompile(pattern).MatchString)
+}
+
+// WithExpectedExitCode makes the command succeed only if it exits with the code, e.g. for documented error paths
+func WithExpectedExitCode(code int) RetryOption {
+	return func(o *retryOptions) {
+		o.expected = strconv.Itoa(code)
+		o.exitCode = func(actual int) bool {
+			return actual == code
+		}
+	}
+}
+
+// WithExpectedFailure makes the command succeed only if it exits with a non-zero code
+func WithExpectedFailure() RetryOption {
+	return func(o *retryOptions) {
+		o.expected = "non-zero"
+		o.exitCode = func(actual int) bool {
+			return actual != 0
+		}
+	}
+}
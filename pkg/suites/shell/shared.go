@@ -0,0 +1,188 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shell
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// sharedSuite tracks a parent suite or a setup used by several suites or tests, possibly running in parallel
+type sharedSuite struct {
+	mu       sync.Mutex
+	refs     int
+	cleanups []func()
+	// parent is the instance of the parent suite that is set up. Its SharedState is passed to the parents of other suites
+	parent interface{}
+	// t is the test currently owning the setup: the one that set it up or the last one releasing it
+	t TestingT
+	// failed is set if the setup failed the test that ran it, so the other tests using it fail instead of running
+	// against a broken environment
+	failed bool
+}
+
+var sharedSuitesMu sync.Mutex
+var sharedSuites = map[interface{}]*sharedSuite{}
+
+type cleanupRouter interface {
+	setCleanup(cleanup func(f func()))
+}
+
+// SharedState is implemented by parent suites keeping the state of the environment they set up, e.g. clients of a
+// cluster. The parent suite that is set up exports its state, the parents of the other suites using it get the state.
+// Each parent keeps the *testing.T of its own suite
+type SharedState interface {
+	State() interface{}
+	SetState(state interface{})
+}
+
+func getSharedSuite(key interface{}) *sharedSuite {
+	sharedSuitesMu.Lock()
+	defer sharedSuitesMu.Unlock()
+
+	if _, ok := sharedSuites[key]; !ok {
+		sharedSuites[key] = new(sharedSuite)
+	}
+	return sharedSuites[key]
+}
+
+func (ss *sharedSuite) acquire(s *Suite, parent interface{}) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if v, ok := parent.(suite.TestingSuite); ok {
+		v.SetT(s.T())
+	}
+	s.Cleanup(func() {
+		ss.release(func() {
+			// Parent cleanups may run after the suite that has set it up is finished, so they report to the last suite
+			if v, ok := ss.parent.(suite.TestingSuite); ok {
+				v.SetT(s.T())
+			}
+			ss.parent = nil
+		})
+	})
+	if ss.refs++; ss.refs == 1 {
+		ss.parent = parent
+		if v, ok := parent.(cleanupRouter); ok {
+			v.setCleanup(ss.addCleanup)
+		}
+		if v, ok := parent.(suite.SetupAllSuite); ok {
+			ss.setUp(v.SetupSuite)
+		}
+		return
+	}
+	if ss.failed {
+		s.T().Fatalf("setup of the required suite %T failed", parent)
+	}
+	if dst, ok := parent.(SharedState); ok && parent != ss.parent {
+		if src, ok := ss.parent.(SharedState); ok {
+			dst.SetState(src.State())
+		}
+	}
+}
+
+// setUp runs the setup and records if it doesn't return, e.g. when it calls FailNow
+func (ss *sharedSuite) setUp(setup func()) {
+	ss.failed = true
+	setup()
+	ss.failed = false
+}
+
+// addCleanup is called only during setup or by cleanups, so the lock is already held
+func (ss *sharedSuite) addCleanup(f func()) {
+	ss.cleanups = append(ss.cleanups, f)
+}
+
+// release calls last before the cleanups if it is the last reference released
+func (ss *sharedSuite) release(last func()) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if ss.refs--; ss.refs > 0 {
+		return
+	}
+	last()
+	for i := len(ss.cleanups) - 1; i >= 0; i-- {
+		ss.cleanups[i]()
+	}
+	ss.cleanups = nil
+}
+
+// SetupParents sets up suites required by the suite. Each parent suite is set up only once while it is used
+// by at least one suite in the process, the parents of the other suites get the SharedState of the one that is set up.
+// Cleanups of the parent run when the last suite using it is torn down.
+func (s *Suite) SetupParents(parents ...interface{}) {
+	for _, p := range parents {
+		getSharedSuite(reflect.TypeOf(p)).acquire(s, p)
+	}
+}
+
+// Cleanup registers a function to be called when the suite is torn down
+func (s *Suite) Cleanup(f func()) {
+	if s.cleanup != nil {
+		s.cleanup(f)
+		return
+	}
+	s.T().Cleanup(f)
+}
+
+func (s *Suite) setCleanup(cleanup func(f func())) {
+	s.cleanup = cleanup
+}
+
+// sharedT is passed to a shared setup. Cleanups are deferred until the last test using the setup ends
+type sharedT struct {
+	ss *sharedSuite
+}
+
+func (t sharedT) Name() string                              { return t.ss.t.Name() }
+func (t sharedT) Cleanup(f func())                          { t.ss.addCleanup(f) }
+func (t sharedT) Errorf(format string, args ...interface{}) { t.ss.t.Errorf(format, args...) }
+func (t sharedT) Fatalf(format string, args ...interface{}) { t.ss.t.Fatalf(format, args...) }
+func (t sharedT) FailNow()                                  { t.ss.t.FailNow() }
+func (t sharedT) unwrap() TestingT                          { return t.ss.t }
+func (t sharedT) Logf(format string, args ...interface{}) {
+	if h, ok := unwrap(t.ss.t).(helperT); ok {
+		h.Helper()
+	}
+	t.ss.t.Logf(format, args...)
+}
+
+// SetupShared runs setup only once while at least one test using the key is running. Tests may run in parallel.
+// Cleanups registered by setup run when the last test using the setup ends. If setup fails the test with FailNow,
+// the other tests using the key fail too
+func SetupShared(t TestingT, key string, setup func(t TestingT)) {
+	ss := getSharedSuite(key)
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	t.Cleanup(func() {
+		ss.release(func() { ss.t = t })
+	})
+	if ss.refs++; ss.refs == 1 {
+		ss.t = t
+		ss.setUp(func() { setup(sharedT{ss: ss}) })
+		return
+	}
+	if ss.failed {
+		t.Fatalf("shared setup %v failed", key)
+	}
+}
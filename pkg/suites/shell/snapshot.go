@@ -0,0 +1,149 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shell
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/networkservicemesh/gotestmd/pkg/bash"
+)
+
+// Snapshot is the state shared by the tests of a suite, taken before a test to find the state the test leaks
+type Snapshot struct {
+	env        map[string]string
+	namespaces []string
+	// existing are the namespaces of the list existing before the test
+	existing map[string]bool
+}
+
+// SnapshotOption sets the state taken by SnapshotState
+type SnapshotOption func(s *Snapshot)
+
+// SnapshotEnv takes the env of the test process, which is inherited by the runners of the next tests
+func SnapshotEnv() SnapshotOption {
+	return func(s *Snapshot) {
+		s.env = environ()
+	}
+}
+
+// SnapshotNamespaces takes the Kubernetes namespaces of the list existing before the test
+func SnapshotNamespaces(namespaces ...string) SnapshotOption {
+	return func(s *Snapshot) {
+		s.namespaces = append(s.namespaces, namespaces...)
+	}
+}
+
+// SnapshotState takes the state set by the options before a test, e.g. in SetupTest. RestoreState checks it after the
+// test
+func (s *Suite) SnapshotState(options ...SnapshotOption) {
+	snapshot := &Snapshot{}
+	for _, o := range options {
+		o(snapshot)
+	}
+	if len(snapshot.namespaces) > 0 {
+		snapshot.existing = map[string]bool{}
+		for _, ns := range s.existingNamespaces(snapshot.namespaces) {
+			snapshot.existing[ns] = true
+		}
+	}
+	s.snapshot = snapshot
+}
+
+// RestoreState fails the test if it has changed the env of the process or left the namespaces of the snapshot it has
+// created, and restores them, so the next tests aren't affected, e.g. in TearDownTest
+func (s *Suite) RestoreState() {
+	snapshot := s.snapshot
+	if snapshot == nil {
+		return
+	}
+	s.snapshot = nil
+
+	if snapshot.env != nil {
+		if leaked := restoreEnv(snapshot.env); len(leaked) > 0 {
+			s.T().Errorf("test has changed env variables: %v", strings.Join(leaked, ", "))
+		}
+	}
+	var leaked []string
+	for _, ns := range s.existingNamespaces(snapshot.namespaces) {
+		if !snapshot.existing[ns] {
+			leaked = append(leaked, ns)
+		}
+	}
+	if len(leaked) > 0 {
+		s.T().Errorf("test has left namespaces: %v", strings.Join(leaked, ", "))
+		s.Runner(".").Run("kubectl delete namespace " + quoteAll(leaked))
+	}
+}
+
+// existingNamespaces returns the namespaces of the list existing in the cluster
+func (s *Suite) existingNamespaces(namespaces []string) []string {
+	if len(namespaces) == 0 {
+		return nil
+	}
+	r := s.Runner(".")
+	r.Run("kubectl get namespace " + quoteAll(namespaces) + " --ignore-not-found -o name")
+	var result []string
+	for _, line := range strings.Split(r.Stdout(), "\n") {
+		if ns := strings.TrimPrefix(strings.TrimSpace(line), "namespace/"); ns != "" {
+			result = append(result, ns)
+		}
+	}
+	return result
+}
+
+func quoteAll(words []string) string {
+	var result []string
+	for _, w := range words {
+		result = append(result, bash.Quote(w))
+	}
+	return strings.Join(result, " ")
+}
+
+func environ() map[string]string {
+	result := map[string]string{}
+	for _, kv := range os.Environ() {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// restoreEnv restores the env of the process and returns the sorted names of the changed variables
+func restoreEnv(env map[string]string) []string {
+	var result []string
+	current := environ()
+	for key, value := range current {
+		if old, ok := env[key]; !ok {
+			_ = os.Unsetenv(key)
+			result = append(result, key)
+		} else if old != value {
+			_ = os.Setenv(key, old)
+			result = append(result, key)
+		}
+	}
+	for key, value := range env {
+		if _, ok := current[key]; !ok {
+			_ = os.Setenv(key, value)
+			result = append(result, key)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
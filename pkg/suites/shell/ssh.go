@@ -0,0 +1,56 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shell
+
+import (
+	"flag"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/networkservicemesh/gotestmd/pkg/bash"
+)
+
+var sshHostFlag = flag.String("gotestmd.ssh-host", os.Getenv("GOTESTMD_SSH_HOST"), "[USER@]HOST running the commands of the runners over SSH, GOTESTMD_SSH_HOST by default. Commands run locally if it's empty")
+var sshPortFlag = flag.Int("gotestmd.ssh-port", envInt("GOTESTMD_SSH_PORT"), "SSH port, GOTESTMD_SSH_PORT by default")
+var sshKeyFlag = flag.String("gotestmd.ssh-key", os.Getenv("GOTESTMD_SSH_KEY"), "private key of the SSH user, GOTESTMD_SSH_KEY by default")
+var sshDirFlag = flag.String("gotestmd.ssh-dir", os.Getenv("GOTESTMD_SSH_DIR"), "checkout of the module on the remote host, GOTESTMD_SSH_DIR by default. Dirs of the runners are translated to it, otherwise they're relative to the home dir of the SSH user")
+
+func envInt(key string) int {
+	result, _ := strconv.Atoi(os.Getenv(key))
+	return result
+}
+
+// remoteHost returns the SSH host running the commands of the runner in the dir or nil if the commands run locally.
+// The dir inside the module is translated to the checkout of the module on the remote host
+func remoteHost(dir string) *bash.SSH {
+	if *sshHostFlag == "" {
+		return nil
+	}
+	remoteDir := filepath.ToSlash(dir)
+	if rel, err := filepath.Rel(findRoot(), dir); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		remoteDir = path.Join(*sshDirFlag, filepath.ToSlash(rel))
+	}
+	return &bash.SSH{
+		Host:    *sshHostFlag,
+		Port:    *sshPortFlag,
+		KeyFile: *sshKeyFlag,
+		Dir:     remoteDir,
+	}
+}
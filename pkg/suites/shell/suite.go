@@ -18,13 +18,17 @@
 package shell
 
 import (
+	"context"
 	"flag"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
-	"testing"
+	"sync/atomic"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -33,43 +37,192 @@ import (
 )
 
 var timeoutFlag = flag.Duration("gotestmd.t", time.Minute, "timeout for command execution. Usage: set timeout in duratiom format via shell.timeout flag")
+var bufferFlag = flag.Bool("gotestmd.buffer", false, "log the output of commands when they finish instead of streaming it line by line into the test log")
+var shellFlag = flag.String("gotestmd.shell", os.Getenv("GOTESTMD_SHELL"), "shell running the commands of the runners, e.g. sh, zsh or \"busybox sh\", GOTESTMD_SHELL or bash by default")
+var outputLimitFlag = flag.Int("gotestmd.output-limit", envInt("GOTESTMD_OUTPUT_LIMIT"), "max size in bytes of stdout and stderr of a command kept and logged, the head and tail of the longer output are kept, GOTESTMD_OUTPUT_LIMIT by default. Zero doesn't limit the output")
+var createDirsFlag = flag.Bool("gotestmd.create-dirs", envBool("GOTESTMD_CREATE_DIRS"), "create the missing dirs of the runners instead of failing the tests, GOTESTMD_CREATE_DIRS by default")
 var once sync.Once
 
+// logger is shared by the runners, so the entries of the runners of parallel tests don't interleave
+var logger = &logrus.Logger{
+	Out:   os.Stderr,
+	Level: logrus.DebugLevel,
+	Formatter: &logrus.TextFormatter{
+		DisableQuote: true,
+	},
+}
+
 // Suite is testify suite that provides a shell helper functions for each test.
 type Suite struct {
 	suite.Suite
+	cleanup func(f func())
+	ctx     context.Context
+	// snapshot is the state taken by SnapshotState before the current test
+	snapshot *Snapshot
 }
 
-// Runner creates runner and sets the passed dir and envs
+// Runner creates runner and sets the passed dir and envs. Commands of the runner are interrupted when the context
+// of the suite is done
 func (s *Suite) Runner(dir string, env ...string) *Runner {
+	return newRunner(s.Context(), suiteT{s: s}, s.Cleanup, dir, env...)
+}
+
+// SetContext sets the context of the suite, e.g. with the suite timeout
+func (s *Suite) SetContext(ctx context.Context) {
+	s.ctx = ctx
+}
+
+// Context returns the context of the suite
+func (s *Suite) Context() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
+}
+
+// suiteT is the current *testing.T of the suite. It changes when a shared parent suite is cleaned up by another suite
+type suiteT struct {
+	s *Suite
+}
+
+func (t suiteT) Name() string                              { return t.s.T().Name() }
+func (t suiteT) Cleanup(f func())                          { t.s.Cleanup(f) }
+func (t suiteT) Errorf(format string, args ...interface{}) { t.s.T().Errorf(format, args...) }
+func (t suiteT) Fatalf(format string, args ...interface{}) { t.s.T().Fatalf(format, args...) }
+func (t suiteT) FailNow()                                  { t.s.T().FailNow() }
+func (t suiteT) Failed() bool                              { return t.s.T().Failed() }
+func (t suiteT) unwrap() TestingT                          { return t.s.T() }
+func (t suiteT) Logf(format string, args ...interface{}) {
+	t.s.T().Helper()
+	t.s.T().Logf(format, args...)
+}
+
+// helperT is implemented by the TestingT marking its callers as helpers, so the log points at the lines calling them
+type helperT interface {
+	Helper()
+}
+
+// wrapperT is implemented by the TestingT passing the calls to another one
+type wrapperT interface {
+	unwrap() TestingT
+}
+
+// unwrap returns the TestingT the wrappers pass the calls to, so helpers are marked by the calls of its Helper
+func unwrap(t TestingT) TestingT {
+	for {
+		w, ok := t.(wrapperT)
+		if !ok {
+			return t
+		}
+		t = w.unwrap()
+	}
+}
+
+// TestingT is the part of *testing.T used by Runner. It's also implemented by ginkgo.GinkgoT()
+type TestingT interface {
+	Name() string
+	Cleanup(f func())
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+	FailNow()
+	Logf(format string, args ...interface{})
+}
+
+// NewRunner creates runner for the test and sets the passed dir and envs. The runner is closed when the test ends
+func NewRunner(t TestingT, dir string, env ...string) *Runner {
+	return newRunner(context.Background(), t, t.Cleanup, dir, env...)
+}
+
+// NewRunnerContext is like NewRunner, but commands of the runner are interrupted when the context is done
+func NewRunnerContext(ctx context.Context, t TestingT, dir string, env ...string) *Runner {
+	return newRunner(ctx, t, t.Cleanup, dir, env...)
+}
+
+func newRunner(ctx context.Context, t TestingT, cleanup func(f func()), dir string, env ...string) *Runner {
+	once.Do(func() {
+		flag.Parse()
+	})
 	result := &Runner{
-		t: s.T(),
+		t:   t,
+		ctx: ctx,
+		env: append([]string(nil), env...),
 	}
-	if !filepath.IsAbs(dir) {
-		dir = filepath.Join(findRoot(), dir)
+	dir, err := resolveDir(dir)
+	if err != nil {
+		t.Fatalf("can't use runner dir: %v", err)
+		return result
 	}
-	b, err := bash.New(bash.WithDir(dir), bash.WithEnv(env))
+	result.dir = dir
+	result.logger = logger.WithField("runner", runnerName(dir))
+	if *dryRunFlag {
+		return result
+	}
+	c, err := newCassette(t, dir)
 	if err != nil {
-		s.FailNowf("can't initialize bash", "%v", err)
+		t.Fatalf("can't initialize cassette: %v", err)
+	}
+	result.cassette = c
+	if c != nil && c.replay {
+		return result
+	}
+	b, err := result.newBash(dir)
+	if err != nil {
+		t.Fatalf("can't initialize bash: %v", err)
 	}
 	result.bash = b
 
-	s.T().Cleanup(func() {
+	cleanup(func() {
 		result.bash.Close()
 	})
-	result.logger = &logrus.Logger{
-		Out:   os.Stderr,
-		Level: logrus.DebugLevel,
-		Formatter: &logrus.TextFormatter{
-			DisableQuote: true,
-		},
+	cleanup(result.collectArtifactsIfFailed)
+	if c != nil {
+		cleanup(func() {
+			if err := c.save(); err != nil {
+				t.Errorf("can't save cassette: %v", err)
+			}
+		})
 	}
-	once.Do(func() {
-		flag.Parse()
-	})
 	return result
 }
 
+// resolveDir returns the absolute dir of the runner. A relative dir is resolved against the module root, so it doesn't
+// depend on the package of the test. The dir is created if the gotestmd.create-dirs flag is set, unless the commands run
+// on a remote host having its own checkout
+func resolveDir(dir string) (string, error) {
+	if !filepath.IsAbs(dir) {
+		root := findRoot()
+		if root == "" {
+			return "", errors.Errorf("%v is relative, but there is no go.mod in the working dir or its parents", dir)
+		}
+		dir = filepath.Join(root, dir)
+	}
+	if *sshHostFlag != "" {
+		return dir, nil
+	}
+	info, err := os.Stat(dir)
+	switch {
+	case os.IsNotExist(err) && *createDirsFlag && !*dryRunFlag:
+		return dir, errors.Wrapf(os.MkdirAll(dir, 0o750), "can't create %v", dir)
+	case os.IsNotExist(err) && *createDirsFlag:
+		return dir, nil
+	case os.IsNotExist(err):
+		return "", errors.Errorf("%v doesn't exist, set the gotestmd.create-dirs flag to create it", dir)
+	case err != nil:
+		return "", errors.Wrapf(err, "can't check %v", dir)
+	case !info.IsDir():
+		return "", errors.Errorf("%v isn't a dir", dir)
+	}
+	return dir, nil
+}
+
+// runnerName returns the dir relative to the module root prefixing the log entries of the runner in the dir
+func runnerName(dir string) string {
+	if rel, err := filepath.Rel(findRoot(), dir); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(rel)
+	}
+	return dir
+}
+
 func findRoot() string {
 	wd, err := os.Getwd()
 	if err != nil {
@@ -81,55 +234,377 @@ func findRoot() string {
 			logrus.Fatal(err.Error())
 		}
 		p := filepath.Clean(filepath.Join(currDir, "go.mod"))
-		if _, err := os.Open(p); err == nil {
+		if _, err := os.Stat(p); err == nil {
 			return currDir
 		}
+		if filepath.Dir(currDir) == currDir {
+			break
+		}
 		currDir = filepath.Dir(currDir)
 	}
 	return ""
 }
 
-// Runner is shell runner.
+// timedOutExitCode is the exit code of the commands interrupted by the attempt timeout, like the one of timeout utility
+const timedOutExitCode = 124
+
+// Runner is shell runner. Runners of parallel tests are independent: each of them has its own shell, dir and env.
+// The commands of a runner used by several goroutines run one by one
 type Runner struct {
-	t      *testing.T
-	logger *logrus.Logger
-	bash   *bash.Bash
+	// mu serializes the commands and the changes of the runner
+	mu     sync.Mutex
+	t      TestingT
+	ctx    context.Context
+	env    []string
+	dir    string
+	logger *logrus.Entry
+	// bash is nil in the dry run mode set by the gotestmd.dry-run flag and when the commands are replayed
+	bash *bash.Bash
+	// cassette records or replays the commands if the gotestmd.record or gotestmd.replay flag is set
+	cassette *cassette
+	// failed are the commands failed by RunSoft
+	failed []string
+	// vars are the variables set by Setenv and Unsetenv in the order of the calls, so they survive restarts
+	vars []envVar
+	// streaming is set while a command runs with its output streamed into the test log
+	streaming atomic.Bool
+	// last is the result of the last command
+	last result
+	// transcript are the last attempts of the commands, artifacts are the commands registered by Artifact and
+	// collected is set when they are collected for a failure
+	transcript []string
+	artifacts  []artifact
+	collected  bool
+	// image is the image of the container running the commands
+	image string
+}
+
+// result is the result of a command run by the runner
+type result struct {
+	stdout   string
+	stderr   string
+	exitCode int
+	duration time.Duration
+}
+
+// newBash starts the shell of the runner streaming the output of the commands into logLine. The shell runs in the
+// container of the image set by UseImage or on the remote host set by the gotestmd.ssh-host flag. The shell is bash
+// unless the gotestmd.shell flag is set
+func (r *Runner) newBash(dir string) (*bash.Bash, error) {
+	options := []bash.Option{
+		bash.WithDir(dir),
+		bash.WithEnv(r.env),
+		bash.WithShell(*shellFlag),
+		bash.WithOutputLimit(*outputLimitFlag),
+		bash.WithStdoutLines(r.logStdout),
+		bash.WithStderrLines(r.logStderr),
+	}
+	if r.image != "" {
+		options = append(options, bash.WithContainer(container(r.image, dir)))
+	} else if ssh := remoteHost(dir); ssh != nil {
+		options = append(options, bash.WithSSH(ssh))
+	}
+	return bash.New(options...)
+}
+
+// logLine logs the line of the output of the running command into the test log, unless the output is buffered with
+// the gotestmd.buffer flag
+func (r *Runner) logLine(stream, line string) {
+	t := unwrap(r.t)
+	if h, ok := t.(helperT); ok {
+		h.Helper()
+	}
+	if r.streaming.Load() {
+		t.Logf("%v: %v", stream, line)
+	}
+}
+
+func (r *Runner) logStdout(line string) {
+	if h, ok := unwrap(r.t).(helperT); ok {
+		h.Helper()
+	}
+	r.logLine("stdout", line)
+}
+
+func (r *Runner) logStderr(line string) {
+	if h, ok := unwrap(r.t).(helperT); ok {
+		h.Helper()
+	}
+	r.logLine("stderr", line)
+}
+
+// envVar is a variable set or unset by the runner
+type envVar struct {
+	key   string
+	value string
+	unset bool
+}
+
+// command returns the shell command applying the variable
+func (v envVar) command() string {
+	if v.unset {
+		return "unset " + v.key
+	}
+	return "export " + v.key + "=" + bash.Quote(v.value)
 }
 
 // Dir returns the directory where current runner instance is located
 func (r *Runner) Dir() string {
-	return r.bash.Dir()
+	return r.dir
+}
+
+// Stdout returns the stdout of the last attempt of the last command without the trailing new line
+func (r *Runner) Stdout() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last.stdout
+}
+
+// Stderr returns the stderr of the last attempt of the last command without the trailing new line
+func (r *Runner) Stderr() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last.stderr
+}
+
+// ExitCode returns the exit code of the last attempt of the last command. It's timedOutExitCode, 124, if the attempt
+// didn't finish in the attempt timeout
+func (r *Runner) ExitCode() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last.exitCode
+}
+
+// Duration returns the time the last command ran for including its retries, e.g. to check a timing budget
+func (r *Runner) Duration() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last.duration
+}
+
+// Setenv sets the env variable for the next commands of the runner. The variable is scoped to the runner, i.e. to the
+// suite or the test that created it, and survives the restarts of the shell after timeouts
+func (r *Runner) Setenv(key, value string) {
+	r.setenv(envVar{key: key, value: value})
+}
+
+// Unsetenv removes the env variable for the next commands of the runner
+func (r *Runner) Unsetenv(key string) {
+	r.setenv(envVar{key: key, unset: true})
+}
+
+func (r *Runner) setenv(v envVar) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logger.WithField(r.t.Name(), "env").Info(v.key)
+	r.vars = append(r.vars, v)
+	if r.bash != nil {
+		r.apply(v)
+	}
+}
+
+// apply runs the command applying the variable in the shell
+func (r *Runner) apply(v envVar) {
+	_, stderr, exitCode, err := r.bash.RunContext(r.ctx, v.command())
+	if err != nil {
+		r.t.Fatalf("can't set env variable %v: %v", v.key, err)
+		return
+	}
+	if exitCode != 0 {
+		r.t.Fatalf("can't set env variable %v: %v", v.key, strings.TrimSpace(stderr))
+	}
 }
 
 // Run runs cmd, logs stdin, stdout, stderr
 // Tries to run cmd several times, until it succeeds or timeout passes.
 //
-// Fails the test if the command can't be run successfully.
+// Fails the test if the command can't be run successfully or the context of the runner is done.
 func (r *Runner) Run(cmd string) {
-	timeoutCh := time.After(*timeoutFlag)
+	r.RunRetry(cmd)
+}
+
+// RunWithTimeout runs cmd like Run, but an attempt of the command that doesn't finish in the timeout is interrupted
+// and retried like a failed one. Zero timeout doesn't limit the attempts
+func (r *Runner) RunWithTimeout(cmd string, timeout time.Duration) {
+	r.RunRetry(cmd, WithAttemptTimeout(timeout))
+}
+
+// RunRetry runs cmd like Run with the retries set by the options, e.g. for eventually consistent checks:
+//
+//	r.RunRetry("kubectl get pods", shell.WithRetryTimeout(5*time.Minute), shell.WithOutputMatching("Running"))
+func (r *Runner) RunRetry(cmd string, options ...RetryOption) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	o := newRetryOptions(options...)
+	exitCode, failure := r.run(cmd, o)
+	if failure == "" {
+		return
+	}
+	r.collectArtifacts()
+	r.pause(cmd)
+	if o.expected == "" && exitCode != 0 {
+		require.Equal(r.t, 0, exitCode)
+	}
+	r.t.Fatalf("%v: %v", failure, cmd)
+}
+
+// RunSoft runs cmd like Run, but if the command doesn't succeed until timeout, the test is marked as failed and continues.
+// Returns false if the command failed. Failed commands are listed by Report
+func (r *Runner) RunSoft(cmd string) bool {
+	return r.RunSoftRetry(cmd)
+}
+
+// RunSoftWithTimeout runs cmd like RunSoft, but an attempt of the command that doesn't finish in the timeout is
+// interrupted and retried like a failed one. Zero timeout doesn't limit the attempts
+func (r *Runner) RunSoftWithTimeout(cmd string, timeout time.Duration) bool {
+	return r.RunSoftRetry(cmd, WithAttemptTimeout(timeout))
+}
+
+// RunSoftRetry runs cmd like RunSoft with the retries set by the options
+func (r *Runner) RunSoftRetry(cmd string, options ...RetryOption) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, failure := r.run(cmd, newRetryOptions(options...))
+	if failure == "" {
+		return true
+	}
+	r.collectArtifacts()
+	r.pause(cmd)
+	r.t.Errorf("%v: %v", failure, cmd)
+	r.failed = append(r.failed, cmd)
+	return false
+}
+
+// Report fails the test with the list of the commands failed by RunSoft
+func (r *Runner) Report() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.failed) == 0 {
+		return
+	}
+	r.t.Errorf("%v of the steps failed:\n%v", len(r.failed), strings.Join(r.failed, "\n"))
+}
+
+// run runs cmd until it exits with the expected code and output or the retry timeout passes and returns the last exit code
+// with the failure message, which is empty if the command succeeded.
+// An attempt running longer than the attempt timeout is interrupted with the shell, so the shell is restarted and
+// the attempt fails with timedOutExitCode
+func (r *Runner) run(cmd string, o *retryOptions) (exitCode int, failure string) {
+	if *dryRunFlag {
+		r.logDryRun(cmd)
+		r.last = result{}
+		return 0, ""
+	}
+	if r.cassette != nil {
+		if err := r.cassette.begin(cmd); err != nil {
+			r.t.Fatalf("can't replay command: %v", err)
+			return 0, "can't replay command"
+		}
+	}
+	start := time.Now()
+	deadline := start.Add(o.timeout)
+	interval := o.interval
+	attempts := 0
+	// the failure is set before the test is stopped by Fatalf, so the event of the command is logged with it
+	defer func() {
+		r.logEvent(cmd, start, attempts, failure)
+	}()
 	for {
 		r.logger.WithField(r.t.Name(), "stdin").Info(cmd)
-		stdout, stderr, exitCode, err := r.bash.Run(cmd)
-		if err != nil {
+		ctx, cancel := r.ctx, context.CancelFunc(func() {})
+		if o.attemptTimeout > 0 {
+			ctx, cancel = context.WithTimeout(r.ctx, o.attemptTimeout)
+		}
+		attemptStart := time.Now()
+		r.streaming.Store(!*bufferFlag)
+		stdout, stderr, exitCode, err := r.runAttempt(ctx, cmd)
+		attempts++
+		r.streaming.Store(false)
+		cancel()
+		attempt := result{stdout: stdout, stderr: stderr, exitCode: exitCode, duration: time.Since(attemptStart)}
+		if err == context.DeadlineExceeded {
+			attempt.exitCode = timedOutExitCode
+		}
+		r.record(cmd, attempt)
+		r.last = attempt
+		r.last.duration = time.Since(start)
+		if err != nil && r.ctx.Err() != nil {
+			r.logger.WithField("cmd", cmd).Errorf("command was interrupted: %v", err)
+			r.collectArtifacts()
+			failure = "command was interrupted"
+			r.t.Fatalf("command was interrupted: %v", err)
+			return exitCode, failure
+		}
+		timedOut := err == context.DeadlineExceeded
+		if timedOut {
+			r.logger.WithField("cmd", cmd).Errorf("command didn't finish in %v, the shell is restarted with its initial dir and env", o.attemptTimeout)
+			r.restart()
+			exitCode = timedOutExitCode
+		}
+		if err != nil && !timedOut && r.bash == nil {
+			failure = "can't replay command"
+			r.t.Fatalf("can't replay command: %v", err)
+			return exitCode, failure
+		}
+		if err != nil && !timedOut {
 			r.logger.Fatalf("can't run command: %v", err)
 			r.t.FailNow()
 		}
-		if stdout != "" {
+		if stdout != "" && *bufferFlag {
 			r.logger.WithField(r.t.Name(), "stdout").Info(stdout)
 		}
-		if stderr != "" {
+		if stderr != "" && *bufferFlag {
 			r.logger.WithField(r.t.Name(), "stderr").Info(stderr)
 		}
-		if exitCode == 0 {
-			return
+		if exitCode != 0 && !timedOut {
+			r.logger.WithField(r.t.Name(), "exitCode").Info(exitCode)
 		}
-		r.logger.WithField(r.t.Name(), "exitCode").Info(exitCode)
-		select {
-		case <-timeoutCh:
+		if !timedOut && o.exitCode(exitCode) {
+			if o.match == nil || o.match(stdout) {
+				return exitCode, ""
+			}
+			r.logger.WithField("cmd", cmd).Info("output doesn't match")
+		}
+		if !time.Now().Before(deadline) || r.cassette.replayed() {
 			r.logger.WithField("cmd", cmd).Error("command didn't succeed until timeout")
-			require.Equal(r.t, 0, exitCode)
-		default:
-			time.Sleep(time.Millisecond * 100)
+			if timedOut {
+				return exitCode, "command failed with exit code " + strconv.Itoa(timedOutExitCode)
+			}
+			if msg := o.exitCodeError(exitCode); msg != "" {
+				return exitCode, msg
+			}
+			return exitCode, "command output doesn't match"
+		}
+		wait := interval
+		if r.bash == nil {
+			// replayed attempts aren't delayed
+			wait = 0
 		}
+		select {
+		case <-r.ctx.Done():
+			r.logger.WithField("cmd", cmd).Errorf("command didn't succeed until the context is done: %v", r.ctx.Err())
+			r.collectArtifacts()
+			failure = "command didn't succeed until the context is done"
+			r.t.Fatalf("command didn't succeed until the context is done: %v", r.ctx.Err())
+			return exitCode, failure
+		case <-time.After(wait):
+		}
+		interval = o.nextInterval(interval)
+	}
+}
+
+// restart replaces the killed shell with a new one, so the next commands and the cleanups still run
+func (r *Runner) restart() {
+	if r.bash == nil {
+		return
+	}
+	r.bash.Close()
+	b, err := r.newBash(r.dir)
+	if err != nil {
+		r.t.Fatalf("can't initialize bash: %v", err)
+	}
+	r.bash = b
+	for _, v := range r.vars {
+		r.apply(v)
 	}
 }
@@ -19,9 +19,18 @@
 package shell_test
 
 import (
+	"encoding/json"
+	"flag"
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
@@ -69,3 +78,621 @@ fi`)
 	require.NoError(t, err)
 	require.Equal(t, "1\n11\n111\n", string(bytes))
 }
+
+type parentSuite struct {
+	shell.Suite
+	dir   string
+	state string
+}
+
+func (s *parentSuite) State() interface{} {
+	return s.state
+}
+
+func (s *parentSuite) SetState(state interface{}) {
+	s.state = state.(string)
+}
+
+func (s *parentSuite) SetupSuite() {
+	s.state = "set up in " + s.T().Name()
+	r := s.Runner(s.dir)
+	s.Cleanup(func() {
+		r.Run("echo cleanup >> parent.log")
+	})
+	r.Run("echo setup >> parent.log")
+}
+
+func TestShellSetupParentsOnce(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	tempDir := t.TempDir()
+	logFile := filepath.Clean(filepath.Join(tempDir, "parent.log"))
+
+	t.Run("children", func(t *testing.T) {
+		for _, name := range []string{"first", "second"} {
+			child := shell.Suite{}
+			child.SetT(t)
+			t.Run(name, func(t *testing.T) {
+				child.SetupParents(&parentSuite{dir: tempDir})
+			})
+		}
+
+		bytes, err := os.ReadFile(logFile)
+		require.NoError(t, err)
+		require.Equal(t, "setup\n", string(bytes))
+	})
+
+	bytes, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	require.Equal(t, "setup\ncleanup\n", string(bytes))
+}
+
+func TestShellSetupParentsState(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	tempDir := t.TempDir()
+	var parents []*parentSuite
+
+	// setupChild sets up the parent of the child suite of the test and runs the next child while it's in use
+	var setupChild func(t *testing.T, names ...string)
+	setupChild = func(t *testing.T, names ...string) {
+		t.Run(names[0], func(t *testing.T) {
+			child := shell.Suite{}
+			child.SetT(t)
+			parent := &parentSuite{dir: tempDir}
+			parents = append(parents, parent)
+			child.SetupParents(parent)
+			require.Equal(t, "set up in TestShellSetupParentsState/first", parent.state)
+			require.Same(t, t, parent.T())
+			parent.Runner(tempDir).Run("echo " + t.Name() + " >> parent.log")
+			if len(names) > 1 {
+				setupChild(t, names[1:]...)
+			}
+		})
+	}
+	setupChild(t, "first", "second")
+
+	require.Len(t, parents, 2)
+	bytes, err := os.ReadFile(filepath.Clean(filepath.Join(tempDir, "parent.log")))
+	require.NoError(t, err)
+	require.Equal(t, "setup\nTestShellSetupParentsState/first\nTestShellSetupParentsState/first/second\ncleanup\n", string(bytes))
+}
+
+func TestShellSetupSharedParallel(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	tempDir := t.TempDir()
+	logFile := filepath.Clean(filepath.Join(tempDir, "parent.log"))
+
+	setup := func(t shell.TestingT) {
+		r := shell.NewRunner(t, tempDir)
+		t.Cleanup(func() {
+			r.Run("echo cleanup >> parent.log")
+		})
+		r.Run("echo setup >> parent.log")
+	}
+
+	t.Run("children", func(t *testing.T) {
+		shell.SetupShared(t, "TestShellSetupSharedParallel", setup)
+		for _, name := range []string{"first", "second", "third"} {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+				shell.SetupShared(t, "TestShellSetupSharedParallel", setup)
+
+				bytes, err := os.ReadFile(logFile)
+				require.NoError(t, err)
+				require.Equal(t, "setup\n", string(bytes))
+			})
+		}
+	})
+
+	bytes, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	require.Equal(t, "setup\ncleanup\n", string(bytes))
+}
+
+func TestShellSetupSharedFailed(t *testing.T) {
+	var setups int
+	t.Run("children", func(t *testing.T) {
+		// setupShared runs the shared setup in another goroutine and returns the message the test is failed with
+		setupShared := func(setup func(t shell.TestingT)) string {
+			ft := &fatalT{T: t}
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				shell.SetupShared(ft, "TestShellSetupSharedFailed", setup)
+			}()
+			<-done
+			return ft.fatal
+		}
+		require.Equal(t, "broken", setupShared(func(t shell.TestingT) {
+			setups++
+			t.Fatalf("broken")
+		}))
+		require.Equal(t, "shared setup TestShellSetupSharedFailed failed", setupShared(func(shell.TestingT) { setups++ }))
+	})
+	require.Equal(t, 1, setups)
+
+	shell.SetupShared(t, "TestShellSetupSharedFailed", func(shell.TestingT) { setups++ })
+	require.Equal(t, 2, setups)
+}
+
+// recordingT records errors instead of failing the test and the logs
+type recordingT struct {
+	*testing.T
+	errors []string
+	mu     sync.Mutex
+	logs   []string
+}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *recordingT) Logf(format string, args ...interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.logs = append(t.logs, fmt.Sprintf(format, args...))
+}
+
+func TestShellRunSoft(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+	require.NoError(t, flag.Set("gotestmd.t", "100ms"))
+	t.Cleanup(func() { _ = flag.Set("gotestmd.t", time.Minute.String()) })
+
+	tempDir := t.TempDir()
+	rt := &recordingT{T: t}
+	r := shell.NewRunner(rt, tempDir)
+
+	require.False(t, r.RunSoft("(exit 3)"))
+	require.True(t, r.RunSoft("echo second >> soft.log"))
+	require.False(t, r.RunSoft("false"))
+	r.Report()
+
+	bytes, err := os.ReadFile(filepath.Clean(filepath.Join(tempDir, "soft.log")))
+	require.NoError(t, err)
+	require.Equal(t, "second\n", string(bytes))
+	require.Equal(t, []string{
+		"command failed with exit code 3: (exit 3)",
+		"command failed with exit code 1: false",
+		"2 of the steps failed:\n(exit 3)\nfalse",
+	}, rt.errors)
+}
+
+func TestShellRunWithTimeout(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	tempDir := t.TempDir()
+	rt := &recordingT{T: t}
+	r := shell.NewRunner(rt, tempDir, "GREETING=hello")
+
+	r.RunWithTimeout("X=1; echo $GREETING $X >> timeout.log", time.Second)
+	start := time.Now()
+	require.False(t, r.RunSoftRetry("sleep 10", shell.WithAttemptTimeout(100*time.Millisecond), shell.WithRetryTimeout(0)))
+	require.Less(t, int64(time.Since(start)), int64(5*time.Second))
+	r.Run("echo $GREETING $X >> timeout.log")
+
+	bytes, err := os.ReadFile(filepath.Clean(filepath.Join(tempDir, "timeout.log")))
+	require.NoError(t, err)
+	require.Equal(t, "hello 1\nhello\n", string(bytes))
+	require.Equal(t, []string{"command failed with exit code 124: sleep 10"}, rt.errors)
+}
+
+func TestShellRunRetryAfterTimeout(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	tempDir := t.TempDir()
+	rt := &recordingT{T: t}
+	r := shell.NewRunner(rt, tempDir)
+
+	start := time.Now()
+	require.True(t, r.RunSoftRetry("test -f hung || { touch hung; sleep 10; }; echo Running",
+		shell.WithRetryTimeout(5*time.Second),
+		shell.WithAttemptTimeout(100*time.Millisecond),
+		shell.WithOutputMatching("Running"),
+	))
+	require.Less(t, int64(time.Since(start)), int64(5*time.Second))
+	require.Equal(t, 0, r.ExitCode())
+
+	require.False(t, r.RunSoftRetry("sleep 10", shell.WithRetryTimeout(200*time.Millisecond), shell.WithAttemptTimeout(50*time.Millisecond)))
+	require.Equal(t, 124, r.ExitCode())
+	require.Equal(t, []string{"command failed with exit code 124: sleep 10"}, rt.errors)
+}
+
+func TestShellRunRetry(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	tempDir := t.TempDir()
+	rt := &recordingT{T: t}
+	r := shell.NewRunner(rt, tempDir)
+
+	r.RunRetry("echo try >> retry.log && wc -l < retry.log",
+		shell.WithInterval(10*time.Millisecond),
+		shell.WithBackoff(2, 40*time.Millisecond),
+		shell.WithOutputMatching(`^\s*3$`),
+	)
+	bytes, err := os.ReadFile(filepath.Clean(filepath.Join(tempDir, "retry.log")))
+	require.NoError(t, err)
+	require.Equal(t, "try\ntry\ntry\n", string(bytes))
+
+	require.False(t, r.RunSoftRetry("echo Pending", shell.WithRetryTimeout(50*time.Millisecond), shell.WithOutputMatching("Running")))
+	require.True(t, r.RunSoftRetry("echo Running", shell.WithOutput(func(stdout string) bool {
+		return stdout == "Running"
+	})))
+	require.Equal(t, []string{"command output doesn't match: echo Pending"}, rt.errors)
+}
+
+func TestShellRunExpectedExitCode(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+	require.NoError(t, flag.Set("gotestmd.t", "100ms"))
+	t.Cleanup(func() { _ = flag.Set("gotestmd.t", time.Minute.String()) })
+
+	rt := &recordingT{T: t}
+	r := shell.NewRunner(rt, t.TempDir())
+
+	r.RunRetry("(exit 3)", shell.WithExpectedExitCode(3))
+	r.RunRetry("echo denied && false", shell.WithExpectedFailure(), shell.WithOutputMatching("denied"))
+	require.False(t, r.RunSoftRetry("(exit 2)", shell.WithExpectedExitCode(3)))
+	require.False(t, r.RunSoftRetry("true", shell.WithExpectedFailure()))
+	require.Equal(t, []string{
+		"command exited with code 2, expected 3: (exit 2)",
+		"command exited with code 0, expected non-zero: true",
+	}, rt.errors)
+}
+
+func TestShellSetenv(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	tempDir := t.TempDir()
+	rt := &recordingT{T: t}
+	r := shell.NewRunner(rt, tempDir)
+
+	r.Setenv("GREETING", "it's a 'quoted' $HOME")
+	r.Setenv("NAMESPACE", "ns-1")
+	r.Unsetenv("NAMESPACE")
+	r.Run(`echo "$GREETING ${NAMESPACE:-unset}" >> env.log`)
+	require.False(t, r.RunSoftRetry("sleep 10", shell.WithAttemptTimeout(100*time.Millisecond), shell.WithRetryTimeout(0)))
+	r.Run(`echo "$GREETING ${NAMESPACE:-unset}" >> env.log`)
+
+	other := shell.NewRunner(t, tempDir)
+	other.Run(`echo "${GREETING:-unset}" >> env.log`)
+
+	bytes, err := os.ReadFile(filepath.Clean(filepath.Join(tempDir, "env.log")))
+	require.NoError(t, err)
+	require.Equal(t, "it's a 'quoted' $HOME unset\nit's a 'quoted' $HOME unset\nunset\n", string(bytes))
+	require.Equal(t, []string{"command failed with exit code 124: sleep 10"}, rt.errors)
+}
+
+func TestShellStreamOutput(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	rt := &recordingT{T: t}
+	r := shell.NewRunner(rt, t.TempDir())
+
+	r.Run("echo pulling; echo layer 1/2; echo warning >&2")
+	require.ElementsMatch(t, []string{"stdout: pulling", "stdout: layer 1/2", "stderr: warning"}, rt.logs)
+
+	require.NoError(t, flag.Set("gotestmd.buffer", "true"))
+	t.Cleanup(func() { _ = flag.Set("gotestmd.buffer", "false") })
+	r.Run("echo buffered")
+	require.Len(t, rt.logs, 3)
+}
+
+func TestShellLastResult(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+	require.NoError(t, flag.Set("gotestmd.t", "100ms"))
+	t.Cleanup(func() { _ = flag.Set("gotestmd.t", time.Minute.String()) })
+
+	rt := &recordingT{T: t}
+	r := shell.NewRunner(rt, t.TempDir())
+
+	r.Run("sleep 0.1; echo out; echo err >&2")
+	require.Equal(t, "out", r.Stdout())
+	require.Equal(t, "err", r.Stderr())
+	require.Equal(t, 0, r.ExitCode())
+	require.GreaterOrEqual(t, int64(r.Duration()), int64(100*time.Millisecond))
+
+	require.False(t, r.RunSoft("echo denied; (exit 3)"))
+	require.Equal(t, "denied", r.Stdout())
+	require.Empty(t, r.Stderr())
+	require.Equal(t, 3, r.ExitCode())
+
+	require.False(t, r.RunSoftWithTimeout("echo slow; sleep 10", 100*time.Millisecond))
+	require.Empty(t, r.Stdout())
+	require.Equal(t, 124, r.ExitCode())
+}
+
+func TestShellArtifacts(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+	require.NoError(t, flag.Set("gotestmd.t", "100ms"))
+	t.Cleanup(func() { _ = flag.Set("gotestmd.t", time.Minute.String()) })
+	artifactsDir := t.TempDir()
+	require.NoError(t, flag.Set("gotestmd.artifacts-dir", artifactsDir))
+	t.Cleanup(func() { _ = flag.Set("gotestmd.artifacts-dir", "") })
+
+	rt := &recordingT{T: t}
+	dir := filepath.Join(t.TempDir(), "leaf")
+	require.NoError(t, os.Mkdir(dir, 0o750))
+	r := shell.NewRunner(rt, dir)
+	r.Setenv("NAMESPACE", "ns-1")
+	r.Artifact("files", "ls")
+
+	r.Run("touch pod.yaml")
+	require.False(t, r.RunSoft("echo oops; (exit 2)"))
+	require.False(t, r.RunSoft("false"))
+
+	collected := filepath.Join(artifactsDir, t.Name(), "leaf")
+	transcript, err := os.ReadFile(filepath.Clean(filepath.Join(collected, "transcript.log")))
+	require.NoError(t, err)
+	require.Contains(t, string(transcript), "$ touch pod.yaml\n# exit code 0 after ")
+	require.Contains(t, string(transcript), "$ echo oops; (exit 2)\noops\n# exit code 2 after ")
+	require.NotContains(t, string(transcript), "$ false")
+
+	env, err := os.ReadFile(filepath.Clean(filepath.Join(collected, "env.log")))
+	require.NoError(t, err)
+	require.Contains(t, string(env), "NAMESPACE=ns-1\n")
+
+	files, err := os.ReadFile(filepath.Clean(filepath.Join(collected, "files.log")))
+	require.NoError(t, err)
+	require.Equal(t, "pod.yaml\n", string(files))
+	require.Contains(t, rt.logs, "artifacts are collected into "+collected)
+}
+
+func TestShellSSH(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	// ssh is replaced by a script running bash locally
+	bin, remoteRoot := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "ssh"), []byte("#!/bin/bash\nexec bash\n"), 0o700)) // #nosec
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	require.NoError(t, os.MkdirAll(filepath.Join(remoteRoot, "examples", "basic"), 0o750))
+	require.NoError(t, flag.Set("gotestmd.ssh-host", "node-1"))
+	require.NoError(t, flag.Set("gotestmd.ssh-dir", remoteRoot))
+	t.Cleanup(func() {
+		_ = flag.Set("gotestmd.ssh-host", "")
+		_ = flag.Set("gotestmd.ssh-dir", "")
+	})
+
+	r := shell.NewRunner(t, "examples/basic", "GREETING=hello")
+	r.Run("pwd; echo $GREETING")
+	require.Equal(t, filepath.Join(remoteRoot, "examples", "basic")+"\nhello", r.Stdout())
+}
+
+func TestShellUseImage(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	// docker is replaced by a script running bash locally and recording its arguments
+	bin := t.TempDir()
+	argsFile := filepath.Join(bin, "args")
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "docker"), []byte("#!/bin/bash\necho \"$@\" > "+argsFile+"\nexec bash\n"), 0o700)) // #nosec
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	r := shell.NewRunner(t, "pkg/suites/shell")
+	r.Setenv("GREETING", "hello")
+	r.UseImage("golang:1.20")
+	r.Run("pwd; echo $GREETING")
+	require.Equal(t, r.Dir()+"\nhello", r.Stdout())
+
+	args, err := os.ReadFile(filepath.Clean(argsFile))
+	require.NoError(t, err)
+	root := filepath.Dir(filepath.Dir(filepath.Dir(r.Dir())))
+	require.Contains(t, string(args), " -v "+root+":"+root+" --entrypoint bash golang:1.20\n")
+}
+
+func TestShellDryRun(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	require.NoError(t, flag.Set("gotestmd.dry-run", "true"))
+	t.Cleanup(func() { _ = flag.Set("gotestmd.dry-run", "false") })
+
+	dir := t.TempDir()
+	rt := &recordingT{T: t}
+	r := shell.NewRunner(rt, dir, "GREETING=hello")
+	r.Setenv("NAME", "gotest md")
+	r.Unsetenv("GREETING")
+	r.Run("touch created")
+	r.RunRetry("false", shell.WithExpectedExitCode(1))
+
+	require.Equal(t, []string{
+		"dry run in " + dir + ":\ntouch created\nenv: NAME='gotest md'",
+		"dry run in " + dir + ":\nfalse\nenv: NAME='gotest md'",
+	}, rt.logs)
+	require.NoFileExists(t, filepath.Join(dir, "created"))
+	require.Empty(t, rt.errors)
+}
+
+func TestShellRecordReplay(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	cassettes, dir := t.TempDir(), t.TempDir()
+	require.NoError(t, flag.Set("gotestmd.record", cassettes))
+	t.Run("record", func(t *testing.T) {
+		r := shell.NewRunner(&recordingT{T: t}, dir)
+		r.Run("echo one; touch created")
+		r.RunRetry("test -f retried || { touch retried; (exit 1); }", shell.WithInterval(time.Millisecond))
+		r.RunSoftRetry("sleep 1", shell.WithAttemptTimeout(10*time.Millisecond), shell.WithRetryTimeout(0))
+	})
+	require.NoError(t, flag.Set("gotestmd.record", ""))
+	require.NoError(t, os.Rename(filepath.Join(cassettes, t.Name(), "record"), filepath.Join(cassettes, t.Name(), "replay")))
+	require.NoError(t, os.Remove(filepath.Join(dir, "created")))
+	require.NoError(t, os.Remove(filepath.Join(dir, "retried")))
+
+	require.NoError(t, flag.Set("gotestmd.replay", cassettes))
+	t.Cleanup(func() { _ = flag.Set("gotestmd.replay", "") })
+	t.Run("replay", func(t *testing.T) {
+		rt := &recordingT{T: t}
+		r := shell.NewRunner(rt, dir)
+		r.Run("echo one; touch created")
+		require.Equal(t, "one", r.Stdout())
+		require.Equal(t, []string{"stdout: one"}, rt.logs)
+		r.RunRetry("test -f retried || { touch retried; (exit 1); }", shell.WithInterval(time.Hour))
+		require.Equal(t, 0, r.ExitCode())
+		require.False(t, r.RunSoftWithTimeout("sleep 1", time.Hour))
+		require.Equal(t, 124, r.ExitCode())
+		require.Len(t, rt.errors, 1)
+	})
+	require.NoFileExists(t, filepath.Join(dir, "created"))
+	require.NoFileExists(t, filepath.Join(dir, "retried"))
+}
+
+func TestShellConcurrentRunners(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	for i := 0; i < 4; i++ {
+		i := i
+		t.Run(fmt.Sprint("runner-", i), func(t *testing.T) {
+			t.Parallel()
+			r := shell.NewRunner(t, t.TempDir(), fmt.Sprint("INDEX=", i))
+			r.Setenv("NAME", fmt.Sprint("runner-", i))
+			r.Run("touch $NAME")
+			r.Run("ls; echo $INDEX")
+			require.Equal(t, fmt.Sprintf("runner-%v\n%v", i, i), r.Stdout())
+		})
+	}
+}
+
+func TestShellConcurrentCommands(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	r := shell.NewRunner(t, t.TempDir())
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		expected := fmt.Sprint(i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.RunRetry("echo "+expected, shell.WithRetryTimeout(0), shell.WithOutput(func(stdout string) bool {
+				return stdout == expected
+			}))
+		}()
+	}
+	wg.Wait()
+}
+
+type fatalT struct {
+	*testing.T
+	fatal string
+}
+
+func (t *fatalT) Fatalf(format string, args ...interface{}) {
+	t.fatal = fmt.Sprintf(format, args...)
+	runtime.Goexit()
+}
+
+// newRunnerFatal creates the runner in another goroutine and returns the message it's failed with
+func newRunnerFatal(t *testing.T, dir string) string {
+	ft := &fatalT{T: t}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		shell.NewRunner(ft, dir)
+	}()
+	<-done
+	return ft.fatal
+}
+
+func TestShellRunnerDir(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	r := shell.NewRunner(t, "pkg/suites/shell")
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.Equal(t, wd, r.Dir())
+
+	missing := filepath.Join(t.TempDir(), "missing")
+	require.Equal(t, "can't use runner dir: "+missing+" doesn't exist, set the gotestmd.create-dirs flag to create it", newRunnerFatal(t, missing))
+	require.Equal(t, "can't use runner dir: "+filepath.Join(wd, "suite.go")+" isn't a dir", newRunnerFatal(t, "pkg/suites/shell/suite.go"))
+
+	require.NoError(t, flag.Set("gotestmd.create-dirs", "true"))
+	t.Cleanup(func() { _ = flag.Set("gotestmd.create-dirs", "false") })
+	r = shell.NewRunner(t, missing)
+	r.Run("pwd")
+	require.Equal(t, missing, r.Stdout())
+}
+
+func TestShellEvents(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	events := filepath.Join(t.TempDir(), "events.jsonl")
+	require.NoError(t, flag.Set("gotestmd.events", events))
+	t.Cleanup(func() { _ = flag.Set("gotestmd.events", "") })
+
+	dir := t.TempDir()
+	r := shell.NewRunner(&recordingT{T: t}, dir)
+	r.Run("echo hello")
+	r.RunSoftRetry("echo failed >&2; false", shell.WithRetryTimeout(20*time.Millisecond), shell.WithInterval(time.Millisecond))
+
+	data, err := os.ReadFile(filepath.Clean(events))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var first, second map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+	require.Equal(t, t.Name(), first["test"])
+	require.Equal(t, "echo hello", first["cmd"])
+	require.Equal(t, dir, first["dir"])
+	require.Equal(t, float64(0), first["exitCode"])
+	require.Equal(t, float64(1), first["attempts"])
+	require.Nil(t, first["failure"])
+	require.Equal(t, "sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", first["stdoutSha256"])
+	require.NotEmpty(t, first["start"])
+	require.NotEmpty(t, first["end"])
+
+	require.Equal(t, float64(1), second["exitCode"])
+	require.Greater(t, second["attempts"], float64(1))
+	require.Equal(t, "command failed with exit code 1", second["failure"])
+}
+
+func TestShellRestoreState(t *testing.T) {
+	if nsDir := os.Getenv("GOTESTMD_LEAKING_TEST"); nsDir != "" {
+		// the leaking test run by the outer test in a separate process, as it fails
+		s := shell.Suite{}
+		s.SetT(t)
+		s.SnapshotState(shell.SnapshotEnv(), shell.SnapshotNamespaces("existing", "leaked"))
+		require.NoError(t, os.Setenv("LEAKED", "value"))
+		require.NoError(t, os.WriteFile(filepath.Join(nsDir, "leaked"), nil, 0o600))
+		s.RestoreState()
+		_, ok := os.LookupEnv("LEAKED")
+		require.False(t, ok)
+		return
+	}
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	// kubectl is replaced by a script keeping the namespaces as files
+	bin, nsDir := t.TempDir(), t.TempDir()
+	kubectl := "#!/bin/bash\ndir=" + nsDir + "\nif [ \"$1\" = get ]; then for ns in \"${@:3}\"; do [ -f \"$dir/$ns\" ] && echo namespace/$ns; done; fi\nif [ \"$1\" = delete ]; then for ns in \"${@:3}\"; do rm \"$dir/$ns\"; done; fi\ntrue\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "kubectl"), []byte(kubectl), 0o700)) // #nosec
+	require.NoError(t, os.WriteFile(filepath.Join(nsDir, "existing"), nil, 0o600))
+
+	// #nosec
+	cmd := exec.Command(os.Args[0], "-test.run=^TestShellRestoreState$")
+	cmd.Env = append(os.Environ(), "GOTESTMD_LEAKING_TEST="+nsDir, "PATH="+bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	output, err := cmd.CombinedOutput()
+	require.Error(t, err)
+	require.Contains(t, string(output), "test has changed env variables: LEAKED")
+	require.Contains(t, string(output), "test has left namespaces: leaked")
+	require.NoFileExists(t, filepath.Join(nsDir, "leaked"))
+	require.FileExists(t, filepath.Join(nsDir, "existing"))
+}
+
+func TestShellDebugWithoutTerminal(t *testing.T) {
+	if os.Getenv("GOTESTMD_FAILING_TEST") != "" {
+		// the failing test run by the outer test in a separate process without a terminal
+		r := shell.NewRunner(t, t.TempDir())
+		r.RunSoftRetry("(exit 3)", shell.WithRetryTimeout(0))
+		r.Run("echo continued")
+		return
+	}
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	// #nosec
+	cmd := exec.Command(os.Args[0], "-test.run=^TestShellDebugWithoutTerminal$", "-test.v")
+	cmd.Env = append(os.Environ(), "GOTESTMD_FAILING_TEST=1", "GOTESTMD_DEBUG=true")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	output, err := cmd.CombinedOutput()
+	require.Error(t, err)
+	require.Contains(t, string(output), "can't pause the test on the failed command")
+	require.Contains(t, string(output), "stdout: continued")
+}